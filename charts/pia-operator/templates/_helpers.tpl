@@ -83,6 +83,18 @@ Create the args for the operator container
 {{- if .Values.operator.leaderElection }}
 {{- $args = append $args "--leader-elect" }}
 {{- end }}
+{{- if .Values.operator.sharding }}
+{{- $args = append $args "--sharding" }}
+{{- end }}
+{{- if .Values.operator.roleProvisioning }}
+{{- $args = append $args "--role-provisioning" }}
+{{- end }}
+{{- if .Values.operator.trustPolicy.enabled }}
+{{- $args = append $args "--manage-trust-policy" }}
+{{- if .Values.operator.trustPolicy.accessRoleName }}
+{{- $args = append $args (printf "--trust-policy-access-role=%s" .Values.operator.trustPolicy.accessRoleName) }}
+{{- end }}
+{{- end }}
 {{- if .Values.operator.aws.region }}
 {{- $args = append $args (printf "--aws-region=%s" .Values.operator.aws.region) }}
 {{- end }}
@@ -95,8 +107,38 @@ Create the args for the operator container
 {{- if .Values.operator.healthProbeBindAddress }}
 {{- $args = append $args (printf "--health-probe-bind-address=%s" .Values.operator.healthProbeBindAddress) }}
 {{- end }}
+{{- if .Values.operator.maxConcurrentReconciles }}
+{{- $args = append $args (printf "--max-concurrent-reconciles=%d" (int .Values.operator.maxConcurrentReconciles)) }}
+{{- end }}
+{{- if .Values.operator.shutdownGracePeriod }}
+{{- $args = append $args (printf "--shutdown-grace-period=%s" .Values.operator.shutdownGracePeriod) }}
+{{- end }}
+{{- if hasKey .Values.operator "orphanSweepInterval" }}
+{{- $args = append $args (printf "--orphan-sweep-interval=%v" .Values.operator.orphanSweepInterval) }}
+{{- end }}
+{{- with .Values.operator.namespaceTermination }}
+{{- if .concurrency }}
+{{- $args = append $args (printf "--namespace-termination-concurrency=%d" (int .concurrency)) }}
+{{- end }}
+{{- if .timeout }}
+{{- $args = append $args (printf "--namespace-termination-timeout=%s" .timeout) }}
+{{- end }}
+{{- end }}
+{{- if .Values.operator.accountRegistry.accounts }}
+{{- $args = append $args (printf "--account-registry=%s-accounts" (include "pia-operator.fullname" .)) }}
+{{- else if .Values.operator.accountRegistry.configMap }}
+{{- $args = append $args (printf "--account-registry=%s" .Values.operator.accountRegistry.configMap) }}
+{{- end }}
 {{- if .Values.operator.devMode }}
 {{- $args = append $args "--dev-mode" }}
 {{- end }}
+{{- if .Values.operator.config }}
+{{- $args = append $args "--config=/etc/pia-operator/config.yaml" }}
+{{- end }}
+{{- if .Values.webhook.podMode }}
+{{- $args = append $args (printf "--pod-webhook-mode=%s" .Values.webhook.podMode) }}
+{{- $args = append $args (printf "--webhook-port=%d" (int .Values.webhook.port)) }}
+{{- $args = append $args "--webhook-cert-dir=/tmp/k8s-webhook-server/serving-certs" }}
+{{- end }}
 {{- toYaml $args }}
 {{- end }}
\ No newline at end of file
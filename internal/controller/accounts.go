@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	piaarn "github.com/irenedo/pia-operator/pkg/arn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	// accountAliasRole matches the short form <alias>:role/<name> of a role ARN
+	accountAliasRole = regexp.MustCompile(`^([a-z0-9][a-z0-9.-]*):(role/\S+)$`)
+	accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+)
+
+// isAccountAliasRole reports whether a role is given in the short form of the account registry
+func isAccountAliasRole(role string) bool {
+	return accountAliasRole.MatchString(role)
+}
+
+// accountRegistry maps account aliases to the partition and account ID of the account
+type accountRegistry struct {
+	entries map[string]string
+	// partition is used for entries without a partition, the partition of the cluster
+	partition string
+}
+
+// loadAccountRegistry reads the account registry ConfigMap. Each key is an alias and each value an
+// account ID, optionally prefixed with its partition as in aws-cn:123456789012.
+func (r *ServiceAccountReconciler) loadAccountRegistry(ctx context.Context) (accountRegistry, error) {
+	if r.AccountRegistry.Name == "" {
+		return accountRegistry{}, fmt.Errorf("account aliases require an account registry, none is configured")
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, r.AccountRegistry, cm); err != nil {
+		return accountRegistry{}, fmt.Errorf("failed to get account registry %s: %w", r.AccountRegistry, err)
+	}
+	return accountRegistry{entries: cm.Data, partition: piaarn.PartitionForRegion(r.AWSRegion)}, nil
+}
+
+// expand returns the ARN of a role given as <alias>:role/<name>
+func (a accountRegistry) expand(role string) (string, error) {
+	match := accountAliasRole.FindStringSubmatch(role)
+	if match == nil {
+		return role, nil
+	}
+	entry, ok := a.entries[match[1]]
+	if !ok {
+		return "", fmt.Errorf("account alias %q is not in the account registry", match[1])
+	}
+	partition, accountID, found := strings.Cut(strings.TrimSpace(entry), ":")
+	if !found {
+		partition, accountID = a.partition, partition
+	}
+	if !accountIDPattern.MatchString(accountID) || !piaarn.IsPartition(partition) {
+		return "", fmt.Errorf("account registry entry %q of alias %q is not [partition:]account-id", entry, match[1])
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:%s", partition, accountID, match[2]), nil
+}
+
+// expandAccountAliases expands the roles of the binding given in the short form of the account registry
+func (r *ServiceAccountReconciler) expandAccountAliases(ctx context.Context, binding Binding) (Binding, error) {
+	registry, err := r.loadAccountRegistry(ctx)
+	if err != nil {
+		return binding, err
+	}
+	if binding.RoleArn, err = registry.expand(binding.RoleArn); err != nil {
+		return binding, fmt.Errorf("failed to resolve role of binding %q: %w", binding.Name, err)
+	}
+	if binding.AssumeRoleArn, err = registry.expand(binding.AssumeRoleArn); err != nil {
+		return binding, fmt.Errorf("failed to resolve assume-role of binding %q: %w", binding.Name, err)
+	}
+	return binding, nil
+}
+
+// mayUseAccountAlias reports whether the roles of the binding may be given by account alias once
+// defaults and templates are applied
+func (r *ServiceAccountReconciler) mayUseAccountAlias(binding Binding) bool {
+	roles := []string{binding.RoleArn, binding.AssumeRoleArn}
+	if binding.RoleArn == "" {
+		roles = append(roles, r.RoleDefaults.RoleArn, r.RoleDefaults.AssumeRoleArn)
+	}
+	for _, role := range roles {
+		if isAccountAliasRole(role) || isRoleTemplate(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountRegistryCacheOptions limits the ConfigMaps kept in the cache to the account registry
+func AccountRegistryCacheOptions(opts cache.Options, registry types.NamespacedName) cache.Options {
+	if opts.ByObject == nil {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+	}
+	opts.ByObject[&corev1.ConfigMap{}] = cache.ByObject{
+		Namespaces: map[string]cache.Config{registry.Namespace: {}},
+		Field:      fields.OneTermEqualSelector("metadata.name", registry.Name),
+	}
+	return opts
+}
+
+// isAccountRegistry only lets through events of the account registry ConfigMap
+func (r *ServiceAccountReconciler) isAccountRegistry() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return client.ObjectKeyFromObject(obj) == r.AccountRegistry
+	})
+}
+
+// serviceAccountsUsingAccountAliases maps a change of the account registry to reconcile requests for
+// the ServiceAccounts whose roles are given by account alias, so that they are resolved again
+func (r *ServiceAccountReconciler) serviceAccountsUsingAccountAliases(ctx context.Context, _ client.Object) []reconcile.Request {
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := r.List(ctx, serviceAccounts); err != nil {
+		r.Log.Error(err, "Failed to list ServiceAccounts for account registry change")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range serviceAccounts.Items {
+		sa := &serviceAccounts.Items[i]
+		for _, binding := range parseBindings(sa.Annotations) {
+			if r.mayUseAccountAlias(binding) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(sa)})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// operatorAnnotations returns the annotations the operator writes to report on the ServiceAccount.
+// They are written with server-side apply, so the operator owns them and never overwrites the
+// annotations of other writers such as Helm or Argo CD.
+func operatorAnnotations(annotations map[string]string) map[string]string {
+	owned := make(map[string]string)
+	for key, value := range annotations {
+		if isOperatorAnnotation(key) {
+			owned[key] = value
+		}
+	}
+	return owned
+}
+
+func isOperatorAnnotation(key string) bool {
+	switch key {
+	case PodIdentityAssociationIDAnnotation,
+		PodIdentityAssociationRequestNonceAnnotation,
+		PodIdentityAssociationStatusAnnotation,
+		PodIdentityAssociationReadyAnnotation,
+		PodIdentityAssociationAppliedRolesAnnotation,
+		PodIdentityAssociationManagedRoleAnnotation,
+		PodIdentityAssociationTrustedTargetAnnotation,
+		PodIdentityAssociationTrustStatusAnnotation,
+		PodIdentityAssociationSessionStatusAnnotation,
+		PodIdentityAssociationSessionMessageAnnotation,
+		PodIdentityAssociationRenderedRoleAnnotation,
+		PodIdentityAssociationRenderedAssumeRoleAnnotation,
+		PodIdentityAssociationRoleErrorAnnotation:
+		return true
+	}
+	// Association IDs of named bindings
+	return strings.HasPrefix(key, PodIdentityAssociationIDAnnotation+".")
+}
+
+// applyAnnotations writes the operator's annotations of sa; those deleted from sa are removed
+func (r *ServiceAccountReconciler) applyAnnotations(ctx context.Context, sa *corev1.ServiceAccount) error {
+	return r.K8sClient.ApplyServiceAccountAnnotations(ctx, sa, operatorAnnotations(sa.Annotations))
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultBindingName is the name of the binding configured by the unindexed role annotations
+	DefaultBindingName = "default"
+
+	// PodIdentityAssociationPrimaryBindingAnnotation selects the binding applied to the association
+	PodIdentityAssociationPrimaryBindingAnnotation = "pia-operator.eks.aws.com/primary-binding"
+)
+
+// Binding is one role configuration of a ServiceAccount. The unindexed annotations form the
+// default binding, and annotations suffixed with ".<name>", such as pia-operator.eks.aws.com/role.reader,
+// form the binding with that name.
+type Binding struct {
+	Name           string
+	RoleArn        string
+	AssumeRoleArn  string
+	TaggingEnabled bool
+	// Session options, see session.go
+	SessionPolicy     string
+	SessionTags       string
+	TransitiveTagKeys string
+}
+
+// newBinding reads the binding with the given name and role from the annotations
+func newBinding(annotations map[string]string, name, roleArn string) Binding {
+	return Binding{
+		Name:          name,
+		RoleArn:       roleArn,
+		AssumeRoleArn: annotations[bindingAnnotation(PodIdentityAssociationAssumeRoleAnnotation, name)],
+		// Default tagging to true, only disable if explicitly set to "false"
+		TaggingEnabled:    annotations[bindingAnnotation(PodIdentityAssociationTaggingAnnotation, name)] != "false",
+		SessionPolicy:     annotations[bindingAnnotation(PodIdentityAssociationSessionPolicyAnnotation, name)],
+		SessionTags:       annotations[bindingAnnotation(PodIdentityAssociationSessionTagsAnnotation, name)],
+		TransitiveTagKeys: annotations[bindingAnnotation(PodIdentityAssociationTransitiveTagKeysAnnotation, name)],
+	}
+}
+
+// bindingAnnotation returns the annotation of the binding for the given unindexed annotation
+func bindingAnnotation(annotation, name string) string {
+	if name == DefaultBindingName {
+		return annotation
+	}
+	return annotation + "." + name
+}
+
+// parseBindings returns the bindings declared on the ServiceAccount ordered by name.
+// Bindings are declared by their role annotation; assume-role, tagging and session annotations of a
+// binding without a role and names that are not DNS labels are ignored.
+func parseBindings(annotations map[string]string) []Binding {
+	var bindings []Binding
+	for key, roleArn := range annotations {
+		name, ok := bindingName(key)
+		if !ok {
+			continue
+		}
+		bindings = append(bindings, newBinding(annotations, name, roleArn))
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+	return bindings
+}
+
+// bindingName returns the name of the binding declared by a role annotation
+func bindingName(key string) (string, bool) {
+	if key == PodIdentityAssociationRoleAnnotation {
+		return DefaultBindingName, true
+	}
+	name, ok := strings.CutPrefix(key, PodIdentityAssociationRoleAnnotation+".")
+	if !ok || name == DefaultBindingName || len(validation.IsDNS1123Label(name)) > 0 {
+		return "", false
+	}
+	return name, true
+}
+
+// hasBindings reports whether the annotations declare at least one binding, including the
+// default binding of a provisioned role
+func hasBindings(annotations map[string]string) bool {
+	for key := range annotations {
+		if _, ok := bindingName(key); ok {
+			return true
+		}
+	}
+	return requestsManagedRole(annotations)
+}
+
+// primaryBinding picks the binding applied to the association of the ServiceAccount. EKS allows a
+// single association per ServiceAccount, so only one binding can be in effect at a time. The binding
+// named by the primary-binding annotation wins, then the default binding, then the first by name.
+func primaryBinding(annotations map[string]string, bindings []Binding) (Binding, bool) {
+	if len(bindings) == 0 {
+		return Binding{}, false
+	}
+	for _, preferred := range []string{annotations[PodIdentityAssociationPrimaryBindingAnnotation], DefaultBindingName} {
+		for _, binding := range bindings {
+			if binding.Name == preferred {
+				return binding, true
+			}
+		}
+	}
+	return bindings[0], true
+}
+
+// bindingAnnotationsChanged reports whether any annotation configuring the bindings differs
+func bindingAnnotationsChanged(oldAnnotations, newAnnotations map[string]string) bool {
+	for _, pair := range [][2]map[string]string{{oldAnnotations, newAnnotations}, {newAnnotations, oldAnnotations}} {
+		for key, value := range pair[0] {
+			if isBindingAnnotation(key) {
+				if other, ok := pair[1][key]; !ok || other != value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isBindingAnnotation(key string) bool {
+	switch key {
+	case PodIdentityAssociationPrimaryBindingAnnotation,
+		PodIdentityAssociationManagedRolePolicyAnnotation,
+		PodIdentityAssociationManagedRolePolicyARNsAnnotation:
+		return true
+	}
+	for _, annotation := range []string{
+		PodIdentityAssociationRoleAnnotation,
+		PodIdentityAssociationAssumeRoleAnnotation,
+		PodIdentityAssociationTaggingAnnotation,
+		PodIdentityAssociationSessionPolicyAnnotation,
+		PodIdentityAssociationSessionTagsAnnotation,
+		PodIdentityAssociationTransitiveTagKeysAnnotation,
+	} {
+		if key == annotation || strings.HasPrefix(key, annotation+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// syncBindingAssociationIDs records the association ID on the primary binding and removes it from
+// the others, which have no association while they are not primary
+func syncBindingAssociationIDs(annotations map[string]string, primary Binding, associationID string) {
+	removeBindingAssociationIDs(annotations)
+	if primary.Name != DefaultBindingName {
+		annotations[PodIdentityAssociationIDAnnotation+"."+primary.Name] = associationID
+	}
+}
+
+// removeBindingAssociationIDs removes the association IDs recorded on named bindings
+func removeBindingAssociationIDs(annotations map[string]string) {
+	for key := range annotations {
+		if strings.HasPrefix(key, PodIdentityAssociationIDAnnotation+".") {
+			delete(annotations, key)
+		}
+	}
+}
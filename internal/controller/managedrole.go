@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Annotations asking the operator to provision the IAM role of the default binding
+	PodIdentityAssociationManagedRolePolicyAnnotation     = "pia-operator.eks.aws.com/managed-role-policy"
+	PodIdentityAssociationManagedRolePolicyARNsAnnotation = "pia-operator.eks.aws.com/managed-role-policy-arns"
+	PodIdentityAssociationRoleDeletionPolicyAnnotation    = "pia-operator.eks.aws.com/role-deletion-policy"
+
+	// Annotation recording the ARN of the IAM role provisioned by the operator
+	PodIdentityAssociationManagedRoleAnnotation = "pia-operator.eks.aws.com/managed-role"
+)
+
+// requestsManagedRole reports whether the ServiceAccount asks for a provisioned role. An explicit
+// role annotation takes precedence, so the managed role only stands in for the default binding.
+func requestsManagedRole(annotations map[string]string) bool {
+	if _, ok := annotations[PodIdentityAssociationRoleAnnotation]; ok {
+		return false
+	}
+	_, hasPolicy := annotations[PodIdentityAssociationManagedRolePolicyAnnotation]
+	_, hasPolicyARNs := annotations[PodIdentityAssociationManagedRolePolicyARNsAnnotation]
+	return hasPolicy || hasPolicyARNs
+}
+
+// managedBinding is the default binding using the provisioned role
+func managedBinding(annotations map[string]string, roleArn string) Binding {
+	return newBinding(annotations, DefaultBindingName, roleArn)
+}
+
+// provisionRole creates or updates the role of a ServiceAccount asking for a provisioned role and
+// adds its default binding. The role ARN is recorded on the ServiceAccount; the caller persists it.
+func (r *ServiceAccountReconciler) provisionRole(ctx context.Context, sa *corev1.ServiceAccount, bindings []Binding) ([]Binding, error) {
+	roleArn, err := r.RoleProvisioner.EnsureRole(ctx, sa)
+	if err != nil {
+		return nil, err
+	}
+	sa.Annotations[PodIdentityAssociationManagedRoleAnnotation] = roleArn
+	return append(bindings, managedBinding(sa.Annotations, roleArn)), nil
+}
+
+// ownsManagedRole reports whether the ServiceAccount has, or is about to get, a provisioned role
+func (r *ServiceAccountReconciler) ownsManagedRole(sa *corev1.ServiceAccount) bool {
+	if r.RoleProvisioner == nil {
+		return false
+	}
+	_, provisioned := sa.Annotations[PodIdentityAssociationManagedRoleAnnotation]
+	return provisioned || requestsManagedRole(sa.Annotations)
+}
+
+// releaseUnusedRole releases the provisioned role of a ServiceAccount that switched to an explicit
+// role, once its association no longer references the provisioned role
+func (r *ServiceAccountReconciler) releaseUnusedRole(ctx context.Context, sa *corev1.ServiceAccount) error {
+	if r.RoleProvisioner == nil || requestsManagedRole(sa.Annotations) {
+		return nil
+	}
+	if _, provisioned := sa.Annotations[PodIdentityAssociationManagedRoleAnnotation]; !provisioned {
+		return nil
+	}
+
+	if err := r.RoleProvisioner.ReleaseRole(ctx, sa); err != nil {
+		return err
+	}
+	delete(sa.Annotations, PodIdentityAssociationManagedRoleAnnotation)
+	return r.applyAnnotations(ctx, sa)
+}
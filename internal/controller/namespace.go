@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metric "github.com/irenedo/pia-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// defaultNamespaceTerminationConcurrency is used when NamespaceTerminationConcurrency is not set
+	defaultNamespaceTerminationConcurrency = 10
+
+	// namespaceTerminationRequeueDelay is how long a ServiceAccount waits while another worker cleans up its namespace
+	namespaceTerminationRequeueDelay = 2 * time.Second
+)
+
+// namespaceTerminations tracks the terminating namespaces being cleaned up, so that only one worker
+// handles a namespace at a time, and the ones whose termination is blocked by associations that
+// could not be deleted yet
+type namespaceTerminations struct {
+	mu      sync.Mutex
+	running map[string]bool
+	blocked map[string]bool
+}
+
+// start reports whether the caller may clean up the namespace; finish has to be called once it is done
+func (t *namespaceTerminations) start(namespace string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running[namespace] {
+		return false
+	}
+	if t.running == nil {
+		t.running = make(map[string]bool)
+	}
+	t.running[namespace] = true
+	return true
+}
+
+func (t *namespaceTerminations) finish(namespace string, blocked bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.running, namespace)
+	if t.blocked == nil {
+		t.blocked = make(map[string]bool)
+	}
+	if blocked {
+		t.blocked[namespace] = true
+	} else {
+		delete(t.blocked, namespace)
+	}
+	metric.SetNamespaceTerminationsBlocked(len(t.blocked))
+}
+
+// terminatingNamespace returns the namespace of the ServiceAccount if it is being deleted
+func (r *ServiceAccountReconciler) terminatingNamespace(ctx context.Context, name string) (*corev1.Namespace, bool) {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return nil, false
+	}
+	return ns, ns.DeletionTimestamp != nil
+}
+
+// handleNamespaceTermination deletes the associations of every ServiceAccount of a terminating
+// namespace in one batch, instead of one ServiceAccount per reconcile, and removes their finalizers.
+// Once NamespaceTerminationTimeout has passed since the namespace started terminating, the remaining
+// finalizers are released without deleting the associations, which are then deleted from the
+// annotations the ServiceAccounts had when they were deleted.
+func (r *ServiceAccountReconciler) handleNamespaceTermination(ctx context.Context, ns *corev1.Namespace) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespace", ns.Name)
+
+	if !r.namespaceTerminations.start(ns.Name) {
+		log.V(1).Info("Namespace is being cleaned up by another worker")
+		return ctrl.Result{RequeueAfter: namespaceTerminationRequeueDelay}, nil
+	}
+
+	serviceAccounts, err := r.finalizedServiceAccounts(ctx, ns.Name)
+	if err != nil {
+		r.namespaceTerminations.finish(ns.Name, true)
+		log.Error(err, "Failed to list ServiceAccounts of terminating namespace")
+		return ctrl.Result{}, err
+	}
+
+	var deadline time.Time
+	if r.NamespaceTerminationTimeout > 0 {
+		deadline = ns.DeletionTimestamp.Add(r.NamespaceTerminationTimeout)
+		if !time.Now().Before(deadline) {
+			result, err := r.releaseFinalizers(ctx, ns.Name, serviceAccounts)
+			r.namespaceTerminations.finish(ns.Name, err != nil)
+			return result, err
+		}
+
+		// Calls stuck past the timeout must not keep the namespace from terminating
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	log.Info("Deleting Pod Identity Associations of terminating namespace", "serviceAccounts", len(serviceAccounts))
+	result := r.cleanupServiceAccounts(ctx, serviceAccounts)
+	r.namespaceTerminations.finish(ns.Name, !result.IsZero())
+
+	// Come back no later than the timeout to release the finalizers
+	if !deadline.IsZero() {
+		if untilDeadline := time.Until(deadline); result.RequeueAfter > untilDeadline {
+			result = ctrl.Result{Requeue: true, RequeueAfter: max(untilDeadline, 0)}
+		}
+	}
+	return result, nil
+}
+
+// finalizedServiceAccounts lists the ServiceAccounts of the namespace that still have the operator's finalizer
+func (r *ServiceAccountReconciler) finalizedServiceAccounts(ctx context.Context, namespace string) ([]*corev1.ServiceAccount, error) {
+	list := &corev1.ServiceAccountList{}
+	if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var serviceAccounts []*corev1.ServiceAccount
+	for i := range list.Items {
+		if controllerutil.ContainsFinalizer(&list.Items[i], PodIdentityAssociationFinalizer) {
+			serviceAccounts = append(serviceAccounts, &list.Items[i])
+		}
+	}
+	return serviceAccounts, nil
+}
+
+// cleanupServiceAccounts deletes the associations and removes the finalizers of the ServiceAccounts,
+// at most NamespaceTerminationConcurrency at a time. The result requeues after the shortest delay
+// requested for a ServiceAccount that could not be cleaned up yet.
+func (r *ServiceAccountReconciler) cleanupServiceAccounts(ctx context.Context, serviceAccounts []*corev1.ServiceAccount) ctrl.Result {
+	concurrency := r.NamespaceTerminationConcurrency
+	if concurrency < 1 {
+		concurrency = defaultNamespaceTerminationConcurrency
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		result ctrl.Result
+	)
+	slots := make(chan struct{}, concurrency)
+	for _, sa := range serviceAccounts {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(sa *corev1.ServiceAccount) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			saResult := r.cleanupTerminatingServiceAccount(ctx, sa)
+			if saResult.IsZero() {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			result = earliestRequeue(result, saResult)
+		}(sa)
+	}
+	wg.Wait()
+	return result
+}
+
+func (r *ServiceAccountReconciler) cleanupTerminatingServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) ctrl.Result {
+	// Errors that are not retried are given up on so that they do not block the namespace
+	if err := r.deletePodIdentityAssociation(ctx, sa); err != nil {
+		if result := r.deletionRetry(ctx, sa, err, "delete Pod Identity Association"); !result.IsZero() {
+			return result
+		}
+	}
+
+	if err := r.K8sClient.PatchServiceAccount(ctx, sa, removeFinalizer); err != nil {
+		if result := r.deletionRetry(ctx, sa, err, "remove finalizer"); !result.IsZero() {
+			return result
+		}
+	}
+
+	r.ErrorHandler.ResetRetryCount(ctx, sa)
+	return ctrl.Result{}
+}
+
+// deletionRetry returns when to retry a failed deletion step, or an empty result if the error is not retried
+func (r *ServiceAccountReconciler) deletionRetry(ctx context.Context, sa *corev1.ServiceAccount, err error, operation string) ctrl.Result {
+	result, err := r.ErrorHandler.HandleDeletionError(ctx, sa, err, operation)
+	if err != nil {
+		return ctrl.Result{Requeue: true}
+	}
+	return result
+}
+
+// releaseFinalizers removes the finalizers of the ServiceAccounts of a namespace whose termination timed out.
+// Their annotations are left in place, so that the deletion of each ServiceAccount is recorded as a tombstone
+// and its association deleted. Tombstones are lost when the operator restarts; the orphan sweep deletes the
+// associations left behind then, and nothing does when it is disabled.
+func (r *ServiceAccountReconciler) releaseFinalizers(ctx context.Context, namespace string, serviceAccounts []*corev1.ServiceAccount) (ctrl.Result, error) {
+	if len(serviceAccounts) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	r.Log.Info("Namespace termination timed out, releasing finalizers before the Pod Identity Associations were deleted",
+		"namespace", namespace, "serviceAccounts", len(serviceAccounts), "timeout", r.NamespaceTerminationTimeout,
+		"orphanSweepInterval", r.OrphanSweepInterval)
+	metric.IncNamespaceTerminationTimeouts()
+
+	for _, sa := range serviceAccounts {
+		if err := r.K8sClient.PatchServiceAccount(ctx, sa, removeFinalizer); err != nil {
+			r.Log.Error(err, "Failed to release finalizer", "serviceaccount", sa.Name, "namespace", namespace)
+			return ctrl.Result{}, err
+		}
+		r.ErrorHandler.ResetRetryCount(ctx, sa)
+	}
+	return ctrl.Result{}, nil
+}
+
+// earliestRequeue returns the result that requeues first, where an immediate requeue comes before any delay
+func earliestRequeue(a, b ctrl.Result) ctrl.Result {
+	switch {
+	case a.IsZero():
+		return b
+	case b.IsZero():
+		return a
+	case a.RequeueAfter == 0:
+		return a
+	case b.RequeueAfter == 0:
+		return b
+	case b.RequeueAfter < a.RequeueAfter:
+		return b
+	}
+	return a
+}
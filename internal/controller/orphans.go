@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	awsclient "github.com/irenedo/pia-operator/pkg/awsclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// orphanSweep returns a runnable calling SweepOrphans every OrphanSweepInterval. It only runs on
+// the leader, or on every replica when sharding is enabled, each sweeping the namespaces it owns.
+func (r *ServiceAccountReconciler) orphanSweep(reader client.Reader) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(r.OrphanSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := r.SweepOrphans(ctx, reader); err != nil {
+					r.Log.Error(err, "Failed to sweep Pod Identity Associations of deleted ServiceAccounts")
+				}
+			}
+		}
+	})
+}
+
+// SweepOrphans deletes the associations the operator created for ServiceAccounts that no longer exist.
+// Tombstones only live in memory, so the association of a ServiceAccount deleted while the operator
+// was not running, or whose finalizer was released when its namespace timed out, is only found by
+// listing the associations of the cluster. ServiceAccounts are looked up in the cache, and only
+// associations inside the full scope whose ServiceAccount is missing from it are candidates. A
+// candidate is deleted once its tags show the operator created it for that ServiceAccount and the
+// API server, read through reader right before the delete, reports no ServiceAccount of that name:
+// the cache may lag behind a ServiceAccount recreated since, and a ServiceAccount outside the
+// ServiceAccount selector is not cached. ServiceAccounts with a tombstone are left to their reconcile.
+func (r *ServiceAccountReconciler) SweepOrphans(ctx context.Context, reader client.Reader) error {
+	// The replica owning the namespace now sweeps its associations
+	r.tombstones.prune(func(key types.NamespacedName) bool { return !r.ownsNamespace(key.Namespace) })
+
+	associations, err := r.AWSClient.ListPodIdentityAssociations(ctx)
+	if err != nil {
+		return err
+	}
+
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := r.List(ctx, serviceAccounts); err != nil {
+		return err
+	}
+	live := make(map[types.NamespacedName]bool, len(serviceAccounts.Items))
+	for i := range serviceAccounts.Items {
+		live[client.ObjectKeyFromObject(&serviceAccounts.Items[i])] = true
+	}
+
+	var errs []error
+	for _, association := range associations {
+		key := types.NamespacedName{Namespace: association.Namespace, Name: association.ServiceAccountName}
+		if live[key] || !r.sweepsNamespace(ctx, key.Namespace) {
+			continue
+		}
+		if _, ok := r.tombstones.get(key); ok {
+			continue
+		}
+		if err := r.deleteOrphan(ctx, reader, association); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// sweepsNamespace reports whether orphaned associations of the namespace are this replica's to delete
+func (r *ServiceAccountReconciler) sweepsNamespace(ctx context.Context, namespace string) bool {
+	return r.ownsNamespace(namespace) && r.Scope.ContainsNamespace(ctx, r.Client, namespace)
+}
+
+// deleteOrphan deletes the association if its tags show the operator created it for the ServiceAccount
+// inside the scope, and the ServiceAccount does not exist. The list of associations has no tags, so
+// the association is described by ID first.
+func (r *ServiceAccountReconciler) deleteOrphan(ctx context.Context, reader client.Reader, association *awsclient.PodIdentityAssociation) error {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:        association.ServiceAccountName,
+		Namespace:   association.Namespace,
+		Annotations: map[string]string{PodIdentityAssociationIDAnnotation: association.ID},
+	}}
+
+	described, err := r.AWSClient.GetPodIdentityAssociation(ctx, sa)
+	if err != nil {
+		if stderrors.Is(err, awsclient.ErrAssociationNotFound) {
+			return nil
+		}
+		return err
+	}
+	if described.Tags["managed-by"] != "pia-operator" ||
+		described.Tags["namespace"] != sa.Namespace ||
+		described.Tags["serviceaccount"] != sa.Name ||
+		described.Tags[ServiceAccountSelectorTag] != r.Scope.Tags()[ServiceAccountSelectorTag] {
+		return nil
+	}
+
+	existing := &corev1.ServiceAccount{}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(sa), existing); !errors.IsNotFound(err) {
+		if err == nil && existing.UID != types.UID(described.Tags["serviceaccount-uid"]) {
+			r.Log.V(1).Info("ServiceAccount was recreated, leaving its association to the reconcile",
+				"serviceaccount", sa.Name, "namespace", sa.Namespace, "associationID", association.ID)
+		}
+		return err
+	}
+
+	r.Log.Info("Deleting Pod Identity Association of a ServiceAccount that no longer exists",
+		"serviceaccount", sa.Name, "namespace", sa.Namespace, "associationID", association.ID)
+	return r.AWSClient.DeletePodIdentityAssociation(ctx, sa)
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"strconv"
+
+	piaarn "github.com/irenedo/pia-operator/pkg/arn"
+	awsclient "github.com/irenedo/pia-operator/pkg/awsclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodIdentityAssociationRoleErrorAnnotation explains why the roles of the primary binding were rejected
+const PodIdentityAssociationRoleErrorAnnotation = "pia-operator.eks.aws.com/role-error"
+
+// checkRolePartition checks that the roles of the binding are IAM role ARNs in the partition of the
+// cluster; EKS cannot associate roles of another partition. A rejected binding is marked FAILED and
+// false is returned, the caller persists the annotations.
+func (r *ServiceAccountReconciler) checkRolePartition(sa *corev1.ServiceAccount, binding Binding) bool {
+	partition := piaarn.PartitionForRegion(r.AWSRegion)
+	_, err := piaarn.ValidateRole(binding.RoleArn, partition)
+	if err == nil && binding.AssumeRoleArn != "" {
+		_, err = piaarn.ValidateRole(binding.AssumeRoleArn, partition)
+	}
+	if err == nil {
+		delete(sa.Annotations, PodIdentityAssociationRoleErrorAnnotation)
+		return true
+	}
+
+	r.Log.Info("Invalid role ARN, not writing the Pod Identity Association",
+		"serviceaccount", sa.Name, "namespace", sa.Namespace, "binding", binding.Name, "error", err.Error())
+	sa.Annotations[PodIdentityAssociationRoleErrorAnnotation] = err.Error()
+	sa.Annotations[PodIdentityAssociationStatusAnnotation] = string(awsclient.AssociationStatusFailed)
+	sa.Annotations[PodIdentityAssociationReadyAnnotation] = strconv.FormatBool(false)
+	return false
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/irenedo/pia-operator/pkg/podgate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AssociationPending reports whether the ServiceAccount requests a role whose association is not ready.
+// ServiceAccounts reconciled before readiness was reported only have an association ID and count as ready.
+func AssociationPending(sa *corev1.ServiceAccount) bool {
+	if !hasBindings(sa.Annotations) {
+		return false
+	}
+	return sa.Annotations[PodIdentityAssociationIDAnnotation] == "" ||
+		sa.Annotations[PodIdentityAssociationReadyAnnotation] == "false"
+}
+
+// GatedPodCacheOptions limits the pods kept in the cache to those the pod webhook admitted with the readiness gate
+func GatedPodCacheOptions(opts cache.Options) cache.Options {
+	if opts.ByObject == nil {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+	}
+	opts.ByObject[&corev1.Pod{}] = cache.ByObject{
+		Label: labels.SelectorFromSet(labels.Set{podgate.GatedLabel: "true"}),
+	}
+	return opts
+}
+
+// waitingPod only lets through the events of pods whose readiness gate is still closed
+var waitingPod = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	return ok && podgate.Waiting(pod)
+})
+
+// serviceAccountOfPod maps a gated pod to its ServiceAccount. The webhook can gate a pod after the
+// reconcile that opened the gates of the ServiceAccount when it read the ServiceAccount before its
+// readiness was recorded, and the pod would wait forever without another reconcile.
+func serviceAccountOfPod(_ context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	name := pod.Spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: name}}}
+}
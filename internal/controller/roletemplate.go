@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PodIdentityAssociationAccountIDAnnotation is read from the namespace of the ServiceAccount and
+	// is available to role templates as .AccountID
+	PodIdentityAssociationAccountIDAnnotation = "pia-operator.eks.aws.com/account-id"
+
+	// Annotations recording the roles rendered from templates or operator defaults
+	PodIdentityAssociationRenderedRoleAnnotation       = "pia-operator.eks.aws.com/rendered-role"
+	PodIdentityAssociationRenderedAssumeRoleAnnotation = "pia-operator.eks.aws.com/rendered-assume-role"
+)
+
+// RoleDefaults are the roles of bindings whose role annotation is empty. Like the annotations, they
+// may be role templates.
+type RoleDefaults struct {
+	// RoleArn is the role of the binding
+	RoleArn string
+	// AssumeRoleArn is the target role of the binding, unless the binding has an assume-role annotation
+	AssumeRoleArn string
+}
+
+// roleTemplateData is passed to role templates
+type roleTemplateData struct {
+	ClusterName     string
+	Region          string
+	Namespace       string
+	ServiceAccount  string
+	NamespaceLabels map[string]string
+
+	namespaceAnnotations map[string]string
+}
+
+// AccountID is the account ID annotated on the namespace; rendering fails when it is missing
+func (d roleTemplateData) AccountID() (string, error) {
+	accountID := d.namespaceAnnotations[PodIdentityAssociationAccountIDAnnotation]
+	if accountID == "" {
+		return "", fmt.Errorf("namespace %s has no %s annotation", d.Namespace, PodIdentityAssociationAccountIDAnnotation)
+	}
+	return accountID, nil
+}
+
+// isRoleTemplate reports whether a role value has to be rendered
+func isRoleTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// renderBinding fills in the operator's default roles, renders the role templates of the binding and
+// expands account aliases. The rendered roles are recorded on the ServiceAccount, the caller persists
+// the annotations.
+func (r *ServiceAccountReconciler) renderBinding(ctx context.Context, sa *corev1.ServiceAccount, binding Binding) (Binding, error) {
+	defaulted := binding.RoleArn == ""
+	if defaulted {
+		if r.RoleDefaults.RoleArn == "" {
+			return binding, fmt.Errorf("the role of binding %q is empty and no default role is configured", binding.Name)
+		}
+		binding.RoleArn = r.RoleDefaults.RoleArn
+		if _, ok := sa.Annotations[bindingAnnotation(PodIdentityAssociationAssumeRoleAnnotation, binding.Name)]; !ok {
+			binding.AssumeRoleArn = r.RoleDefaults.AssumeRoleArn
+		}
+	}
+
+	templated := isRoleTemplate(binding.RoleArn) || isRoleTemplate(binding.AssumeRoleArn)
+	if templated {
+		ns := &corev1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: sa.Namespace}, ns); err != nil {
+			return binding, fmt.Errorf("failed to get namespace for role templates: %w", err)
+		}
+		data := roleTemplateData{
+			ClusterName:          r.ClusterName,
+			Region:               r.AWSRegion,
+			Namespace:            sa.Namespace,
+			ServiceAccount:       sa.Name,
+			NamespaceLabels:      ns.Labels,
+			namespaceAnnotations: ns.Annotations,
+		}
+
+		var err error
+		if binding.RoleArn, err = renderRole(binding.RoleArn, data); err != nil {
+			return binding, fmt.Errorf("failed to render role of binding %q: %w", binding.Name, err)
+		}
+		if binding.AssumeRoleArn, err = renderRole(binding.AssumeRoleArn, data); err != nil {
+			return binding, fmt.Errorf("failed to render assume-role of binding %q: %w", binding.Name, err)
+		}
+	}
+
+	aliased := isAccountAliasRole(binding.RoleArn) || isAccountAliasRole(binding.AssumeRoleArn)
+	if aliased {
+		var err error
+		if binding, err = r.expandAccountAliases(ctx, binding); err != nil {
+			return binding, err
+		}
+	}
+
+	if sa.Annotations == nil {
+		sa.Annotations = make(map[string]string)
+	}
+	delete(sa.Annotations, PodIdentityAssociationRenderedRoleAnnotation)
+	delete(sa.Annotations, PodIdentityAssociationRenderedAssumeRoleAnnotation)
+	if defaulted || templated || aliased {
+		sa.Annotations[PodIdentityAssociationRenderedRoleAnnotation] = binding.RoleArn
+		if binding.AssumeRoleArn != "" {
+			sa.Annotations[PodIdentityAssociationRenderedAssumeRoleAnnotation] = binding.AssumeRoleArn
+		}
+	}
+	return binding, nil
+}
+
+// renderRole renders a role template and checks that the result is an ARN
+func renderRole(value string, data roleTemplateData) (string, error) {
+	if !isRoleTemplate(value) {
+		return value, nil
+	}
+	tmpl, err := template.New("role").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	role := strings.TrimSpace(buf.String())
+	if !(strings.HasPrefix(role, "arn:") || isAccountAliasRole(role)) || strings.ContainsAny(role, " \t\n") {
+		return "", fmt.Errorf("rendered role %q is not an ARN", role)
+	}
+	return role, nil
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// rolloutOnChange restarts the workloads using the ServiceAccount when its association now grants
+// different roles than the last time it was ready, and the ServiceAccount opted in with the
+// rollout-on-change annotation. The applied roles are recorded on the ServiceAccount once every
+// workload was restarted, so the restart happens once per change; the caller persists the
+// annotation. A non-zero delay is returned while rate limited restarts are pending. Workloads
+// record the roles they were restarted for, so the retry only restarts the remaining ones.
+func (r *ServiceAccountReconciler) rolloutOnChange(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string) (time.Duration, error) {
+	roles := appliedRoles(roleArn, assumeRoleArn)
+	if sa.Annotations[PodIdentityAssociationAppliedRolesAnnotation] == roles {
+		return 0, nil
+	}
+
+	if sa.Annotations[PodIdentityAssociationRolloutAnnotation] == "true" {
+		if r.Restarter == nil || (r.RolloutEnabled != nil && !r.RolloutEnabled()) {
+			r.Log.Info("Rollout on change requested but workload restarts are not enabled",
+				"serviceaccount", sa.Name, "namespace", sa.Namespace)
+		} else {
+			result, err := r.Restarter.RestartWorkloads(ctx, sa, roles)
+			if err != nil {
+				return 0, err
+			}
+			r.Log.Info("Restarted workloads after Pod Identity Association change",
+				"serviceaccount", sa.Name, "namespace", sa.Namespace, "workloads", result.Restarted)
+			if !result.Done() {
+				r.Log.Info("Workload restarts are rate limited, requeueing",
+					"serviceaccount", sa.Name, "namespace", sa.Namespace, "requeueAfter", result.RequeueAfter)
+				return result.RequeueAfter, nil
+			}
+		}
+	}
+
+	sa.Annotations[PodIdentityAssociationAppliedRolesAnnotation] = roles
+	return 0, nil
+}
+
+// appliedRoles is the value of the applied-roles annotation for the given roles
+func appliedRoles(roleArn, assumeRoleArn string) string {
+	if assumeRoleArn == "" {
+		return roleArn
+	}
+	return roleArn + "," + assumeRoleArn
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceAccountSelectorTag is the association tag identifying the ServiceAccount selector of the scope
+const ServiceAccountSelectorTag = "serviceaccount-selector"
+
+// Scope restricts the ServiceAccounts handled by the reconciler, so that several
+// operator instances can split a cluster between them. Empty fields do not restrict anything.
+type Scope struct {
+	// Namespaces the ServiceAccount must be in
+	Namespaces []string
+	// NamespaceSelector the ServiceAccount's namespace labels must match
+	NamespaceSelector labels.Selector
+	// ServiceAccountSelector the ServiceAccount labels must match
+	ServiceAccountSelector labels.Selector
+}
+
+// NewScope builds a Scope from namespace names and label selector strings
+func NewScope(namespaces []string, namespaceSelector, serviceAccountSelector string) (Scope, error) {
+	scope := Scope{Namespaces: namespaces}
+	if namespaceSelector != "" {
+		selector, err := labels.Parse(namespaceSelector)
+		if err != nil {
+			return Scope{}, err
+		}
+		scope.NamespaceSelector = selector
+	}
+	if serviceAccountSelector != "" {
+		selector, err := labels.Parse(serviceAccountSelector)
+		if err != nil {
+			return Scope{}, err
+		}
+		scope.ServiceAccountSelector = selector
+	}
+	return scope, nil
+}
+
+// CacheOptions limits the manager cache to the scoped namespaces and ServiceAccounts,
+// so that objects outside the scope are never listed or kept in memory.
+func (s Scope) CacheOptions() cache.Options {
+	opts := cache.Options{}
+	if len(s.Namespaces) > 0 {
+		opts.DefaultNamespaces = make(map[string]cache.Config, len(s.Namespaces))
+		for _, ns := range s.Namespaces {
+			opts.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	byObject := map[client.Object]cache.ByObject{}
+	if s.ServiceAccountSelector != nil {
+		byObject[&corev1.ServiceAccount{}] = cache.ByObject{Label: s.ServiceAccountSelector}
+	}
+	if s.NamespaceSelector != nil {
+		byObject[&corev1.Namespace{}] = cache.ByObject{Label: s.NamespaceSelector}
+	}
+	if len(byObject) > 0 {
+		opts.ByObject = byObject
+	}
+	return opts
+}
+
+// Contains reports whether the object is inside the scope.
+// The namespace selector is checked against the namespace read through reader.
+func (s Scope) Contains(ctx context.Context, reader client.Reader, obj client.Object) bool {
+	if s.ServiceAccountSelector != nil && !s.ServiceAccountSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return s.ContainsNamespace(ctx, reader, obj.GetNamespace())
+}
+
+// ContainsNamespace reports whether the namespace is inside the scope
+func (s Scope) ContainsNamespace(ctx context.Context, reader client.Reader, namespace string) bool {
+	if len(s.Namespaces) > 0 && !containsString(s.Namespaces, namespace) {
+		return false
+	}
+	if s.NamespaceSelector != nil {
+		ns := &corev1.Namespace{}
+		if err := reader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			return false
+		}
+		if !s.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tags returns the tags identifying the scope on the associations created inside it. The labels
+// of a deleted ServiceAccount are gone, so the orphan sweep tells from the tag whether its
+// association was created by an instance with the same ServiceAccount selector.
+func (s Scope) Tags() map[string]string {
+	if s.ServiceAccountSelector == nil {
+		return nil
+	}
+	return map[string]string{ServiceAccountSelectorTag: selectorHash(s.ServiceAccountSelector)}
+}
+
+// selectorHash identifies a selector in a tag value, which cannot hold every character of a selector
+func selectorHash(selector labels.Selector) string {
+	sum := sha256.Sum256([]byte(selector.String()))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Predicate filters out events for objects outside the scope
+func (s Scope) Predicate(reader client.Reader) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return s.Contains(context.Background(), reader, obj)
+	})
+}
+
+// namespaceLabelsChanged only lets through namespace events that can move a namespace into the scope
+var namespaceLabelsChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	CreateFunc:  func(e event.CreateEvent) bool { return true },
+	DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+	GenericFunc: func(e event.GenericEvent) bool { return false },
+}
+
+// serviceAccountsInNamespace maps a namespace event to reconcile requests for the annotated
+// ServiceAccounts in that namespace, so they are picked up when the namespace enters the scope.
+func (r *ServiceAccountReconciler) serviceAccountsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := r.List(ctx, serviceAccounts, client.InNamespace(obj.GetName())); err != nil {
+		r.Log.Error(err, "Failed to list ServiceAccounts for namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range serviceAccounts.Items {
+		sa := &serviceAccounts.Items[i]
+		if !hasBindings(sa.Annotations) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(sa)})
+	}
+	return requests
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/irenedo/pia-operator/internal/controller"
+)
+
+var _ = Describe("Scope", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		sa         *corev1.ServiceAccount
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "a"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "b"}}},
+		).Build()
+
+		sa = &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sa",
+				Namespace: "team-a",
+				Labels:    map[string]string{"pia": "enabled"},
+			},
+		}
+	})
+
+	Describe("NewScope", func() {
+		It("should reject invalid selectors", func() {
+			_, err := controller.NewScope(nil, "tenant in (a", "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should leave selectors unset when empty", func() {
+			scope, err := controller.NewScope(nil, "", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scope.NamespaceSelector).To(BeNil())
+			Expect(scope.ServiceAccountSelector).To(BeNil())
+		})
+	})
+
+	Describe("Contains", func() {
+		It("should contain everything when empty", func() {
+			Expect(controller.Scope{}.Contains(ctx, fakeClient, sa)).To(BeTrue())
+		})
+
+		It("should filter by namespace name", func() {
+			scope, err := controller.NewScope([]string{"team-b"}, "", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeFalse())
+
+			sa.Namespace = "team-b"
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeTrue())
+		})
+
+		It("should filter by namespace labels", func() {
+			scope, err := controller.NewScope(nil, "tenant=a", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeTrue())
+
+			sa.Namespace = "team-b"
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeFalse())
+		})
+
+		It("should exclude ServiceAccounts in unknown namespaces when a namespace selector is set", func() {
+			scope, err := controller.NewScope(nil, "tenant=a", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			sa.Namespace = "missing"
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeFalse())
+		})
+
+		It("should filter by ServiceAccount labels", func() {
+			scope, err := controller.NewScope(nil, "", "pia=enabled")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeTrue())
+
+			sa.Labels = nil
+			Expect(scope.Contains(ctx, fakeClient, sa)).To(BeFalse())
+		})
+	})
+
+	Describe("CacheOptions", func() {
+		It("should restrict the cache to the configured namespaces and selectors", func() {
+			scope, err := controller.NewScope([]string{"team-a", "team-b"}, "tenant=a", "pia=enabled")
+			Expect(err).ToNot(HaveOccurred())
+
+			opts := scope.CacheOptions()
+
+			Expect(opts.DefaultNamespaces).To(HaveLen(2))
+			Expect(opts.DefaultNamespaces).To(HaveKey("team-a"))
+			Expect(opts.ByObject).To(HaveLen(2))
+		})
+
+		It("should not restrict the cache when the scope is empty", func() {
+			opts := controller.Scope{}.CacheOptions()
+
+			Expect(opts.DefaultNamespaces).To(BeNil())
+			Expect(opts.ByObject).To(BeNil())
+		})
+	})
+})
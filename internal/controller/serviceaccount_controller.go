@@ -9,16 +9,27 @@
 //   - pia-operator.eks.aws.com/role: Specifies the AWS IAM role ARN to associate.
 //   - pia-operator.eks.aws.com/assume-role: (Optional) Specifies a target role ARN for role assumption.
 //   - pia-operator.eks.aws.com/tagging: (Optional) Boolean to control session tags (default: true).
+//   - pia-operator.eks.aws.com/rollout-on-change: (Optional) Restart the workloads using the ServiceAccount after its roles change.
+//
+// The role, assume-role and tagging annotations can also be suffixed with ".<name>" to declare several named
+// bindings; pia-operator.eks.aws.com/primary-binding selects the one applied to the single association EKS allows.
+// Without a role annotation, pia-operator.eks.aws.com/managed-role-policy and managed-role-policy-arns ask the
+// operator to provision the IAM role of the default binding itself when role provisioning is enabled.
 //
 // When a ServiceAccount is annotated, the controller:
 //   - Adds a finalizer to ensure cleanup on deletion.
 //   - Creates or updates the Pod Identity Association in AWS.
 //   - Stores the association ID in the ServiceAccount's annotations.
+//   - Checks whether the association is usable, records its status and readiness as annotations and requeues while it is pending.
 //
 // On deletion or annotation removal, the controller:
 //   - Deletes the Pod Identity Association in AWS.
 //   - Removes related annotations and the finalizer from the ServiceAccount.
 //
+// A ServiceAccount deleted after its finalizer was removed by hand is still cleaned up, using the
+// annotations it had when it was deleted. Associations whose ServiceAccount was deleted while the
+// operator was not running are deleted by a periodic sweep of the associations it tagged.
+//
 // The controller uses custom error handling and metrics to track reconciliation status and errors.
 // It is designed to be robust against transient errors and supports retry logic via controller-runtime mechanisms.
 //
@@ -27,20 +38,35 @@ package controller
 
 import (
 	"context"
+	stderrors "errors"
+	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	awsclient "github.com/irenedo/pia-operator/pkg/awsclient"
 	errorhandling "github.com/irenedo/pia-operator/pkg/errors"
+	"github.com/irenedo/pia-operator/pkg/iamrole"
 	k8sclient "github.com/irenedo/pia-operator/pkg/k8sclient"
 	metric "github.com/irenedo/pia-operator/pkg/metrics"
+	"github.com/irenedo/pia-operator/pkg/podgate"
+	"github.com/irenedo/pia-operator/pkg/rollout"
+	"github.com/irenedo/pia-operator/pkg/sharding"
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -48,12 +74,28 @@ const (
 	PodIdentityAssociationRoleAnnotation       = "pia-operator.eks.aws.com/role"
 	PodIdentityAssociationAssumeRoleAnnotation = "pia-operator.eks.aws.com/assume-role"
 	PodIdentityAssociationTaggingAnnotation    = "pia-operator.eks.aws.com/tagging"
+	PodIdentityAssociationRolloutAnnotation    = "pia-operator.eks.aws.com/rollout-on-change"
 
 	// Finalizer for cleanup
 	PodIdentityAssociationFinalizer = "pia-operator.eks.aws.com/finalizer"
 
 	// Annotation for storing Pod Identity Association ID
 	PodIdentityAssociationIDAnnotation = "pia-operator.eks.aws.com/association-id"
+
+	// Annotation holding a random value that tells the creates of successive associations of the
+	// ServiceAccount apart, so that an association created after the previous one was deleted does
+	// not reuse its client request token
+	PodIdentityAssociationRequestNonceAnnotation = "pia-operator.eks.aws.com/request-nonce"
+
+	// Annotations reporting whether the association can be used, for CD pipelines to wait on
+	PodIdentityAssociationStatusAnnotation = "pia-operator.eks.aws.com/association-status"
+	PodIdentityAssociationReadyAnnotation  = "pia-operator.eks.aws.com/ready"
+
+	// Annotation recording the roles of the last ready association, to detect role changes
+	PodIdentityAssociationAppliedRolesAnnotation = "pia-operator.eks.aws.com/applied-roles"
+
+	// associationPendingRequeueDelay is how long to wait before checking a pending association again
+	associationPendingRequeueDelay = 10 * time.Second
 )
 
 // ServiceAccountReconciler reconciles a ServiceAccount object
@@ -65,27 +107,84 @@ type ServiceAccountReconciler struct {
 	ClusterName   string
 	AWSClient     awsclient.AWSClient
 	K8sClient     k8sclient.Cli
-	errorHandler  errorhandling.ErrorHandlerInterface
+	// ErrorHandler classifies errors and tracks retries; a default handler is created when nil
+	ErrorHandler errorhandling.ErrorHandlerInterface
+	// MaxConcurrentReconciles is the number of ServiceAccounts reconciled in parallel (default 1)
+	MaxConcurrentReconciles int
+	// RateLimiter delays requeued ServiceAccounts; the controller-runtime default is used when nil
+	RateLimiter ratelimiter.RateLimiter
+	// Scope restricts the ServiceAccounts handled by this reconciler
+	Scope Scope
+	// Restarter restarts workloads of ServiceAccounts annotated with rollout-on-change; nil disables restarts
+	Restarter rollout.Restarter
+	// RolloutEnabled reports whether the WorkloadRollout feature gate currently allows restarts; nil always allows them
+	RolloutEnabled func() bool
+	// PodGate opens the readiness gates added by the pod webhook once the association is ready; nil when unused
+	PodGate podgate.Gate
+	// RoleProvisioner creates the IAM roles of ServiceAccounts with a managed-role-policy annotation; nil disables provisioning
+	RoleProvisioner iamrole.Provisioner
+	// TrustManager makes assume-role target roles trust their base role; nil leaves trust policies alone
+	TrustManager trustpolicy.Manager
+	// Shard limits reconciliation to the namespaces owned by this replica; nil disables sharding
+	Shard sharding.ShardOwner
+	// NamespaceTerminationConcurrency is the number of associations deleted in parallel for a terminating namespace (default 10)
+	NamespaceTerminationConcurrency int
+	// NamespaceTerminationTimeout is how long after a namespace started terminating the finalizers of its
+	// ServiceAccounts are released even if their associations could not be deleted; zero never releases them
+	NamespaceTerminationTimeout time.Duration
+	// RoleDefaults are the roles of bindings with an empty role annotation; empty requires a role on every binding
+	RoleDefaults RoleDefaults
+	// AccountRegistry is the ConfigMap mapping account aliases to account IDs; empty disables account aliases
+	AccountRegistry types.NamespacedName
+	// ShutdownGracePeriod is how long in-flight reconciles keep running after the manager stopped; zero cancels them
+	ShutdownGracePeriod time.Duration
+	// OrphanSweepInterval is how often associations of ServiceAccounts that no longer exist are deleted; zero disables the sweep
+	OrphanSweepInterval time.Duration
+
+	tombstones            tombstones
+	namespaceTerminations namespaceTerminations
+	ownedNamespaces       ownedNamespaces
 }
 
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods/status,verbs=patch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *ServiceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("serviceaccount", req.NamespacedName)
+	ctx, cancel := r.drainContext(ctx)
+	defer cancel()
+
+	// The namespace may have moved to another replica since the request was queued. Its orphan
+	// sweep deletes the association of a ServiceAccount deleted here, so the tombstone is dropped.
+	if !r.ownsNamespace(req.Namespace) {
+		log.V(1).Info("Namespace is owned by another shard, skipping")
+		r.tombstones.remove(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
 
 	// Initialize error handler if not already done
-	if r.errorHandler == nil {
-		r.errorHandler = errorhandling.NewErrorHandler(r.Client, r.Log)
+	if r.ErrorHandler == nil {
+		r.ErrorHandler = errorhandling.NewErrorHandler(r.Client, r.Log)
 	}
 
 	// Fetch the ServiceAccount instance
 	serviceAccount, err := r.K8sClient.GetServiceAccount(ctx, req.Namespace, req.Name)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			// The ServiceAccount was deleted without the finalizer, its association may still exist
+			if tombstone, ok := r.tombstones.get(req.NamespacedName); ok {
+				return r.handleTombstone(ctx, tombstone)
+			}
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected.
 			log.Info("ServiceAccount resource not found. Ignoring since object must be deleted")
@@ -94,6 +193,8 @@ func (r *ServiceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		log.Error(err, "Failed to get ServiceAccount")
 		return ctrl.Result{}, err
 	}
+	// A ServiceAccount recreated with the same name adopts the association
+	r.tombstones.remove(req.NamespacedName)
 
 	// Check if ServiceAccount is being deleted
 	if serviceAccount.DeletionTimestamp != nil {
@@ -101,47 +202,81 @@ func (r *ServiceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	// Check if relevant annotations exist
-	roleArn, hasRoleArn := serviceAccount.Annotations[PodIdentityAssociationRoleAnnotation]
-	assumeRoleArn := serviceAccount.Annotations[PodIdentityAssociationAssumeRoleAnnotation]
-	// Default tagging to true, only disable if explicitly set to "false"
-	taggingEnabled := serviceAccount.Annotations[PodIdentityAssociationTaggingAnnotation] != "false"
+	bindings := parseBindings(serviceAccount.Annotations)
+	provisionRole := r.RoleProvisioner != nil && requestsManagedRole(serviceAccount.Annotations)
 
-	if !hasRoleArn {
+	if len(bindings) == 0 && !provisionRole {
 		// No relevant annotations, ensure any existing association is cleaned up
 		return r.cleanupPodIdentityAssociation(ctx, serviceAccount)
 	}
 
 	// Add finalizer if not present
 	if !controllerutil.ContainsFinalizer(serviceAccount, PodIdentityAssociationFinalizer) {
-		controllerutil.AddFinalizer(serviceAccount, PodIdentityAssociationFinalizer)
-		if err := r.Update(ctx, serviceAccount); err != nil {
+		if err := r.K8sClient.PatchServiceAccount(ctx, serviceAccount, func(sa *corev1.ServiceAccount) {
+			controllerutil.AddFinalizer(sa, PodIdentityAssociationFinalizer)
+		}); err != nil {
 			log.Error(err, "Failed to add finalizer")
 			return ctrl.Result{}, err
 		}
 	}
 
+	if provisionRole {
+		bindings, err = r.provisionRole(ctx, serviceAccount, bindings)
+		if err != nil {
+			return r.ErrorHandler.HandleError(ctx, serviceAccount, err, "provision IAM role")
+		}
+	}
+
+	primary, _ := primaryBinding(serviceAccount.Annotations, bindings)
+	if len(bindings) > 1 {
+		log.V(1).Info("EKS allows one Pod Identity Association per ServiceAccount, only the primary binding is applied",
+			"primaryBinding", primary.Name, "bindings", len(bindings))
+	}
+
+	// Role templates are rendered before any AWS call, a missing namespace label or annotation may be added later
+	primary, err = r.renderBinding(ctx, serviceAccount, primary)
+	if err != nil {
+		return r.ErrorHandler.HandleError(ctx, serviceAccount, err, "render role templates")
+	}
+
 	// Create or update Pod Identity Association
-	return r.reconcilePodIdentityAssociation(ctx, serviceAccount, roleArn, assumeRoleArn, taggingEnabled)
+	return r.reconcilePodIdentityAssociation(ctx, serviceAccount, primary)
 }
 
 // reconcilePodIdentityAssociation creates or updates a Pod Identity Association in AWS EKS
 // for the given ServiceAccount, establishing the connection between the Kubernetes ServiceAccount
-// and the specified IAM role ARN to enable pod-level IAM permissions.
-func (r *ServiceAccountReconciler) reconcilePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool) (ctrl.Result, error) {
-	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace)
+// and the IAM role ARN of the primary binding to enable pod-level IAM permissions.
+func (r *ServiceAccountReconciler) reconcilePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, binding Binding) (ctrl.Result, error) {
+	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "binding", binding.Name)
+	roleArn, assumeRoleArn, session := binding.RoleArn, binding.AssumeRoleArn, binding.session()
+
+	if sa.Annotations == nil {
+		sa.Annotations = make(map[string]string)
+	}
+	if !r.checkRolePartition(sa, binding) || !r.checkSessionOptions(sa, binding) {
+		// Retrying cannot fix the roles or options, the annotation change triggers the next reconcile
+		if err := r.applyAnnotations(ctx, sa); err != nil {
+			return r.ErrorHandler.HandleError(ctx, sa, err, "update ServiceAccount annotation")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.syncTrustPolicy(ctx, sa, binding); err != nil {
+		return r.ErrorHandler.HandleError(ctx, sa, err, "update target role trust policy")
+	}
 
 	exists, err := r.AWSClient.AssociationExists(ctx, sa)
 	if err != nil {
-		return r.errorHandler.HandleError(ctx, sa, err, "check existing Pod Identity Association")
+		return r.ErrorHandler.HandleError(ctx, sa, err, "check existing Pod Identity Association")
 	}
 
 	var associationID string
 	var op string
 	if exists {
-		associationID, err = r.updatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, taggingEnabled, log)
+		associationID, err = r.updatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session, log)
 		op = "update"
 	} else {
-		associationID, err = r.createPodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, taggingEnabled, log)
+		associationID, err = r.createPodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session, log)
 		op = "create"
 	}
 	if err != nil {
@@ -149,31 +284,80 @@ func (r *ServiceAccountReconciler) reconcilePodIdentityAssociation(ctx context.C
 		return ctrl.Result{}, err
 	}
 
-	if associationID != "" {
-		if sa.Annotations == nil {
-			sa.Annotations = make(map[string]string)
+	if associationID == "" {
+		// The write failed with an error that is not retried
+		if err := r.setAssociationStatus(ctx, sa, awsclient.AssociationStatusFailed); err != nil {
+			log.Error(err, "Failed to update ServiceAccount with association status")
+			return r.ErrorHandler.HandleError(ctx, sa, err, "update ServiceAccount annotation")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	association, err := r.AWSClient.CheckAssociationReady(ctx, associationID, roleArn, assumeRoleArn, session)
+	if err != nil {
+		return r.ErrorHandler.HandleError(ctx, sa, err, "check Pod Identity Association readiness")
+	}
+
+	var rolloutPending time.Duration
+	if association.Ready() {
+		rolloutPending, err = r.rolloutOnChange(ctx, sa, roleArn, assumeRoleArn)
+		if err != nil {
+			return r.ErrorHandler.HandleError(ctx, sa, err, "restart workloads")
 		}
-		sa.Annotations[PodIdentityAssociationIDAnnotation] = associationID
-		if err := r.K8sClient.UpdateServiceAccount(ctx, sa); err != nil {
-			log.Error(err, "Failed to update ServiceAccount with association ID annotation")
-			return r.errorHandler.HandleError(ctx, sa, err, "update ServiceAccount annotation")
+	}
+	sa.Annotations[PodIdentityAssociationIDAnnotation] = associationID
+	syncBindingAssociationIDs(sa.Annotations, binding, associationID)
+	if err := r.setAssociationStatus(ctx, sa, awsclient.AssociationStatus(association.Status)); err != nil {
+		log.Error(err, "Failed to update ServiceAccount with association ID annotation")
+		return r.ErrorHandler.HandleError(ctx, sa, err, "update ServiceAccount annotation")
+	}
+
+	if !association.Ready() {
+		log.Info("Pod Identity Association is not ready yet, requeueing", "status", association.Status, "associationID", associationID)
+		return ctrl.Result{RequeueAfter: associationPendingRequeueDelay}, nil
+	}
+
+	if r.PodGate != nil {
+		if err := r.PodGate.OpenGates(ctx, sa); err != nil {
+			return r.ErrorHandler.HandleError(ctx, sa, err, "open pod readiness gates")
 		}
 	}
 
+	if err := r.releaseUnusedRole(ctx, sa); err != nil {
+		return r.ErrorHandler.HandleError(ctx, sa, err, "release IAM role")
+	}
+
 	// Mark success
-	r.errorHandler.MarkSuccess(ctx, sa, "Pod Identity Association ready")
+	r.ErrorHandler.MarkSuccess(ctx, sa, "Pod Identity Association ready")
 
 	metric.SetAssociationsManaged(1)
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: rolloutPending}, nil
+}
+
+// setAssociationStatus records the association status and readiness on the ServiceAccount
+func (r *ServiceAccountReconciler) setAssociationStatus(ctx context.Context, sa *corev1.ServiceAccount, status awsclient.AssociationStatus) error {
+	if sa.Annotations == nil {
+		sa.Annotations = make(map[string]string)
+	}
+	sa.Annotations[PodIdentityAssociationStatusAnnotation] = string(status)
+	sa.Annotations[PodIdentityAssociationReadyAnnotation] = strconv.FormatBool(status == awsclient.AssociationStatusActive)
+	return r.applyAnnotations(ctx, sa)
 }
 
 // updatePodIdentityAssociation updates an existing Pod Identity Association in AWS EKS
 // with new role ARN configuration, ensuring the ServiceAccount maintains proper
 // IAM role binding while preserving the existing association ID.
-func (r *ServiceAccountReconciler) updatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool, log logr.Logger) (string, error) {
-	associationID, err := r.AWSClient.UpdatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+func (r *ServiceAccountReconciler) updatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session awsclient.SessionOptions, log logr.Logger) (string, error) {
+	associationID, err := r.AWSClient.UpdatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session)
 	if err != nil {
-		result, handleErr := r.errorHandler.HandleError(ctx, sa, err, "update Pod Identity Association")
+		if stderrors.Is(err, awsclient.ErrAssociationNotFound) {
+			// The association was deleted outside of the operator since it was looked up
+			log.Info("Pod Identity Association no longer exists, creating it again")
+			delete(sa.Annotations, PodIdentityAssociationIDAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationRequestNonceAnnotation)
+			return r.createPodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session, log)
+		}
+		result, handleErr := r.ErrorHandler.HandleError(ctx, sa, err, "update Pod Identity Association")
 		if handleErr != nil {
 			return "", handleErr
 		}
@@ -187,10 +371,18 @@ func (r *ServiceAccountReconciler) updatePodIdentityAssociation(ctx context.Cont
 
 // createPodIdentityAssociation creates a new Pod Identity Association in AWS EKS
 // linking the ServiceAccount to the specified IAM role ARN.
-func (r *ServiceAccountReconciler) createPodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool, log logr.Logger) (string, error) {
-	associationID, err := r.AWSClient.CreatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+func (r *ServiceAccountReconciler) createPodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session awsclient.SessionOptions, log logr.Logger) (string, error) {
+	// The nonce is recorded before the create, so that a retried create sends the same client request token
+	if sa.Annotations[PodIdentityAssociationRequestNonceAnnotation] == "" {
+		sa.Annotations[PodIdentityAssociationRequestNonceAnnotation] = utilrand.String(16)
+		if err := r.applyAnnotations(ctx, sa); err != nil {
+			return "", err
+		}
+	}
+
+	associationID, err := r.AWSClient.CreatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session)
 	if err != nil {
-		result, handleErr := r.errorHandler.HandleError(ctx, sa, err, "create Pod Identity Association")
+		result, handleErr := r.ErrorHandler.HandleError(ctx, sa, err, "create Pod Identity Association")
 		if handleErr != nil {
 			return "", handleErr
 		}
@@ -217,15 +409,19 @@ func (r *ServiceAccountReconciler) logAssociationSuccess(log logr.Logger, operat
 // removes the finalizer, and updates the ServiceAccount. Errors encountered during these steps are handled and returned appropriately.
 func (r *ServiceAccountReconciler) handleDeletion(ctx context.Context, sa *corev1.ServiceAccount) (ctrl.Result, error) {
 	if controllerutil.ContainsFinalizer(sa, PodIdentityAssociationFinalizer) {
+		// The ServiceAccounts of a terminating namespace are cleaned up together
+		if ns, terminating := r.terminatingNamespace(ctx, sa.Namespace); terminating {
+			return r.handleNamespaceTermination(ctx, ns)
+		}
+
 		// Delete Pod Identity Association
 		if err := r.deletePodIdentityAssociation(ctx, sa); err != nil {
-			return r.errorHandler.HandleDeletionError(ctx, sa, err, "delete Pod Identity Association")
+			return r.ErrorHandler.HandleDeletionError(ctx, sa, err, "delete Pod Identity Association")
 		}
 
 		// Remove finalizer
-		controllerutil.RemoveFinalizer(sa, PodIdentityAssociationFinalizer)
-		if err := r.Update(ctx, sa); err != nil {
-			return r.errorHandler.HandleDeletionError(ctx, sa, err, "remove finalizer")
+		if err := r.K8sClient.PatchServiceAccount(ctx, sa, removeFinalizer); err != nil {
+			return r.ErrorHandler.HandleDeletionError(ctx, sa, err, "remove finalizer")
 		}
 	}
 
@@ -237,16 +433,30 @@ func (r *ServiceAccountReconciler) handleDeletion(ctx context.Context, sa *corev
 func (r *ServiceAccountReconciler) deletePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount) error {
 	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace)
 
-	if err := r.AWSClient.DeletePodIdentityAssociation(ctx, sa); err != nil {
+	if err := r.releasePodIdentityAssociation(ctx, sa); err != nil {
 		return err
 	}
 
 	// Remove Pod Identity Association annotations
 	if sa.Annotations != nil {
-		delete(sa.Annotations, PodIdentityAssociationAssumeRoleAnnotation)
-		delete(sa.Annotations, PodIdentityAssociationIDAnnotation)
-		delete(sa.Annotations, PodIdentityAssociationTaggingAnnotation)
-		if err := r.K8sClient.UpdateServiceAccount(ctx, sa); err != nil {
+		if err := r.K8sClient.PatchServiceAccount(ctx, sa, func(sa *corev1.ServiceAccount) {
+			delete(sa.Annotations, PodIdentityAssociationAssumeRoleAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationIDAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationRequestNonceAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationTaggingAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationStatusAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationReadyAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationAppliedRolesAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationManagedRoleAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationTrustedTargetAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationTrustStatusAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationSessionStatusAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationSessionMessageAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationRenderedRoleAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationRenderedAssumeRoleAnnotation)
+			delete(sa.Annotations, PodIdentityAssociationRoleErrorAnnotation)
+			removeBindingAssociationIDs(sa.Annotations)
+		}); err != nil {
 			log.Error(err, "Failed to remove Pod Identity Association annotations from ServiceAccount")
 			return err
 		}
@@ -256,6 +466,23 @@ func (r *ServiceAccountReconciler) deletePodIdentityAssociation(ctx context.Cont
 	return nil
 }
 
+// releasePodIdentityAssociation deletes the association of the ServiceAccount and everything granted
+// for it in AWS, without writing the ServiceAccount
+func (r *ServiceAccountReconciler) releasePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount) error {
+	if err := r.AWSClient.DeletePodIdentityAssociation(ctx, sa); err != nil {
+		return err
+	}
+
+	if err := r.revokeTrust(ctx, sa); err != nil {
+		return err
+	}
+
+	if r.ownsManagedRole(sa) {
+		return r.RoleProvisioner.ReleaseRole(ctx, sa)
+	}
+	return nil
+}
+
 // cleanupPodIdentityAssociation removes the Pod Identity Association and finalizer
 // when the ServiceAccount no longer has the required annotations.
 func (r *ServiceAccountReconciler) cleanupPodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount) (ctrl.Result, error) {
@@ -266,43 +493,89 @@ func (r *ServiceAccountReconciler) cleanupPodIdentityAssociation(ctx context.Con
 
 	// Delete the association
 	if err := r.deletePodIdentityAssociation(ctx, sa); err != nil {
-		return r.errorHandler.HandleDeletionError(ctx, sa, err, "cleanup Pod Identity Association")
+		return r.ErrorHandler.HandleDeletionError(ctx, sa, err, "cleanup Pod Identity Association")
 	}
 
 	// Remove finalizer
-	controllerutil.RemoveFinalizer(sa, PodIdentityAssociationFinalizer)
-	if err := r.K8sClient.UpdateServiceAccount(ctx, sa); err != nil {
-		return r.errorHandler.HandleDeletionError(ctx, sa, err, "remove finalizer during cleanup")
+	if err := r.K8sClient.PatchServiceAccount(ctx, sa, removeFinalizer); err != nil {
+		return r.ErrorHandler.HandleDeletionError(ctx, sa, err, "remove finalizer during cleanup")
 	}
 
 	// Reset retry count on successful cleanup
-	r.errorHandler.ResetRetryCount(ctx, sa)
+	r.ErrorHandler.ResetRetryCount(ctx, sa)
 	return ctrl.Result{}, nil
 }
 
+func removeFinalizer(sa *corev1.ServiceAccount) {
+	controllerutil.RemoveFinalizer(sa, PodIdentityAssociationFinalizer)
+}
+
+// UpdatePredicate lets through the updates that change the binding annotations of a ServiceAccount
+// or start its deletion. Deleting a ServiceAccount with the finalizer only sets its deletion
+// timestamp, so the association would otherwise be left until the next resync.
+func (r *ServiceAccountReconciler) UpdatePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			deletionStarted := e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+			return deletionStarted || bindingAnnotationsChanged(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+		},
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.ServiceAccount{}).
-		WithEventFilter(predicate.Funcs{
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				oldRoleArn := e.ObjectOld.GetAnnotations()[PodIdentityAssociationRoleAnnotation]
-				newRoleArn := e.ObjectNew.GetAnnotations()[PodIdentityAssociationRoleAnnotation]
-				oldAssumeRoleArn := e.ObjectOld.GetAnnotations()[PodIdentityAssociationAssumeRoleAnnotation]
-				newAssumeRoleArn := e.ObjectNew.GetAnnotations()[PodIdentityAssociationAssumeRoleAnnotation]
-				oldTagging := e.ObjectOld.GetAnnotations()[PodIdentityAssociationTaggingAnnotation]
-				newTagging := e.ObjectNew.GetAnnotations()[PodIdentityAssociationTaggingAnnotation]
-				return oldRoleArn != newRoleArn || oldAssumeRoleArn != newAssumeRoleArn || oldTagging != newTagging
-			},
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.ServiceAccount{}, builder.WithPredicates(r.UpdatePredicate(), predicate.Funcs{
 			CreateFunc: func(e event.CreateEvent) bool {
 				annotations := e.Object.GetAnnotations()
-				_, hasRoleArn := annotations[PodIdentityAssociationRoleAnnotation]
 				_, hasAssumeRoleArn := annotations[PodIdentityAssociationAssumeRoleAnnotation]
 				hasFinalizer := controllerutil.ContainsFinalizer(e.Object, PodIdentityAssociationFinalizer)
-				return hasRoleArn || hasAssumeRoleArn || hasFinalizer
+				return hasBindings(annotations) || hasAssumeRoleArn || hasFinalizer
 			},
-			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			// Deletions are filtered by the tombstone predicate
+			DeleteFunc:  func(e event.DeleteEvent) bool { return true },
 			GenericFunc: func(e event.GenericEvent) bool { return false },
-		}).
-		Complete(r)
+		}, r.Scope.Predicate(mgr.GetClient()), r.shardPredicate(), r.TombstonePredicate())).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		})
+
+	if r.Scope.NamespaceSelector != nil {
+		// Reconcile the ServiceAccounts of namespaces whose labels move them into the scope
+		b = b.Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.serviceAccountsInNamespace),
+			builder.WithPredicates(namespaceLabelsChanged))
+	}
+
+	if r.AccountRegistry.Name != "" {
+		// Resolve the account aliases again when the registry changes
+		b = b.Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.serviceAccountsUsingAccountAliases),
+			builder.WithPredicates(r.isAccountRegistry()))
+	}
+
+	if r.PodGate != nil {
+		// Open the gates of pods that were gated after the association became ready
+		b = b.Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(serviceAccountOfPod),
+			builder.WithPredicates(waitingPod))
+	}
+
+	if r.OrphanSweepInterval > 0 {
+		// Delete the associations left behind by ServiceAccounts deleted while the operator could not clean up
+		if err := mgr.Add(r.orphanSweep(mgr.GetAPIReader())); err != nil {
+			return err
+		}
+	}
+
+	if r.Shard != nil {
+		// Reconcile the ServiceAccounts of namespaces that moved to this replica
+		events := make(chan event.GenericEvent)
+		r.Shard.OnRebalance(r.rebalance(events))
+		b = b.WatchesRawSource(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{}).
+			Watches(&corev1.Namespace{}, r.countOwnedNamespaces())
+	}
+
+	return b.Complete(r)
 }
@@ -3,6 +3,10 @@ package controller_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -15,11 +19,20 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/irenedo/pia-operator/internal/controller"
+	"github.com/irenedo/pia-operator/pkg/awsclient"
 	awsclientmocks "github.com/irenedo/pia-operator/pkg/awsclient/mocks"
+	iamrolemocks "github.com/irenedo/pia-operator/pkg/iamrole/mocks"
 	k8sclientmocks "github.com/irenedo/pia-operator/pkg/k8sclient/mocks"
+	podgatemocks "github.com/irenedo/pia-operator/pkg/podgate/mocks"
+	"github.com/irenedo/pia-operator/pkg/rollout"
+	rolloutmocks "github.com/irenedo/pia-operator/pkg/rollout/mocks"
+	shardingmocks "github.com/irenedo/pia-operator/pkg/sharding/mocks"
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
+	trustmocks "github.com/irenedo/pia-operator/pkg/trustpolicy/mocks"
 )
 
 var _ = Describe("ServiceAccountReconciler", func() {
@@ -54,6 +67,18 @@ var _ = Describe("ServiceAccountReconciler", func() {
 		}
 	})
 
+	// expectPatches applies the changes of the reconciler's merge patches to sa
+	expectPatches := func(sa *corev1.ServiceAccount) *mock.Call {
+		return mockK8sClient.On("PatchServiceAccount", ctx, sa, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(2).(func(*corev1.ServiceAccount))(args.Get(1).(*corev1.ServiceAccount))
+		}).Return(nil)
+	}
+
+	// expectApply accepts the operator's annotations applied to sa
+	expectApply := func(sa *corev1.ServiceAccount) *mock.Call {
+		return mockK8sClient.On("ApplyServiceAccountAnnotations", ctx, sa, mock.Anything).Return(nil)
+	}
+
 	Describe("Reconcile", func() {
 		Context("when ServiceAccount does not exist", func() {
 			It("should return no error and empty result", func() {
@@ -76,6 +101,27 @@ var _ = Describe("ServiceAccountReconciler", func() {
 			})
 		})
 
+		Context("when the namespace is owned by another shard", func() {
+			It("should skip the ServiceAccount without calling any client", func() {
+				mockShard := shardingmocks.NewMockShardOwner(GinkgoT())
+				mockShard.On("Owns", "default").Return(false)
+				reconciler.Shard = mockShard
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "test-sa",
+						Namespace: "default",
+					},
+				}
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				mockK8sClient.AssertNotCalled(GinkgoT(), "GetServiceAccount", mock.Anything, mock.Anything, mock.Anything)
+			})
+		})
+
 		Context("when ServiceAccount exists but has no relevant annotations", func() {
 			It("should return no error and empty result", func() {
 				sa := &corev1.ServiceAccount{
@@ -128,15 +174,27 @@ var _ = Describe("ServiceAccountReconciler", func() {
 
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
 				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
-				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", true).Return("assoc-123", nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
 
-				// Mock the K8sClient update for the association ID
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				// Mock the finalizer patch and the annotations applied for the association ID
+				expectPatches(sa)
+				var applied map[string]string
+				expectApply(sa).Run(func(args mock.Arguments) {
+					applied = args.Get(2).(map[string]string)
+				})
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Finalizers).To(ContainElement(controller.PodIdentityAssociationFinalizer))
+				// Only the operator's own annotations are applied, the role annotation belongs to the ServiceAccount's author
+				Expect(applied).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation, "assoc-123"))
+				Expect(applied).To(HaveKeyWithValue(controller.PodIdentityAssociationReadyAnnotation, "true"))
+				Expect(applied).ToNot(HaveKey(controller.PodIdentityAssociationRoleAnnotation))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationStatusAnnotation, "ACTIVE"))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationReadyAnnotation, "true"))
 
 				mockK8sClient.AssertExpectations(GinkgoT())
 				mockAWSClient.AssertExpectations(GinkgoT())
@@ -165,10 +223,12 @@ var _ = Describe("ServiceAccountReconciler", func() {
 
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
 				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
-				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", true).Return("assoc-456", nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
 
-				// Mock the K8sClient update for the association ID
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				// Mock the finalizer patch and the annotations applied for the association ID
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
@@ -179,18 +239,19 @@ var _ = Describe("ServiceAccountReconciler", func() {
 				mockAWSClient.AssertExpectations(GinkgoT())
 			})
 
-			It("should handle AWS errors", func() {
+			It("should restart workloads when the roles change and rollout on change is enabled", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRoleAnnotation:         "arn:aws:iam::123456789012:role/updated-role",
+							controller.PodIdentityAssociationRolloutAnnotation:      "true",
+							controller.PodIdentityAssociationAppliedRolesAnnotation: "arn:aws:iam::123456789012:role/test-role",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -200,34 +261,39 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
-				expectedError := errors.New("AWS error")
+				mockRestarter := rolloutmocks.NewMockRestarter(GinkgoT())
+				reconciler.Restarter = mockRestarter
 
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, expectedError)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				mockRestarter.On("RestartWorkloads", ctx, sa, "arn:aws:iam::123456789012:role/updated-role").Return(rollout.Result{Restarted: []string{"Deployment/web"}}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
-				// The reconciler should return the result from error handler, which will handle the error
-				// and return a requeue result with no error to the controller-runtime
-				Expect(err).ToNot(HaveOccurred())                     // Error handler handles the error internally
-				Expect(result.RequeueAfter).To(BeNumerically(">", 0)) // Should requeue after some time
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationAppliedRolesAnnotation, "arn:aws:iam::123456789012:role/updated-role"))
 
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				mockRestarter.AssertExpectations(GinkgoT())
 			})
 
-			It("should handle tagging enabled by default (no annotation)", func() {
+			It("should record the roles without restarting workloads when the WorkloadRollout feature gate is disabled", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRoleAnnotation:         "arn:aws:iam::123456789012:role/updated-role",
+							controller.PodIdentityAssociationRolloutAnnotation:      "true",
+							controller.PodIdentityAssociationAppliedRolesAnnotation: "arn:aws:iam::123456789012:role/test-role",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -237,34 +303,37 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockRestarter := rolloutmocks.NewMockRestarter(GinkgoT())
+				reconciler.Restarter = mockRestarter
+				reconciler.RolloutEnabled = func() bool { return false }
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
-				// Expect tagging to be enabled (true) by default
-				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", true).Return("assoc-123", nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				result, err := reconciler.Reconcile(ctx, req)
+				_, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
-
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationAppliedRolesAnnotation, "arn:aws:iam::123456789012:role/updated-role"))
+				mockRestarter.AssertNotCalled(GinkgoT(), "RestartWorkloads", mock.Anything, mock.Anything, mock.Anything)
 			})
 
-			It("should handle tagging explicitly enabled (annotation set to true)", func() {
+			It("should requeue without recording the roles while workload restarts are rate limited", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
-							controller.PodIdentityAssociationTaggingAnnotation: "true",
+							controller.PodIdentityAssociationRoleAnnotation:         "arn:aws:iam::123456789012:role/updated-role",
+							controller.PodIdentityAssociationRolloutAnnotation:      "true",
+							controller.PodIdentityAssociationAppliedRolesAnnotation: "arn:aws:iam::123456789012:role/test-role",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -274,34 +343,39 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockRestarter := rolloutmocks.NewMockRestarter(GinkgoT())
+				reconciler.Restarter = mockRestarter
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
-				// Expect tagging to be enabled (true)
-				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", true).Return("assoc-123", nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				mockRestarter.On("RestartWorkloads", ctx, sa, "arn:aws:iam::123456789012:role/updated-role").Return(rollout.Result{Restarted: []string{"Deployment/web"}, RequeueAfter: time.Minute}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationAppliedRolesAnnotation, "arn:aws:iam::123456789012:role/test-role"))
 
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				mockRestarter.AssertExpectations(GinkgoT())
 			})
 
-			It("should handle tagging disabled (annotation set to false)", func() {
+			It("should not restart workloads when the roles did not change", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
-							controller.PodIdentityAssociationTaggingAnnotation: "false",
+							controller.PodIdentityAssociationRoleAnnotation:         "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRolloutAnnotation:      "true",
+							controller.PodIdentityAssociationAppliedRolesAnnotation: "arn:aws:iam::123456789012:role/test-role",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -311,34 +385,33 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockRestarter := rolloutmocks.NewMockRestarter(GinkgoT())
+				reconciler.Restarter = mockRestarter
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
-				// Expect tagging to be disabled (false)
-				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", false).Return("assoc-123", nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				result, err := reconciler.Reconcile(ctx, req)
+				_, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
-
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				mockRestarter.AssertNotCalled(GinkgoT(), "RestartWorkloads", mock.Anything, mock.Anything, mock.Anything)
 			})
 
-			It("should handle tagging enabled with non-false value", func() {
+			It("should open pod readiness gates once the association is ready", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
-							controller.PodIdentityAssociationTaggingAnnotation: "anything-not-false",
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -348,34 +421,39 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockPodGate := podgatemocks.NewMockGate(GinkgoT())
+				reconciler.PodGate = mockPodGate
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
-				// Expect tagging to be enabled (true) for any value that's not "false"
-				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", true).Return("assoc-123", nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+				mockPodGate.On("OpenGates", ctx, sa).Return(nil)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(ctrl.Result{}))
-
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				mockPodGate.AssertExpectations(GinkgoT())
 			})
 
-			It("should handle update with tagging disabled", func() {
+			It("should apply the binding selected as primary", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/updated-role",
-							controller.PodIdentityAssociationTaggingAnnotation: "false",
+							controller.PodIdentityAssociationRoleAnnotation:                "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRoleAnnotation + ".writer":    "arn:aws:iam::123456789012:role/writer-role",
+							controller.PodIdentityAssociationTaggingAnnotation + ".writer": "false",
+							controller.PodIdentityAssociationPrimaryBindingAnnotation:      "writer",
+							controller.PodIdentityAssociationIDAnnotation + ".reader":      "assoc-stale",
 						},
 					},
 				}
 
-				// Create the ServiceAccount in the fake client
 				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
 				req := ctrl.Request{
@@ -387,35 +465,38 @@ var _ = Describe("ServiceAccountReconciler", func() {
 
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
 				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
-				// Expect tagging to be disabled (false) in the update call
-				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", false).Return("assoc-456", nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/writer-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/writer-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation, "assoc-456"))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation+".writer", "assoc-456"))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationIDAnnotation + ".reader"))
 
-				mockK8sClient.AssertExpectations(GinkgoT())
 				mockAWSClient.AssertExpectations(GinkgoT())
 			})
-		})
 
-		Context("when ServiceAccount is being deleted", func() {
-			It("should handle deletion with finalizer", func() {
-				deletionTime := metav1.Now()
+			It("should apply the first named binding when there is no primary or default binding", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRoleAnnotation + ".writer":       "arn:aws:iam::123456789012:role/writer-role",
+							controller.PodIdentityAssociationRoleAnnotation + ".reader":       "arn:aws:iam::123456789012:role/reader-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation + ".reader": "arn:aws:iam::987654321098:role/target-role",
+							controller.PodIdentityAssociationPrimaryBindingAnnotation:         "missing",
 						},
-						Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
-						DeletionTimestamp: &deletionTime,
 					},
 				}
 
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
 				req := ctrl.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      sa.Name,
@@ -424,28 +505,33 @@ var _ = Describe("ServiceAccountReconciler", func() {
 				}
 
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/reader-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-789", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-789", "arn:aws:iam::123456789012:role/reader-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-789", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				result, err := reconciler.Reconcile(ctx, req)
+				_, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation+".reader", "assoc-789"))
 
-				mockK8sClient.AssertExpectations(GinkgoT())
 				mockAWSClient.AssertExpectations(GinkgoT())
 			})
 
-			It("should handle deletion without finalizer", func() {
-				deletionTime := metav1.Now()
+			It("should provision the role of ServiceAccounts with a managed role policy", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:              "test-sa",
-						Namespace:         "default",
-						DeletionTimestamp: &deletionTime,
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationManagedRolePolicyARNsAnnotation: "arn:aws:iam::aws:policy/ReadOnlyAccess",
+						},
 					},
 				}
 
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
 				req := ctrl.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      sa.Name,
@@ -453,33 +539,41 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockProvisioner := iamrolemocks.NewMockProvisioner(GinkgoT())
+				reconciler.RoleProvisioner = mockProvisioner
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockProvisioner.On("EnsureRole", ctx, sa).Return("arn:aws:iam::123456789012:role/test-cluster-default-test-sa", nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-cluster-default-test-sa", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-cluster-default-test-sa", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationManagedRoleAnnotation, "arn:aws:iam::123456789012:role/test-cluster-default-test-sa"))
 
-				mockK8sClient.AssertExpectations(GinkgoT())
+				mockProvisioner.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
 			})
 
-			It("should remove tagging annotation during deletion cleanup", func() {
-				deletionTime := metav1.Now()
+			It("should release the provisioned role after switching to an explicit role", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
-							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::123456789012:role/assume-role",
-							controller.PodIdentityAssociationTaggingAnnotation:    "false",
-							controller.PodIdentityAssociationIDAnnotation:         "assoc-123",
+							controller.PodIdentityAssociationRoleAnnotation:        "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationManagedRoleAnnotation: "arn:aws:iam::123456789012:role/test-cluster-default-test-sa",
 						},
-						Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
-						DeletionTimestamp: &deletionTime,
 					},
 				}
 
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
 				req := ctrl.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      sa.Name,
@@ -487,39 +581,94 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockProvisioner := iamrolemocks.NewMockProvisioner(GinkgoT())
+				reconciler.RoleProvisioner = mockProvisioner
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+				mockProvisioner.On("ReleaseRole", ctx, sa).Return(nil)
+
+				_, err := reconciler.Reconcile(ctx, req)
 
-				// Mock the K8sClient update call and verify all annotations are removed
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Run(func(args mock.Arguments) {
-					updatedSA, ok := args.Get(1).(*corev1.ServiceAccount)
-					Expect(ok).To(BeTrue())
-					// Verify that all PIA-related annotations are removed
-					Expect(updatedSA.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationAssumeRoleAnnotation))
-					Expect(updatedSA.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationIDAnnotation))
-					Expect(updatedSA.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTaggingAnnotation))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationManagedRoleAnnotation))
+				mockProvisioner.AssertNotCalled(GinkgoT(), "EnsureRole", mock.Anything, mock.Anything)
+			})
+
+			It("should make the target role trust the base role", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:          "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation:    "arn:aws:iam::987654321098:role/target-role",
+							controller.PodIdentityAssociationTrustedTargetAnnotation: "arn:aws:iam::987654321098:role/old-target-role",
+						},
+					},
+				}
+
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockTrustManager := trustmocks.NewMockManager(GinkgoT())
+				reconciler.TrustManager = mockTrustManager
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				// Another ServiceAccount still trusted by the previous target role
+				Expect(fakeClient.Create(ctx, &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:          "arn:aws:iam::123456789012:role/other-role",
+							controller.PodIdentityAssociationTaggingAnnotation:       "false",
+							controller.PodIdentityAssociationTrustedTargetAnnotation: "arn:aws:iam::987654321098:role/old-target-role",
+						},
+					},
+				})).To(Succeed())
+				mockTrustManager.On("Revoke", ctx, sa, "arn:aws:iam::987654321098:role/old-target-role", []trustpolicy.Subject{
+					{Namespace: "default", Name: "other-sa", RoleArn: "arn:aws:iam::123456789012:role/other-role"},
 				}).Return(nil)
+				mockTrustManager.On("Grant", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", true).Return(nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				result, err := reconciler.Reconcile(ctx, req)
+				_, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
-
-				mockK8sClient.AssertExpectations(GinkgoT())
-				mockAWSClient.AssertExpectations(GinkgoT())
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationTrustedTargetAnnotation, "arn:aws:iam::987654321098:role/target-role"))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationTrustStatusAnnotation, controller.TrustStatusUpdated))
+				mockTrustManager.AssertExpectations(GinkgoT())
 			})
-		})
 
-		Context("when ServiceAccount needs cleanup", func() {
-			It("should cleanup when finalizer exists but no role annotation", func() {
+			It("should report a denied trust policy update and still write the association", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:       "test-sa",
-						Namespace:  "default",
-						Finalizers: []string{controller.PodIdentityAssociationFinalizer},
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::987654321098:role/target-role",
+						},
 					},
 				}
 
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
 				req := ctrl.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      sa.Name,
@@ -527,33 +676,40 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockTrustManager := trustmocks.NewMockManager(GinkgoT())
+				reconciler.TrustManager = mockTrustManager
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Return(nil)
+				mockTrustManager.On("Grant", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", true).
+					Return(fmt.Errorf("%w: cannot update the trust policy", trustpolicy.ErrAccessDenied))
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				result, err := reconciler.Reconcile(ctx, req)
+				_, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
-
-				mockK8sClient.AssertExpectations(GinkgoT())
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationTrustStatusAnnotation, controller.TrustStatusDenied))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTrustedTargetAnnotation))
 				mockAWSClient.AssertExpectations(GinkgoT())
 			})
 
-			It("should remove tagging annotation during cleanup when no role annotation", func() {
+			It("should report a trust policy IAM rejects and still write the association", func() {
 				sa := &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
 						Annotations: map[string]string{
-							controller.PodIdentityAssociationTaggingAnnotation: "false",
-							controller.PodIdentityAssociationIDAnnotation:      "assoc-456",
-							"other-annotation": "should-remain",
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::987654321098:role/target-role",
 						},
-						Finalizers: []string{controller.PodIdentityAssociationFinalizer},
 					},
 				}
 
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
 				req := ctrl.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      sa.Name,
@@ -561,50 +717,1525 @@ var _ = Describe("ServiceAccountReconciler", func() {
 					},
 				}
 
+				mockTrustManager := trustmocks.NewMockManager(GinkgoT())
+				reconciler.TrustManager = mockTrustManager
+
 				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
-				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+				mockTrustManager.On("Grant", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", true).
+					Return(fmt.Errorf("%w: policy size exceeded", trustpolicy.ErrRejected))
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
-				// Mock the K8sClient update call and verify PIA annotations are removed but others remain
-				mockK8sClient.On("UpdateServiceAccount", ctx, sa).Run(func(args mock.Arguments) {
-					updatedSA, ok := args.Get(1).(*corev1.ServiceAccount)
-					Expect(ok).To(BeTrue())
-					// Verify that PIA-related annotations are removed
-					Expect(updatedSA.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTaggingAnnotation))
-					Expect(updatedSA.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationIDAnnotation))
-					// Verify that non-PIA annotations remain
-					Expect(updatedSA.Annotations).To(HaveKeyWithValue("other-annotation", "should-remain"))
-				}).Return(nil)
+				_, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationTrustStatusAnnotation, controller.TrustStatusRejected))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTrustedTargetAnnotation))
+			})
+
+			It("should requeue and report the status while the association is not ready", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+						},
+					},
+				}
+
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusCreating)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation, "assoc-123"))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationStatusAnnotation, "CREATING"))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationReadyAnnotation, "false"))
 
 				mockK8sClient.AssertExpectations(GinkgoT())
 				mockAWSClient.AssertExpectations(GinkgoT())
 			})
-		})
 
-		Context("when getting ServiceAccount fails", func() {
-			It("should return error for non-NotFound errors", func() {
-				req := ctrl.Request{
-					NamespacedName: types.NamespacedName{
+			It("should handle AWS errors", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
 						Name:      "test-sa",
 						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+						},
 					},
 				}
 
-				expectedError := errors.New("internal server error")
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
 
-				mockK8sClient.On("GetServiceAccount", ctx, "default", "test-sa").Return(nil, expectedError)
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				expectedError := errors.New("AWS error")
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, expectedError)
 
 				result, err := reconciler.Reconcile(ctx, req)
 
-				Expect(err).To(Equal(expectedError))
-				Expect(result).To(Equal(ctrl.Result{}))
+				// The reconciler should return the result from error handler, which will handle the error
+				// and return a requeue result with no error to the controller-runtime
+				Expect(err).ToNot(HaveOccurred())                     // Error handler handles the error internally
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0)) // Should requeue after some time
 
 				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
 			})
-		})
+
+			It("should create the association again when it was deleted outside of the operator", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationIDAnnotation:   "assoc-456",
+						},
+					},
+				}
+
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				notFound := &awsclient.Error{Operation: "update", Kind: awsclient.ErrAssociationNotFound, Err: errors.New("ResourceNotFoundException")}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("", notFound)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-789", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-789", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-789", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				_, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation, "assoc-789"))
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle tagging enabled by default (no annotation)", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+						},
+					},
+				}
+
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				// Expect tagging to be enabled (true) by default
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle tagging explicitly enabled (annotation set to true)", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationTaggingAnnotation: "true",
+						},
+					},
+				}
+
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				// Expect tagging to be enabled (true)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle tagging disabled (annotation set to false)", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationTaggingAnnotation: "false",
+						},
+					},
+				}
+
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				// Expect tagging to be disabled (false)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle tagging enabled with non-false value", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationTaggingAnnotation: "anything-not-false",
+						},
+					},
+				}
+
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				// Expect tagging to be enabled (true) for any value that's not "false"
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle update with tagging disabled", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:    "arn:aws:iam::123456789012:role/updated-role",
+							controller.PodIdentityAssociationTaggingAnnotation: "false",
+						},
+					},
+				}
+
+				// Create the ServiceAccount in the fake client
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(true, nil)
+				// Expect tagging to be disabled (false) in the update call
+				mockAWSClient.On("UpdatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return("assoc-456", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-456", "arn:aws:iam::123456789012:role/updated-role", "", awsclient.SessionOptions{TaggingEnabled: false}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-456", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+		})
+
+		Context("when ServiceAccount has role templates", func() {
+			const renderedRole = "arn:aws:iam::123456789012:role/test-cluster-payments-test-sa"
+
+			BeforeEach(func() {
+				Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:        "payments",
+					Labels:      map[string]string{"team": "billing"},
+					Annotations: map[string]string{controller.PodIdentityAssociationAccountIDAnnotation: "123456789012"},
+				}})).To(Succeed())
+			})
+
+			reconcile := func(sa *corev1.ServiceAccount) (ctrl.Result, error) {
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+				return reconciler.Reconcile(ctx, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace},
+				})
+			}
+
+			It("should render the roles before creating the association and record them", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "payments",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::{{.AccountID}}:role/{{.ClusterName}}-{{.Namespace}}-{{.ServiceAccount}}",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::987654321098:role/{{.NamespaceLabels.team}}-{{.Region}}",
+						},
+					},
+				}
+				targetRole := "arn:aws:iam::987654321098:role/billing-us-west-2"
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, renderedRole, targetRole, awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", renderedRole, targetRole, awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedRoleAnnotation, renderedRole))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedAssumeRoleAnnotation, targetRole))
+			})
+
+			It("should use the default role for an empty role annotation", func() {
+				reconciler.RoleDefaults = controller.RoleDefaults{
+					RoleArn:       "arn:aws:iam::{{.AccountID}}:role/{{.ClusterName}}-{{.Namespace}}-{{.ServiceAccount}}",
+					AssumeRoleArn: "arn:aws:iam::987654321098:role/shared",
+				}
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "payments",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "",
+							// An empty assume-role annotation opts out of the default target role
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, renderedRole, "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", renderedRole, "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedRoleAnnotation, renderedRole))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationRenderedAssumeRoleAnnotation))
+			})
+
+			It("should retry without calling AWS when a template cannot be rendered", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "payments",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/{{.NamespaceLabels.owner}}",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "AssociationExists", mock.Anything, mock.Anything)
+			})
+
+			It("should not use a role that is empty without a default", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "test-sa",
+						Namespace:   "payments",
+						Annotations: map[string]string{controller.PodIdentityAssociationRoleAnnotation: ""},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "AssociationExists", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("when ServiceAccount roles use account aliases", func() {
+			BeforeEach(func() {
+				reconciler.AccountRegistry = types.NamespacedName{Namespace: "pia-operator", Name: "accounts"}
+				Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "accounts", Namespace: "pia-operator"},
+					Data: map[string]string{
+						"payments-prod": "987654321098",
+						"payments-cn":   "aws-cn:111122223333",
+					},
+				})).To(Succeed())
+			})
+
+			reconcile := func(sa *corev1.ServiceAccount) (ctrl.Result, error) {
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+				return reconciler.Reconcile(ctx, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace},
+				})
+			}
+
+			It("should expand the aliases into role ARNs before creating the association", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "payments-prod:role/reader",
+						},
+					},
+				}
+				targetRole := "arn:aws:iam::987654321098:role/reader"
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", targetRole, awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", targetRole, awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedAssumeRoleAnnotation, targetRole))
+			})
+
+			It("should use the partition of the registry entry", func() {
+				reconciler.AWSRegion = "cn-north-1"
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "payments-cn:role/app",
+						},
+					},
+				}
+				role := "arn:aws-cn:iam::111122223333:role/app"
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedRoleAnnotation, role))
+			})
+
+			It("should default to the partition of the cluster", func() {
+				reconciler.AWSRegion = "us-gov-west-1"
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "payments-prod:role/app",
+						},
+					},
+				}
+				role := "arn:aws-us-gov:iam::987654321098:role/app"
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationRenderedRoleAnnotation, role))
+			})
+
+			It("should retry without calling AWS when the alias is not in the registry", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "payments-dev:role/reader",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "AssociationExists", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("when ServiceAccount roles are not in the partition of the cluster", func() {
+			reconcile := func(sa *corev1.ServiceAccount) (ctrl.Result, error) {
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+				return reconciler.Reconcile(ctx, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace},
+				})
+			}
+
+			It("should mark the association failed without calling AWS", func() {
+				reconciler.AWSRegion = "cn-north-1"
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws-cn:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::987654321098:role/reader",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationStatusAnnotation, string(awsclient.AssociationStatusFailed)))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationReadyAnnotation, "false"))
+				Expect(sa.Annotations[controller.PodIdentityAssociationRoleErrorAnnotation]).To(Equal(
+					`role "arn:aws:iam::987654321098:role/reader" is in partition aws but the cluster is in partition aws-cn`))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "AssociationExists", mock.Anything, mock.Anything)
+			})
+
+			It("should reject a role that is not an IAM role ARN", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:user/test-user",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations[controller.PodIdentityAssociationRoleErrorAnnotation]).To(ContainSubstring("is not a role ARN"))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "AssociationExists", mock.Anything, mock.Anything)
+			})
+
+			It("should clear the error once the roles are valid", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:      "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationRoleErrorAnnotation: "role is in partition aws-cn",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				_, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationRoleErrorAnnotation))
+			})
+		})
+
+		Context("when ServiceAccount has session annotations", func() {
+			const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+
+			reconcile := func(sa *corev1.ServiceAccount) (ctrl.Result, error) {
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+				return reconciler.Reconcile(ctx, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace},
+				})
+			}
+
+			It("should pass the session policy and report it as applied", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:          "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationTaggingAnnotation:       "false",
+							controller.PodIdentityAssociationSessionPolicyAnnotation: policy,
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{Policy: policy}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{Policy: policy}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationSessionStatusAnnotation, controller.SessionStatusApplied))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationSessionMessageAnnotation))
+			})
+
+			It("should not write the association when the session policy needs session tags disabled", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:          "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationSessionPolicyAnnotation: policy,
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa).Maybe()
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationSessionStatusAnnotation, controller.SessionStatusInvalid))
+				Expect(sa.Annotations[controller.PodIdentityAssociationSessionMessageAnnotation]).To(ContainSubstring("session-policy requires session tags to be disabled"))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "CreatePodIdentityAssociation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+
+			DescribeTable("should not write the association when the session policy is malformed",
+				func(sessionPolicy, problem string) {
+					sa := &corev1.ServiceAccount{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-sa",
+							Namespace: "default",
+							Annotations: map[string]string{
+								controller.PodIdentityAssociationRoleAnnotation:          "arn:aws:iam::123456789012:role/test-role",
+								controller.PodIdentityAssociationTaggingAnnotation:       "false",
+								controller.PodIdentityAssociationSessionPolicyAnnotation: sessionPolicy,
+							},
+						},
+					}
+
+					mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+					expectPatches(sa).Maybe()
+					expectApply(sa)
+
+					result, err := reconcile(sa)
+
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(ctrl.Result{}))
+					Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationSessionStatusAnnotation, controller.SessionStatusInvalid))
+					Expect(sa.Annotations[controller.PodIdentityAssociationSessionMessageAnnotation]).To(ContainSubstring(problem))
+				},
+				Entry("not JSON", `{"Statement": [`, "not a valid JSON policy document"),
+				Entry("without statements", `{"Version":"2012-10-17"}`, "has no Statement"),
+				Entry("with a scalar statement", `{"Version":"2012-10-17","Statement":"Allow"}`, "must be an object or a list"),
+				Entry("with an unknown version", `{"Version":"2024-01-01","Statement":[]}`, "unknown Version"),
+				Entry("too long", `{"Statement":[],"Sid":"`+strings.Repeat("x", 2048)+`"}`, "longer than 2048 characters"),
+			)
+
+			It("should report session tags and transitive tag keys as unsupported and still create the association", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:              "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationSessionTagsAnnotation:       "team=payments, cost-center=42",
+							controller.PodIdentityAssociationTransitiveTagKeysAnnotation: "team",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("AssociationExists", ctx, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", ctx, sa, "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", ctx, "assoc-123", "arn:aws:iam::123456789012:role/test-role", "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+				expectPatches(sa)
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationSessionStatusAnnotation, controller.SessionStatusUnsupported))
+				Expect(sa.Annotations[controller.PodIdentityAssociationSessionMessageAnnotation]).To(ContainSubstring("session-tags, transitive-tag-keys"))
+			})
+
+			It("should reject transitive tag keys that are not session tags", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:              "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationSessionTagsAnnotation:       "team=payments",
+							controller.PodIdentityAssociationTransitiveTagKeysAnnotation: "owner",
+						},
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				expectPatches(sa).Maybe()
+				expectApply(sa)
+
+				result, err := reconcile(sa)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationSessionStatusAnnotation, controller.SessionStatusInvalid))
+				Expect(sa.Annotations[controller.PodIdentityAssociationSessionMessageAnnotation]).To(ContainSubstring(`"owner" is not a session tag`))
+			})
+		})
+
+		Context("when ServiceAccount is being deleted", func() {
+			It("should handle deletion with finalizer", func() {
+				deletionTime := metav1.Now()
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+						},
+						Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
+						DeletionTimestamp: &deletionTime,
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+				expectPatches(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			Context("with a templated assume-role whose grant was not recorded", func() {
+				var (
+					sa  *corev1.ServiceAccount
+					req ctrl.Request
+				)
+
+				BeforeEach(func() {
+					deletionTime := metav1.Now()
+					sa = &corev1.ServiceAccount{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-sa",
+							Namespace: "default",
+							Annotations: map[string]string{
+								controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+								controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::{{.AccountID}}:role/{{.ServiceAccount}}",
+							},
+							Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
+							DeletionTimestamp: &deletionTime,
+						},
+					}
+					req = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sa)}
+
+					mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+					mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+					expectPatches(sa)
+				})
+
+				It("should revoke the trust of the rendered target role", func() {
+					sa.Annotations[controller.PodIdentityAssociationRenderedAssumeRoleAnnotation] = "arn:aws:iam::987654321098:role/test-sa"
+					mockTrustManager := trustmocks.NewMockManager(GinkgoT())
+					reconciler.TrustManager = mockTrustManager
+					mockTrustManager.On("Revoke", ctx, sa, "arn:aws:iam::987654321098:role/test-sa", []trustpolicy.Subject(nil)).Return(nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+
+					Expect(err).ToNot(HaveOccurred())
+					mockTrustManager.AssertExpectations(GinkgoT())
+				})
+
+				It("should not revoke the trust of the unrendered template", func() {
+					mockTrustManager := trustmocks.NewMockManager(GinkgoT())
+					reconciler.TrustManager = mockTrustManager
+
+					_, err := reconciler.Reconcile(ctx, req)
+
+					Expect(err).ToNot(HaveOccurred())
+					mockTrustManager.AssertNotCalled(GinkgoT(), "Revoke", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				})
+			})
+
+			It("should release the provisioned role on deletion", func() {
+				deletionTime := metav1.Now()
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationManagedRolePolicyARNsAnnotation: "arn:aws:iam::aws:policy/ReadOnlyAccess",
+							controller.PodIdentityAssociationManagedRoleAnnotation:           "arn:aws:iam::123456789012:role/test-cluster-default-test-sa",
+						},
+						Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
+						DeletionTimestamp: &deletionTime,
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockProvisioner := iamrolemocks.NewMockProvisioner(GinkgoT())
+				reconciler.RoleProvisioner = mockProvisioner
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+				mockProvisioner.On("ReleaseRole", ctx, sa).Return(nil)
+				expectPatches(sa)
+
+				_, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationManagedRoleAnnotation))
+				mockProvisioner.AssertExpectations(GinkgoT())
+			})
+
+			It("should handle deletion without finalizer", func() {
+				deletionTime := metav1.Now()
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "test-sa",
+						Namespace:         "default",
+						DeletionTimestamp: &deletionTime,
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should remove tagging annotation during deletion cleanup", func() {
+				deletionTime := metav1.Now()
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:       "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationAssumeRoleAnnotation: "arn:aws:iam::123456789012:role/assume-role",
+							controller.PodIdentityAssociationTaggingAnnotation:    "false",
+							controller.PodIdentityAssociationIDAnnotation:         "assoc-123",
+						},
+						Finalizers:        []string{controller.PodIdentityAssociationFinalizer},
+						DeletionTimestamp: &deletionTime,
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+
+				// Mock the K8sClient patches removing the annotations and the finalizer
+				expectPatches(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				// Verify that all PIA-related annotations are removed
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationAssumeRoleAnnotation))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationIDAnnotation))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTaggingAnnotation))
+				Expect(sa.Finalizers).To(BeEmpty())
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+		})
+
+		Context("when ServiceAccount needs cleanup", func() {
+			It("should cleanup when finalizer exists but no role annotation", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "test-sa",
+						Namespace:  "default",
+						Finalizers: []string{controller.PodIdentityAssociationFinalizer},
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+				expectPatches(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should remove tagging annotation during cleanup when no role annotation", func() {
+				sa := &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationTaggingAnnotation: "false",
+							controller.PodIdentityAssociationIDAnnotation:      "assoc-456",
+							"other-annotation": "should-remain",
+						},
+						Finalizers: []string{controller.PodIdentityAssociationFinalizer},
+					},
+				}
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      sa.Name,
+						Namespace: sa.Namespace,
+					},
+				}
+
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(sa, nil)
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil)
+
+				// Mock the K8sClient patches removing the annotations and the finalizer
+				expectPatches(sa)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				// Verify that PIA-related annotations are removed
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationTaggingAnnotation))
+				Expect(sa.Annotations).ToNot(HaveKey(controller.PodIdentityAssociationIDAnnotation))
+				// Verify that non-PIA annotations remain
+				Expect(sa.Annotations).To(HaveKeyWithValue("other-annotation", "should-remain"))
+				Expect(sa.Finalizers).To(BeEmpty())
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+		})
+
+		Context("when the namespace of the ServiceAccount is terminating", func() {
+			var (
+				serviceAccounts []*corev1.ServiceAccount
+				req             ctrl.Request
+			)
+
+			// terminateNamespace deletes a namespace with three ServiceAccounts, as the namespace controller does
+			terminateNamespace := func() {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:       "terminating",
+					Finalizers: []string{"kubernetes"},
+				}}
+				Expect(fakeClient.Create(ctx, ns)).To(Succeed())
+				Expect(fakeClient.Delete(ctx, ns)).To(Succeed())
+
+				serviceAccounts = nil
+				for i := 0; i < 3; i++ {
+					sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("test-sa-%d", i),
+						Namespace: "terminating",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationIDAnnotation:   fmt.Sprintf("assoc-%d", i),
+						},
+						Finalizers: []string{controller.PodIdentityAssociationFinalizer},
+					}}
+					Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+					Expect(fakeClient.Delete(ctx, sa)).To(Succeed())
+					Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(sa), sa)).To(Succeed())
+					serviceAccounts = append(serviceAccounts, sa)
+				}
+				req = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(serviceAccounts[0])}
+				mockK8sClient.On("GetServiceAccount", ctx, "terminating", serviceAccounts[0].Name).Return(serviceAccounts[0], nil)
+			}
+
+			// expectFinalizerRemovals records the ServiceAccounts whose finalizer was removed
+			expectFinalizerRemovals := func() *[]string {
+				var released []string
+				var mu sync.Mutex
+				mockK8sClient.On("PatchServiceAccount", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					sa := args.Get(1).(*corev1.ServiceAccount)
+					args.Get(2).(func(*corev1.ServiceAccount))(sa)
+					if len(sa.Finalizers) == 0 {
+						mu.Lock()
+						defer mu.Unlock()
+						released = append(released, sa.Name)
+					}
+				}).Return(nil)
+				return &released
+			}
+
+			BeforeEach(func() {
+				reconciler.NamespaceTerminationConcurrency = 2
+				reconciler.NamespaceTerminationTimeout = 10 * time.Second
+			})
+
+			It("should delete the associations of every ServiceAccount of the namespace at once", func() {
+				terminateNamespace()
+				released := expectFinalizerRemovals()
+				mockAWSClient.On("DeletePodIdentityAssociation", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(*released).To(ConsistOf("test-sa-0", "test-sa-1", "test-sa-2"))
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should retry failed deletions no later than the termination timeout", func() {
+				terminateNamespace()
+				released := expectFinalizerRemovals()
+				throttled := &awsclient.Error{Operation: "delete", Kind: awsclient.ErrThrottled, Err: errors.New("rate exceeded")}
+				mockAWSClient.On("DeletePodIdentityAssociation", mock.Anything, mock.MatchedBy(func(sa *corev1.ServiceAccount) bool {
+					return sa.Name == "test-sa-1"
+				})).Return(throttled)
+				mockAWSClient.On("DeletePodIdentityAssociation", mock.Anything, mock.Anything).Return(nil)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 10*time.Second))
+				Expect(*released).To(ConsistOf("test-sa-0", "test-sa-2"))
+			})
+
+			It("should release the finalizers without deleting the associations after the timeout", func() {
+				// The timeout has passed as soon as the namespace is deleted
+				reconciler.NamespaceTerminationTimeout = time.Nanosecond
+				terminateNamespace()
+				released := expectFinalizerRemovals()
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(*released).To(ConsistOf("test-sa-0", "test-sa-1", "test-sa-2"))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "DeletePodIdentityAssociation", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("when ServiceAccount was deleted without the finalizer", func() {
+			var (
+				sa  *corev1.ServiceAccount
+				req ctrl.Request
+			)
+
+			BeforeEach(func() {
+				sa = &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sa",
+						Namespace: "default",
+						Annotations: map[string]string{
+							controller.PodIdentityAssociationRoleAnnotation:  "arn:aws:iam::123456789012:role/test-role",
+							controller.PodIdentityAssociationIDAnnotation:    "assoc-123",
+							controller.PodIdentityAssociationReadyAnnotation: "true",
+						},
+					},
+				}
+				req = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sa)}
+
+				notFoundError := k8errors.NewNotFound(corev1.Resource("serviceaccounts"), sa.Name)
+				mockK8sClient.On("GetServiceAccount", ctx, sa.Namespace, sa.Name).Return(nil, notFoundError)
+			})
+
+			It("should delete the association recorded in the last known annotations", func() {
+				Expect(reconciler.TombstonePredicate().Delete(event.DeleteEvent{Object: sa})).To(BeTrue())
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil).Once()
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				// The tombstone is gone once the association was deleted
+				_, err = reconciler.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+				mockAWSClient.AssertNumberOfCalls(GinkgoT(), "DeletePodIdentityAssociation", 1)
+			})
+
+			It("should retry deleting the association after a transient error", func() {
+				Expect(reconciler.TombstonePredicate().Delete(event.DeleteEvent{Object: sa})).To(BeTrue())
+				throttled := &awsclient.Error{Operation: "delete", Kind: awsclient.ErrThrottled, Err: errors.New("rate exceeded")}
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(throttled).Once()
+				mockAWSClient.On("DeletePodIdentityAssociation", ctx, sa).Return(nil).Once()
+
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+				result, err = reconciler.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				mockAWSClient.AssertExpectations(GinkgoT())
+			})
+
+			It("should ignore the deletion of a ServiceAccount the operator already cleaned up", func() {
+				delete(sa.Annotations, controller.PodIdentityAssociationIDAnnotation)
+				delete(sa.Annotations, controller.PodIdentityAssociationReadyAnnotation)
+
+				Expect(reconciler.TombstonePredicate().Delete(event.DeleteEvent{Object: sa})).To(BeFalse())
+
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+				mockAWSClient.AssertNotCalled(GinkgoT(), "DeletePodIdentityAssociation", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("when getting ServiceAccount fails", func() {
+			It("should return error for non-NotFound errors", func() {
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "test-sa",
+						Namespace: "default",
+					},
+				}
+
+				expectedError := errors.New("internal server error")
+
+				mockK8sClient.On("GetServiceAccount", ctx, "default", "test-sa").Return(nil, expectedError)
+
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).To(Equal(expectedError))
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				mockK8sClient.AssertExpectations(GinkgoT())
+			})
+		})
+
+		Context("when the operator is shutting down", func() {
+			const role = "arn:aws:iam::123456789012:role/test-role"
+			var (
+				sa      *corev1.ServiceAccount
+				stopped context.Context
+			)
+
+			BeforeEach(func() {
+				sa = &corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "test-sa",
+						Namespace:   "default",
+						Annotations: map[string]string{controller.PodIdentityAssociationRoleAnnotation: role},
+					},
+				}
+				Expect(fakeClient.Create(ctx, sa)).To(Succeed())
+
+				var stop context.CancelFunc
+				stopped, stop = context.WithCancel(ctx)
+				stop()
+
+				mockK8sClient.On("GetServiceAccount", mock.Anything, sa.Namespace, sa.Name).Return(sa, nil)
+				mockK8sClient.On("PatchServiceAccount", mock.Anything, sa, mock.Anything).Run(func(args mock.Arguments) {
+					args.Get(2).(func(*corev1.ServiceAccount))(args.Get(1).(*corev1.ServiceAccount))
+				}).Return(nil).Maybe()
+				mockK8sClient.On("ApplyServiceAccountAnnotations", mock.Anything, sa, mock.Anything).Return(nil).Maybe()
+			})
+
+			reconcile := func() (ctrl.Result, error) {
+				return reconciler.Reconcile(stopped, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace},
+				})
+			}
+
+			It("should let an in-flight reconcile record its association within the grace period", func() {
+				reconciler.ShutdownGracePeriod = time.Minute
+				notCancelled := mock.MatchedBy(func(c context.Context) bool { return c.Err() == nil })
+
+				mockAWSClient.On("AssociationExists", notCancelled, sa).Return(false, nil)
+				mockAWSClient.On("CreatePodIdentityAssociation", notCancelled, sa, role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return("assoc-123", nil)
+				mockAWSClient.On("CheckAssociationReady", notCancelled, "assoc-123", role, "", awsclient.SessionOptions{TaggingEnabled: true}).Return(&awsclient.PodIdentityAssociation{ID: "assoc-123", Status: string(awsclient.AssociationStatusActive)}, nil)
+
+				result, err := reconcile()
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(sa.Annotations).To(HaveKeyWithValue(controller.PodIdentityAssociationIDAnnotation, "assoc-123"))
+			})
+
+			It("should cancel the AWS calls once the grace period has passed", func() {
+				reconciler.ShutdownGracePeriod = 10 * time.Millisecond
+
+				mockAWSClient.On("AssociationExists", mock.Anything, sa).Run(func(args mock.Arguments) {
+					Eventually(args.Get(0).(context.Context).Done()).Should(BeClosed())
+				}).Return(false, context.Canceled)
+
+				result, err := reconcile()
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				mockAWSClient.AssertNotCalled(GinkgoT(), "CreatePodIdentityAssociation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		})
+	})
+
+	Describe("SweepOrphans", func() {
+		// orphan is the ServiceAccount the sweep builds to describe and delete an association by ID
+		orphan := func(namespace, name, associationID string) *corev1.ServiceAccount {
+			return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{controller.PodIdentityAssociationIDAnnotation: associationID},
+			}}
+		}
+
+		// describe returns the association with the tags the operator sets for the ServiceAccount
+		describe := func(namespace, name, associationID, managedBy string) {
+			mockAWSClient.On("GetPodIdentityAssociation", ctx, orphan(namespace, name, associationID)).Return(&awsclient.PodIdentityAssociation{
+				ID:   associationID,
+				Tags: map[string]string{"managed-by": managedBy, "namespace": namespace, "serviceaccount": name},
+			}, nil)
+		}
+
+		It("should only delete the associations the operator created for ServiceAccounts that no longer exist", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"}})).To(Succeed())
+			mockAWSClient.On("ListPodIdentityAssociations", ctx).Return([]*awsclient.PodIdentityAssociation{
+				{ID: "a-live", Namespace: "default", ServiceAccountName: "live"},
+				{ID: "a-orphan", Namespace: "default", ServiceAccountName: "deleted"},
+				{ID: "a-foreign", Namespace: "default", ServiceAccountName: "unmanaged"},
+			}, nil)
+			describe("default", "deleted", "a-orphan", "pia-operator")
+			describe("default", "unmanaged", "a-foreign", "terraform")
+			mockAWSClient.On("DeletePodIdentityAssociation", ctx, orphan("default", "deleted", "a-orphan")).Return(nil).Once()
+
+			Expect(reconciler.SweepOrphans(ctx, fakeClient)).To(Succeed())
+
+			mockAWSClient.AssertNotCalled(GinkgoT(), "GetPodIdentityAssociation", ctx, orphan("default", "live", "a-live"))
+			mockAWSClient.AssertNumberOfCalls(GinkgoT(), "DeletePodIdentityAssociation", 1)
+		})
+
+		It("should leave associations of ServiceAccounts recreated since the cache was read", func() {
+			// The API server already has the recreated ServiceAccount the cache has not seen yet
+			apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "default", UID: "uid-new"},
+			}).Build()
+			mockAWSClient.On("ListPodIdentityAssociations", ctx).Return([]*awsclient.PodIdentityAssociation{
+				{ID: "a-old", Namespace: "default", ServiceAccountName: "recreated"},
+			}, nil)
+			mockAWSClient.On("GetPodIdentityAssociation", ctx, orphan("default", "recreated", "a-old")).Return(&awsclient.PodIdentityAssociation{
+				ID: "a-old",
+				Tags: map[string]string{
+					"managed-by": "pia-operator", "namespace": "default", "serviceaccount": "recreated", "serviceaccount-uid": "uid-old",
+				},
+			}, nil)
+
+			Expect(reconciler.SweepOrphans(ctx, apiReader)).To(Succeed())
+
+			mockAWSClient.AssertNotCalled(GinkgoT(), "DeletePodIdentityAssociation", mock.Anything, mock.Anything)
+		})
+
+		It("should only delete associations inside the scope", func() {
+			Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selected", Labels: map[string]string{"team": "a"}}})).To(Succeed())
+			Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"team": "b"}}})).To(Succeed())
+			scope, err := controller.NewScope(nil, "team=a", "app=web")
+			Expect(err).ToNot(HaveOccurred())
+			reconciler.Scope = scope
+
+			mockAWSClient.On("ListPodIdentityAssociations", ctx).Return([]*awsclient.PodIdentityAssociation{
+				{ID: "a-other-namespace", Namespace: "other", ServiceAccountName: "deleted"},
+				{ID: "a-other-selector", Namespace: "selected", ServiceAccountName: "unselected"},
+				{ID: "a-orphan", Namespace: "selected", ServiceAccountName: "deleted"},
+			}, nil)
+			// Created by an instance without a ServiceAccount selector
+			describe("selected", "unselected", "a-other-selector", "pia-operator")
+			mockAWSClient.On("GetPodIdentityAssociation", ctx, orphan("selected", "deleted", "a-orphan")).Return(&awsclient.PodIdentityAssociation{
+				ID: "a-orphan",
+				Tags: map[string]string{
+					"managed-by": "pia-operator", "namespace": "selected", "serviceaccount": "deleted",
+					controller.ServiceAccountSelectorTag: scope.Tags()[controller.ServiceAccountSelectorTag],
+				},
+			}, nil)
+			mockAWSClient.On("DeletePodIdentityAssociation", ctx, orphan("selected", "deleted", "a-orphan")).Return(nil).Once()
+
+			Expect(reconciler.SweepOrphans(ctx, fakeClient)).To(Succeed())
+
+			mockAWSClient.AssertNotCalled(GinkgoT(), "GetPodIdentityAssociation", ctx, orphan("other", "deleted", "a-other-namespace"))
+			mockAWSClient.AssertNumberOfCalls(GinkgoT(), "DeletePodIdentityAssociation", 1)
+		})
+
+		It("should leave the namespaces of other shards and drop their tombstones", func() {
+			mockShard := shardingmocks.NewMockShardOwner(GinkgoT())
+			owned := map[string]bool{"default": true}
+			mockShard.On("Owns", mock.Anything).Return(func(namespace string) bool { return owned[namespace] })
+			reconciler.Shard = mockShard
+
+			tombstone := orphan("moved", "test-sa", "a-moved")
+			tombstone.Annotations[controller.PodIdentityAssociationRoleAnnotation] = "arn:aws:iam::123456789012:role/test-role"
+			Expect(reconciler.TombstonePredicate().Delete(event.DeleteEvent{Object: tombstone})).To(BeTrue())
+			mockAWSClient.On("ListPodIdentityAssociations", ctx).Return([]*awsclient.PodIdentityAssociation{
+				{ID: "a-moved", Namespace: "moved", ServiceAccountName: "test-sa"},
+			}, nil)
+
+			Expect(reconciler.SweepOrphans(ctx, fakeClient)).To(Succeed())
+
+			// The namespace moving back does not resurrect the dropped tombstone
+			owned["moved"] = true
+			mockK8sClient.On("GetServiceAccount", ctx, "moved", "test-sa").
+				Return(nil, k8errors.NewNotFound(corev1.Resource("serviceaccounts"), "test-sa"))
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tombstone)})
+			Expect(err).ToNot(HaveOccurred())
+			mockAWSClient.AssertNotCalled(GinkgoT(), "GetPodIdentityAssociation", mock.Anything, mock.Anything)
+			mockAWSClient.AssertNotCalled(GinkgoT(), "DeletePodIdentityAssociation", mock.Anything, mock.Anything)
+		})
+	})
+})
+
+var _ = Describe("UpdatePredicate", func() {
+	var reconciler *controller.ServiceAccountReconciler
+
+	BeforeEach(func() {
+		reconciler = &controller.ServiceAccountReconciler{}
+	})
+
+	serviceAccount := func() *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-sa",
+				Namespace:  "default",
+				Finalizers: []string{controller.PodIdentityAssociationFinalizer},
+				Annotations: map[string]string{
+					controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+				},
+			},
+		}
+	}
+
+	It("should enqueue a ServiceAccount whose deletion started", func() {
+		oldSA := serviceAccount()
+		newSA := oldSA.DeepCopy()
+		now := metav1.Now()
+		newSA.DeletionTimestamp = &now
+
+		Expect(reconciler.UpdatePredicate().Update(event.UpdateEvent{ObjectOld: oldSA, ObjectNew: newSA})).To(BeTrue())
+	})
+
+	It("should enqueue a ServiceAccount whose role changed", func() {
+		oldSA := serviceAccount()
+		newSA := oldSA.DeepCopy()
+		newSA.Annotations[controller.PodIdentityAssociationRoleAnnotation] = "arn:aws:iam::123456789012:role/other-role"
+
+		Expect(reconciler.UpdatePredicate().Update(event.UpdateEvent{ObjectOld: oldSA, ObjectNew: newSA})).To(BeTrue())
+	})
+
+	It("should ignore updates of a ServiceAccount that is already being deleted", func() {
+		oldSA := serviceAccount()
+		now := metav1.Now()
+		oldSA.DeletionTimestamp = &now
+		newSA := oldSA.DeepCopy()
+		newSA.Labels = map[string]string{"team": "platform"}
+
+		Expect(reconciler.UpdatePredicate().Update(event.UpdateEvent{ObjectOld: oldSA, ObjectNew: newSA})).To(BeFalse())
+	})
+})
+
+var _ = Describe("AssociationPending", func() {
+	serviceAccount := func(annotations map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "default", Annotations: annotations}}
+	}
+
+	It("should ignore ServiceAccounts without a role", func() {
+		Expect(controller.AssociationPending(serviceAccount(nil))).To(BeFalse())
+	})
+
+	It("should report a missing association ID as pending", func() {
+		sa := serviceAccount(map[string]string{controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role"})
+		Expect(controller.AssociationPending(sa)).To(BeTrue())
+	})
+
+	It("should report an association that is not ready as pending", func() {
+		sa := serviceAccount(map[string]string{
+			controller.PodIdentityAssociationRoleAnnotation:  "arn:aws:iam::123456789012:role/test-role",
+			controller.PodIdentityAssociationIDAnnotation:    "a-12345",
+			controller.PodIdentityAssociationReadyAnnotation: "false",
+		})
+		Expect(controller.AssociationPending(sa)).To(BeTrue())
+	})
+
+	It("should treat a recorded association without readiness as ready", func() {
+		sa := serviceAccount(map[string]string{
+			controller.PodIdentityAssociationRoleAnnotation: "arn:aws:iam::123456789012:role/test-role",
+			controller.PodIdentityAssociationIDAnnotation:   "a-12345",
+		})
+		Expect(controller.AssociationPending(sa)).To(BeFalse())
+	})
+
+	It("should report a named binding without an association as pending", func() {
+		sa := serviceAccount(map[string]string{controller.PodIdentityAssociationRoleAnnotation + ".reader": "arn:aws:iam::123456789012:role/reader"})
+		Expect(controller.AssociationPending(sa)).To(BeTrue())
 	})
 })
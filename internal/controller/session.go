@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	awsclient "github.com/irenedo/pia-operator/pkg/awsclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Annotations configuring the role session of a binding; like the role annotations they can be
+	// suffixed with ".<name>" for named bindings
+	PodIdentityAssociationSessionPolicyAnnotation     = "pia-operator.eks.aws.com/session-policy"
+	PodIdentityAssociationSessionTagsAnnotation       = "pia-operator.eks.aws.com/session-tags"
+	PodIdentityAssociationTransitiveTagKeysAnnotation = "pia-operator.eks.aws.com/transitive-tag-keys"
+
+	// Annotations reporting whether the session options of the primary binding were applied
+	PodIdentityAssociationSessionStatusAnnotation  = "pia-operator.eks.aws.com/session-status"
+	PodIdentityAssociationSessionMessageAnnotation = "pia-operator.eks.aws.com/session-message"
+
+	// Values of the session status annotation
+	SessionStatusApplied     = "Applied"
+	SessionStatusUnsupported = "Unsupported"
+	SessionStatusInvalid     = "Invalid"
+)
+
+// maxSessionPolicyLength is the longest session policy EKS accepts on an association
+const maxSessionPolicyLength = 2048
+
+// eksSessionTagKeys are the session tags EKS Pod Identity adds to the role session unless tagging is disabled
+var eksSessionTagKeys = map[string]bool{
+	"eks-cluster-arn":            true,
+	"eks-cluster-name":           true,
+	"kubernetes-namespace":       true,
+	"kubernetes-service-account": true,
+	"kubernetes-pod-name":        true,
+	"kubernetes-pod-uid":         true,
+}
+
+// session returns the session options of the binding supported by the EKS API
+func (b Binding) session() awsclient.SessionOptions {
+	return awsclient.SessionOptions{TaggingEnabled: b.TaggingEnabled, Policy: b.SessionPolicy}
+}
+
+// requestsSessionOptions reports whether the binding configures its role session beyond tagging
+func (b Binding) requestsSessionOptions() bool {
+	return b.SessionPolicy != "" || b.SessionTags != "" || b.TransitiveTagKeys != ""
+}
+
+// validateSession checks the session options of the binding. Invalid options are returned as
+// problems; valid options the EKS Pod Identity API has no parameter for are returned as unsupported.
+func (b Binding) validateSession() (problems, unsupported []string) {
+	if b.SessionPolicy != "" {
+		if problem := validateSessionPolicy(b.SessionPolicy); problem != "" {
+			problems = append(problems, problem)
+		} else if b.TaggingEnabled {
+			problems = append(problems, `session-policy requires session tags to be disabled with tagging: "false"`)
+		}
+	}
+
+	tags, err := parseSessionTags(b.SessionTags)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	for _, key := range splitAnnotationList(b.TransitiveTagKeys) {
+		if _, ok := tags[key]; ok || (eksSessionTagKeys[key] && b.TaggingEnabled) {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("transitive-tag-keys: %q is not a session tag of the binding", key))
+	}
+
+	if len(tags) > 0 {
+		unsupported = append(unsupported, "session-tags")
+	}
+	if b.TransitiveTagKeys != "" {
+		unsupported = append(unsupported, "transitive-tag-keys")
+	}
+	return problems, unsupported
+}
+
+// validateSessionPolicy checks that the session policy is a JSON policy document EKS can accept,
+// returning the problem found or an empty string
+func validateSessionPolicy(value string) string {
+	if len(value) > maxSessionPolicyLength {
+		return fmt.Sprintf("session-policy is longer than %d characters", maxSessionPolicyLength)
+	}
+	var policy struct {
+		Version   string
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return "session-policy is not a valid JSON policy document"
+	}
+	var statement interface{}
+	if err := json.Unmarshal(policy.Statement, &statement); err != nil || statement == nil {
+		return "session-policy has no Statement"
+	}
+	switch statement.(type) {
+	case []interface{}, map[string]interface{}:
+	default:
+		return "session-policy Statement must be an object or a list of objects"
+	}
+	if policy.Version != "" && policy.Version != "2012-10-17" && policy.Version != "2008-10-17" {
+		return fmt.Sprintf("session-policy has unknown Version %q", policy.Version)
+	}
+	return ""
+}
+
+// parseSessionTags parses a comma separated list of key=value session tags
+func parseSessionTags(value string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, item := range splitAnnotationList(value) {
+		key, tagValue, ok := strings.Cut(item, "=")
+		key, tagValue = strings.TrimSpace(key), strings.TrimSpace(tagValue)
+		switch {
+		case !ok || key == "":
+			return nil, fmt.Errorf("session-tags: %q is not a key=value pair", item)
+		case strings.HasPrefix(strings.ToLower(key), "aws:"):
+			return nil, fmt.Errorf("session-tags: key %q uses the reserved aws: prefix", key)
+		case eksSessionTagKeys[key]:
+			return nil, fmt.Errorf("session-tags: key %q is set by EKS Pod Identity", key)
+		case len(key) > 128:
+			return nil, fmt.Errorf("session-tags: key %q is longer than 128 characters", key)
+		case len(tagValue) > 256:
+			return nil, fmt.Errorf("session-tags: value of key %q is longer than 256 characters", key)
+		}
+		tags[key] = tagValue
+	}
+	return tags, nil
+}
+
+// splitAnnotationList splits a comma separated annotation value, dropping empty entries
+func splitAnnotationList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// checkSessionOptions records on the ServiceAccount whether the session options of the binding were
+// applied, and reports false when they are invalid so that the association is left unchanged.
+// Options that are valid but not supported by EKS are ignored. The caller persists the annotations.
+func (r *ServiceAccountReconciler) checkSessionOptions(sa *corev1.ServiceAccount, binding Binding) bool {
+	if !binding.requestsSessionOptions() {
+		delete(sa.Annotations, PodIdentityAssociationSessionStatusAnnotation)
+		delete(sa.Annotations, PodIdentityAssociationSessionMessageAnnotation)
+		return true
+	}
+	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "binding", binding.Name)
+
+	problems, unsupported := binding.validateSession()
+	switch {
+	case len(problems) > 0:
+		message := strings.Join(problems, "; ")
+		log.Info("Invalid session options, not writing the Pod Identity Association", "problems", message)
+		sa.Annotations[PodIdentityAssociationSessionStatusAnnotation] = SessionStatusInvalid
+		sa.Annotations[PodIdentityAssociationSessionMessageAnnotation] = message
+		return false
+	case len(unsupported) > 0:
+		message := strings.Join(unsupported, ", ") + " not supported by the EKS Pod Identity API, ignored"
+		log.Info("Session options are not supported by EKS and are ignored", "options", unsupported)
+		sa.Annotations[PodIdentityAssociationSessionStatusAnnotation] = SessionStatusUnsupported
+		sa.Annotations[PodIdentityAssociationSessionMessageAnnotation] = message
+	default:
+		sa.Annotations[PodIdentityAssociationSessionStatusAnnotation] = SessionStatusApplied
+		delete(sa.Annotations, PodIdentityAssociationSessionMessageAnnotation)
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	metric "github.com/irenedo/pia-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ownedNamespaces tracks the namespaces owned by this replica for the shard ownership gauge
+type ownedNamespaces struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+func (o *ownedNamespaces) set(namespace string, owned bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.names == nil {
+		o.names = make(map[string]bool)
+	}
+	if owned {
+		o.names[namespace] = true
+	} else {
+		delete(o.names, namespace)
+	}
+	metric.SetShardOwnedNamespaces(len(o.names))
+}
+
+// reset replaces the owned namespaces after a rebalance
+func (o *ownedNamespaces) reset(namespaces []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		o.names[namespace] = true
+	}
+	metric.SetShardOwnedNamespaces(len(o.names))
+}
+
+// ownsNamespace reports whether this replica handles the namespace; everything is owned when sharding is disabled
+func (r *ServiceAccountReconciler) ownsNamespace(namespace string) bool {
+	return r.Shard == nil || r.Shard.Owns(namespace)
+}
+
+// shardPredicate drops events for ServiceAccounts in namespaces owned by other replicas
+func (r *ServiceAccountReconciler) shardPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return r.ownsNamespace(obj.GetNamespace())
+	})
+}
+
+// countOwnedNamespaces keeps the shard ownership gauge up to date as namespaces are created and deleted.
+// It does not enqueue anything.
+func (r *ServiceAccountReconciler) countOwnedNamespaces() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.RateLimitingInterface) {
+			r.ownedNamespaces.set(e.Object.GetName(), r.ownsNamespace(e.Object.GetName()))
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+			r.ownedNamespaces.set(e.Object.GetName(), false)
+		},
+	}
+}
+
+// recountOwnedNamespaces recomputes the shard ownership gauge from the cached namespaces
+func (r *ServiceAccountReconciler) recountOwnedNamespaces(ctx context.Context) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		return err
+	}
+	var owned []string
+	for i := range namespaces.Items {
+		if r.ownsNamespace(namespaces.Items[i].Name) {
+			owned = append(owned, namespaces.Items[i].Name)
+		}
+	}
+	r.ownedNamespaces.reset(owned)
+	return nil
+}
+
+// rebalance enqueues every managed ServiceAccount this replica owns after the shard membership changed
+// or a namespace was handed over. Events for namespaces that moved here were dropped while another
+// replica owned them, so they have to be reconciled again. The cache may not be synced yet on the first
+// rebalance, so the listing runs in the background instead of blocking the membership loop.
+func (r *ServiceAccountReconciler) rebalance(events chan<- event.GenericEvent) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		go func() {
+			if err := r.recountOwnedNamespaces(ctx); err != nil {
+				r.Log.Error(err, "Failed to count owned namespaces after shard rebalance")
+			}
+
+			serviceAccounts := &corev1.ServiceAccountList{}
+			if err := r.List(ctx, serviceAccounts); err != nil {
+				r.Log.Error(err, "Failed to list ServiceAccounts after shard rebalance")
+				return
+			}
+
+			for i := range serviceAccounts.Items {
+				sa := &serviceAccounts.Items[i]
+				if !hasBindings(sa.Annotations) && !controllerutil.ContainsFinalizer(sa, PodIdentityAssociationFinalizer) {
+					continue
+				}
+				if !r.ownsNamespace(sa.Namespace) {
+					continue
+				}
+				select {
+				case events <- event.GenericEvent{Object: sa}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
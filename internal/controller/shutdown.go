@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// drainContext returns the context of a reconcile. On shutdown the manager cancels ctx while the
+// reconcile may be between creating an association and recording its ID; the returned context is
+// only cancelled ShutdownGracePeriod later, so that the reconcile can finish. The caller cancels it
+// when the reconcile returns.
+func (r *ServiceAccountReconciler) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.ShutdownGracePeriod <= 0 {
+		return ctx, func() {}
+	}
+	drain, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(r.ShutdownGracePeriod, cancel)
+	})
+	return drain, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// tombstones holds the last known state of ServiceAccounts that were deleted before the operator
+// cleaned up after them, for example because their finalizer was removed by hand
+type tombstones struct {
+	mu      sync.Mutex
+	objects map[types.NamespacedName]*corev1.ServiceAccount
+}
+
+func (t *tombstones) add(sa *corev1.ServiceAccount) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.objects == nil {
+		t.objects = make(map[types.NamespacedName]*corev1.ServiceAccount)
+	}
+	t.objects[client.ObjectKeyFromObject(sa)] = sa.DeepCopy()
+}
+
+func (t *tombstones) get(key types.NamespacedName) (*corev1.ServiceAccount, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sa, ok := t.objects[key]
+	return sa, ok
+}
+
+func (t *tombstones) remove(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.objects, key)
+}
+
+// prune removes the tombstones whose key matches
+func (t *tombstones) prune(match func(types.NamespacedName) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.objects {
+		if match(key) {
+			delete(t.objects, key)
+		}
+	}
+}
+
+// TombstonePredicate lets through the deletion of ServiceAccounts that still carry the operator's
+// annotations, which the operator removes once the association is deleted, and records their last
+// known state for the reconcile. It has to be the last predicate so that only enqueued deletions are recorded.
+func (r *ServiceAccountReconciler) TombstonePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			sa, ok := e.Object.(*corev1.ServiceAccount)
+			if !ok || len(operatorAnnotations(sa.Annotations)) == 0 {
+				return false
+			}
+			r.tombstones.add(sa)
+			return true
+		},
+	}
+}
+
+// handleTombstone deletes the association of a ServiceAccount that no longer exists, using the
+// annotations it had when it was deleted. The association is deleted by its recorded ID or, when
+// the ID was never recorded, looked up by the namespace and name of the ServiceAccount.
+func (r *ServiceAccountReconciler) handleTombstone(ctx context.Context, sa *corev1.ServiceAccount) (ctrl.Result, error) {
+	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace)
+	log.Info("ServiceAccount was deleted before its Pod Identity Association, deleting it",
+		"associationID", sa.Annotations[PodIdentityAssociationIDAnnotation])
+
+	if err := r.releasePodIdentityAssociation(ctx, sa); err != nil {
+		result, err := r.ErrorHandler.HandleDeletionError(ctx, sa, err, "delete Pod Identity Association of deleted ServiceAccount")
+		if err != nil || !result.IsZero() {
+			return result, err
+		}
+		// The error is not worth retrying, give up on the association
+	}
+
+	r.tombstones.remove(client.ObjectKeyFromObject(sa))
+	r.ErrorHandler.ResetRetryCount(ctx, sa)
+	return ctrl.Result{}, nil
+}
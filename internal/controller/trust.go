@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Annotation recording the target role whose trust policy trusts the base role for this ServiceAccount
+	PodIdentityAssociationTrustedTargetAnnotation = "pia-operator.eks.aws.com/trust-policy-target"
+	// Annotation reporting the outcome of the last trust policy update
+	PodIdentityAssociationTrustStatusAnnotation = "pia-operator.eks.aws.com/trust-policy-status"
+
+	// Values of the trust policy status annotation
+	TrustStatusUpdated  = "Updated"
+	TrustStatusDenied   = "Denied"
+	TrustStatusReadOnly = "ReadOnly"
+	TrustStatusRejected = "Rejected"
+)
+
+// syncTrustPolicy makes the target role of the binding trust its base role, and revokes the
+// trust of a previous target role. Denied, read-only and rejected trust policies are reported on the
+// ServiceAccount instead of failing the reconcile, since retrying cannot fix them and the
+// target role may already trust the base role. The caller persists the annotations.
+func (r *ServiceAccountReconciler) syncTrustPolicy(ctx context.Context, sa *corev1.ServiceAccount, binding Binding) error {
+	if r.TrustManager == nil {
+		return nil
+	}
+	log := r.Log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace)
+
+	if previous := sa.Annotations[PodIdentityAssociationTrustedTargetAnnotation]; previous != "" && previous != binding.AssumeRoleArn {
+		keep, err := r.trustSubjects(ctx, sa, previous)
+		if err != nil {
+			return err
+		}
+		if err := r.TrustManager.Revoke(ctx, sa, previous, keep); err != nil {
+			if !isUnfixableTrustError(err) {
+				return err
+			}
+			log.Error(err, "Failed to revoke trust of previous target role", "targetRoleArn", previous)
+		}
+		delete(sa.Annotations, PodIdentityAssociationTrustedTargetAnnotation)
+	}
+
+	if binding.AssumeRoleArn == "" {
+		delete(sa.Annotations, PodIdentityAssociationTrustStatusAnnotation)
+		return nil
+	}
+
+	err := r.TrustManager.Grant(ctx, sa, binding.RoleArn, binding.AssumeRoleArn, binding.TaggingEnabled)
+	switch {
+	case err == nil:
+		sa.Annotations[PodIdentityAssociationTrustedTargetAnnotation] = binding.AssumeRoleArn
+		sa.Annotations[PodIdentityAssociationTrustStatusAnnotation] = TrustStatusUpdated
+	case stderrors.Is(err, trustpolicy.ErrAccessDenied):
+		log.Error(err, "Not allowed to update the trust policy of the target role", "targetRoleArn", binding.AssumeRoleArn)
+		sa.Annotations[PodIdentityAssociationTrustStatusAnnotation] = TrustStatusDenied
+	case stderrors.Is(err, trustpolicy.ErrReadOnly):
+		log.Error(err, "Trust policy of the target role is read-only", "targetRoleArn", binding.AssumeRoleArn)
+		sa.Annotations[PodIdentityAssociationTrustStatusAnnotation] = TrustStatusReadOnly
+	case stderrors.Is(err, trustpolicy.ErrRejected):
+		log.Error(err, "IAM rejected the trust policy of the target role", "targetRoleArn", binding.AssumeRoleArn)
+		sa.Annotations[PodIdentityAssociationTrustStatusAnnotation] = TrustStatusRejected
+	default:
+		return err
+	}
+	return nil
+}
+
+// revokeTrust removes the trust granted for the ServiceAccount when it is deleted or unbound
+func (r *ServiceAccountReconciler) revokeTrust(ctx context.Context, sa *corev1.ServiceAccount) error {
+	if r.TrustManager == nil {
+		return nil
+	}
+	target := sa.Annotations[PodIdentityAssociationTrustedTargetAnnotation]
+	if target == "" {
+		// The grant may have succeeded without being recorded
+		target = grantedTarget(sa.Annotations)
+	}
+	if target == "" {
+		return nil
+	}
+
+	keep, err := r.trustSubjects(ctx, sa, target)
+	if err != nil {
+		return err
+	}
+	if err := r.TrustManager.Revoke(ctx, sa, target, keep); err != nil {
+		if !isUnfixableTrustError(err) {
+			return err
+		}
+		r.Log.Error(err, "Failed to revoke trust of target role, remove the statement manually",
+			"serviceaccount", sa.Name, "namespace", sa.Namespace, "targetRoleArn", target)
+	}
+	return nil
+}
+
+// trustSubjects returns the other ServiceAccounts the target role trusts, so that revoking the
+// trust of one ServiceAccount keeps the namespaces and names they share in the trust policy
+func (r *ServiceAccountReconciler) trustSubjects(ctx context.Context, sa *corev1.ServiceAccount, target string) ([]trustpolicy.Subject, error) {
+	var list corev1.ServiceAccountList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	var subjects []trustpolicy.Subject
+	for i := range list.Items {
+		other := &list.Items[i]
+		if (other.Namespace == sa.Namespace && other.Name == sa.Name) || !other.DeletionTimestamp.IsZero() ||
+			other.Annotations[PodIdentityAssociationTrustedTargetAnnotation] != target {
+			continue
+		}
+		binding, ok := primaryBinding(other.Annotations, parseBindings(other.Annotations))
+		if !ok {
+			continue
+		}
+		roleArn := other.Annotations[PodIdentityAssociationRenderedRoleAnnotation]
+		if roleArn == "" {
+			roleArn = binding.RoleArn
+		}
+		subjects = append(subjects, trustpolicy.Subject{
+			Namespace:      other.Namespace,
+			Name:           other.Name,
+			RoleArn:        roleArn,
+			TaggingEnabled: binding.TaggingEnabled,
+		})
+	}
+	return subjects, nil
+}
+
+// grantedTarget returns the target role the primary binding was last rendered to. Templates and
+// account aliases are only resolved while reconciling, so an assume-role annotation is only used
+// as is when it is already an ARN.
+func grantedTarget(annotations map[string]string) string {
+	if rendered := annotations[PodIdentityAssociationRenderedAssumeRoleAnnotation]; rendered != "" {
+		return rendered
+	}
+	binding, ok := primaryBinding(annotations, parseBindings(annotations))
+	if !ok || isRoleTemplate(binding.AssumeRoleArn) || isAccountAliasRole(binding.AssumeRoleArn) {
+		return ""
+	}
+	return binding.AssumeRoleArn
+}
+
+func isUnfixableTrustError(err error) bool {
+	return stderrors.Is(err, trustpolicy.ErrAccessDenied) || stderrors.Is(err, trustpolicy.ErrReadOnly) ||
+		stderrors.Is(err, trustpolicy.ErrRejected)
+}
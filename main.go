@@ -3,21 +3,43 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/irenedo/pia-operator/internal/controller"
 
 	"github.com/irenedo/pia-operator/pkg/awsclient"
+	"github.com/irenedo/pia-operator/pkg/config"
+	"github.com/irenedo/pia-operator/pkg/discovery"
+	errorhandling "github.com/irenedo/pia-operator/pkg/errors"
+	"github.com/irenedo/pia-operator/pkg/health"
+	"github.com/irenedo/pia-operator/pkg/iamrole"
 	"github.com/irenedo/pia-operator/pkg/k8sclient"
 	metrics "github.com/irenedo/pia-operator/pkg/metrics"
+	"github.com/irenedo/pia-operator/pkg/podgate"
+	"github.com/irenedo/pia-operator/pkg/rollout"
+	"github.com/irenedo/pia-operator/pkg/sharding"
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// awsCheckTimeout bounds the AWS calls of the readiness check
+	awsCheckTimeout = 10 * time.Second
+	// shutdownMargin is added to the shutdown grace period for the manager to stop its other runnables
+	shutdownMargin = 5 * time.Second
 )
 
 var (
@@ -30,21 +52,49 @@ func init() {
 }
 
 func main() {
-	ctx := context.Background()
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
-	var awsRegion string
-	var clusterName string
+	// Cancelled on SIGTERM, the AWS calls made while setting up stop with the manager
+	ctx := ctrl.SetupSignalHandler()
+	var configFile string
 	var devMode bool
 
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+	defaults := config.Default()
+	flag.StringVar(&configFile, "config", "", "Path to an OperatorConfig file. Flags set explicitly override values from the file.")
+	flag.String("metrics-bind-address", defaults.Metrics.BindAddress, "The address the metric endpoint binds to.")
+	flag.String("health-probe-bind-address", defaults.Health.HealthProbeBindAddress, "The address the probe endpoint binds to.")
+	flag.Bool("leader-elect", defaults.LeaderElection.LeaderElect,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.StringVar(&awsRegion, "aws-region", "", "AWS region for EKS operations")
-	flag.StringVar(&clusterName, "cluster-name", "", "EKS cluster name")
+	flag.String("aws-region", defaults.AWSRegion, "AWS region for EKS operations. Discovered when unset.")
+	flag.String("cluster-name", defaults.ClusterName, "EKS cluster name. Discovered when unset.")
+	flag.String("watch-namespaces", "", "Comma separated list of namespaces to watch. Defaults to all namespaces.")
+	flag.String("namespace-selector", "", "Label selector that namespaces must match for their ServiceAccounts to be handled")
+	flag.String("serviceaccount-selector", "", "Label selector that ServiceAccounts must match to be handled")
+	flag.Int("max-concurrent-reconciles", defaults.Controller.MaxConcurrentReconciles,
+		"Number of ServiceAccounts reconciled in parallel.")
+	flag.Int("rollout-max-restarts-per-minute", defaults.Rollout.MaxRestartsPerMinute,
+		"Maximum number of workloads restarted per minute for ServiceAccounts annotated with rollout-on-change.")
+	flag.String("pod-webhook-mode", "",
+		"Hold back pods until their Pod Identity Association is ready: deny or readiness-gate. Disabled when empty.")
+	flag.Int("webhook-port", defaults.Webhook.Port, "The port the webhook server listens on.")
+	flag.String("webhook-cert-dir", "", "Directory with tls.crt and tls.key for the webhook server.")
+	flag.Bool("sharding", defaults.Sharding.Enabled,
+		"Split namespaces between all replicas using lease based shard membership. Cannot be combined with --leader-elect.")
+	flag.Bool("role-provisioning", defaults.RoleProvisioning.Enabled,
+		"Create the IAM roles of ServiceAccounts annotated with managed-role-policy or managed-role-policy-arns.")
+	flag.Bool("manage-trust-policy", defaults.TrustPolicy.Enabled,
+		"Add the base role to the trust policy of assume-role target roles, and remove it when the ServiceAccount is unbound.")
+	flag.String("trust-policy-access-role", "", "Name of the role assumed in the target role's account to change its trust policy.")
+	flag.Int("namespace-termination-concurrency", defaults.NamespaceTermination.Concurrency,
+		"Number of Pod Identity Associations of a terminating namespace deleted in parallel.")
+	flag.Duration("namespace-termination-timeout", defaults.NamespaceTermination.Timeout.Duration,
+		"Time after which the finalizers of a terminating namespace's ServiceAccounts are released even if their associations could not be deleted. 0 never releases them.")
+	flag.Duration("shutdown-grace-period", defaults.Controller.ShutdownGracePeriod.Duration,
+		"Time in-flight reconciles may keep running after a shutdown signal to finish their AWS operations. 0 cancels them immediately.")
+	flag.Duration("orphan-sweep-interval", defaults.Controller.OrphanSweepInterval.Duration,
+		"Interval at which Pod Identity Associations of ServiceAccounts that no longer exist are deleted. 0 disables the sweep.")
+	flag.String("account-registry", "",
+		"Name of the ConfigMap mapping account aliases to account IDs, in the operator namespace. Disabled when empty.")
+	flag.String("shard-lease-namespace", "", "Namespace of the shard membership leases. Defaults to the POD_NAMESPACE environment variable.")
 	flag.BoolVar(&devMode, "dev-mode", false, "Enable development logging mode (more verbose logs)")
 
 	opts := zap.Options{
@@ -55,19 +105,67 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	if awsRegion == "" {
-		awsRegion = "eu-west-1"
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load configuration")
+		os.Exit(1)
+	}
+	if err := cfg.ApplyFlagOverrides(flag.CommandLine); err != nil {
+		setupLog.Error(err, "unable to apply flag overrides")
+		os.Exit(1)
+	}
+	if cfg.ClusterName == "" || cfg.AWSRegion == "" {
+		cluster, err := discoverCluster(ctx, discovery.Cluster{Name: cfg.ClusterName, Region: cfg.AWSRegion})
+		if err != nil {
+			setupLog.Error(err, "unable to discover the cluster")
+			os.Exit(1)
+		}
+		cfg.ClusterName, cfg.AWSRegion = cluster.Name, cluster.Region
 	}
+	if err := cfg.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+	store := config.NewStore(cfg)
 
-	if clusterName == "" {
-		setupLog.Error(nil, "cluster-name flag is required")
+	scope, err := controller.NewScope(cfg.Scope.Namespaces, cfg.Scope.NamespaceSelector, cfg.Scope.ServiceAccountSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid scope configuration")
 		os.Exit(1)
 	}
 
+	cacheOptions := scope.CacheOptions()
+	var accountRegistry types.NamespacedName
+	if cfg.AccountRegistry.ConfigMap != "" {
+		accountRegistry = types.NamespacedName{Namespace: cfg.AccountRegistry.Namespace, Name: cfg.AccountRegistry.ConfigMap}
+		if accountRegistry.Namespace == "" {
+			accountRegistry.Namespace = os.Getenv("POD_NAMESPACE")
+		}
+		if accountRegistry.Namespace == "" {
+			setupLog.Error(nil, "account registry namespace is not set, use accountRegistry.namespace or POD_NAMESPACE")
+			os.Exit(1)
+		}
+		cacheOptions = controller.AccountRegistryCacheOptions(cacheOptions, accountRegistry)
+	}
+
+	if podgate.Mode(cfg.Webhook.PodMode) == podgate.ModeReadinessGate {
+		cacheOptions = controller.GatedPodCacheOptions(cacheOptions)
+	}
+
+	shutdownTimeout := cfg.Controller.ShutdownGracePeriod.Duration + shutdownMargin
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:           scheme,
-		LeaderElection:   enableLeaderElection,
-		LeaderElectionID: "pia-operator.eks.aws.com",
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: cfg.Metrics.BindAddress},
+		HealthProbeBindAddress: cfg.Health.HealthProbeBindAddress,
+		LeaderElection:         cfg.LeaderElection.LeaderElect,
+		LeaderElectionID:       cfg.LeaderElection.ResourceName,
+		Cache:                  cacheOptions,
+		// Reconciles are cancelled after the grace period and need a moment to return
+		GracefulShutdownTimeout: &shutdownTimeout,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    cfg.Webhook.Port,
+			CertDir: cfg.Webhook.CertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -76,20 +174,103 @@ func main() {
 	// Register custom metrics
 	metrics.RegisterMetrics(ctrlmetrics.Registry)
 
-	awsClient, err := awsclient.NewClient(ctx, clusterName, awsRegion, ctrl.Log.WithName("controllers").WithName("ServiceAccount"))
+	awsClient, err := awsclient.NewClient(ctx, cfg.ClusterName, cfg.AWSRegion, ctrl.Log.WithName("controllers").WithName("ServiceAccount"),
+		awsclient.WithTagSource(func() map[string]string {
+			tags := make(map[string]string)
+			for k, v := range store.Get().Tags {
+				tags[k] = v
+			}
+			for k, v := range scope.Tags() {
+				tags[k] = v
+			}
+			return tags
+		}))
 	if err != nil {
 		setupLog.Error(err, "unable to create AWS client")
 		os.Exit(1)
 	}
 
+	errorHandler := errorhandling.NewErrorHandler(mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ServiceAccount"))
+	errorHandler.SetRetryPolicy(retryPolicy(cfg))
+	store.OnChange(func(cfg *config.OperatorConfig) {
+		errorHandler.SetRetryPolicy(retryPolicy(cfg))
+		setupLog.Info("configuration reloaded")
+	})
+
 	reconciler := &controller.ServiceAccountReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		Log:         ctrl.Log.WithName("controllers").WithName("ServiceAccount"),
-		AWSRegion:   awsRegion,
-		ClusterName: clusterName,
-		AWSClient:   awsClient,
-		K8sClient:   k8sclient.NewClient(mgr.GetClient()),
+		Client:                          mgr.GetClient(),
+		Scheme:                          mgr.GetScheme(),
+		Log:                             ctrl.Log.WithName("controllers").WithName("ServiceAccount"),
+		AWSRegion:                       cfg.AWSRegion,
+		ClusterName:                     cfg.ClusterName,
+		AWSClient:                       awsClient,
+		K8sClient:                       k8sclient.NewClient(mgr.GetClient()),
+		ErrorHandler:                    errorHandler,
+		MaxConcurrentReconciles:         cfg.Controller.MaxConcurrentReconciles,
+		RateLimiter:                     errorHandler.ControllerRateLimiter(),
+		Scope:                           scope,
+		NamespaceTerminationConcurrency: cfg.NamespaceTermination.Concurrency,
+		NamespaceTerminationTimeout:     cfg.NamespaceTermination.Timeout.Duration,
+		ShutdownGracePeriod:             cfg.Controller.ShutdownGracePeriod.Duration,
+		OrphanSweepInterval:             cfg.Controller.OrphanSweepInterval.Duration,
+		RoleDefaults: controller.RoleDefaults{
+			RoleArn:       cfg.RoleDefaults.Role,
+			AssumeRoleArn: cfg.RoleDefaults.AssumeRole,
+		},
+		AccountRegistry: accountRegistry,
+		Restarter: rollout.NewRestarter(mgr.GetAPIReader(), mgr.GetClient(),
+			mgr.GetEventRecorderFor("pia-operator"), cfg.Rollout.MaxRestartsPerMinute, ctrl.Log.WithName("rollout")),
+		RolloutEnabled: func() bool { return store.Get().FeatureEnabled(config.FeatureWorkloadRollout) },
+	}
+
+	if cfg.RoleProvisioning.Enabled {
+		provisioner, err := iamrole.NewProvisioner(ctx, cfg.AWSRegion, iamrole.Options{
+			ClusterName:         cfg.ClusterName,
+			NameTemplate:        cfg.RoleProvisioning.NameTemplate,
+			Path:                cfg.RoleProvisioning.Path,
+			PermissionsBoundary: cfg.RoleProvisioning.PermissionsBoundary,
+			DeletionPolicy:      cfg.RoleProvisioning.DeletionPolicy,
+		}, ctrl.Log.WithName("iamrole"))
+		if err != nil {
+			setupLog.Error(err, "unable to create IAM role provisioner")
+			os.Exit(1)
+		}
+		reconciler.RoleProvisioner = provisioner
+	}
+
+	if cfg.TrustPolicy.Enabled {
+		trustManager, err := trustpolicy.NewManager(ctx, cfg.AWSRegion, trustpolicy.Options{
+			ClusterName:    cfg.ClusterName,
+			AccessRoleName: cfg.TrustPolicy.AccessRoleName,
+		}, ctrl.Log.WithName("trustpolicy"))
+		if err != nil {
+			setupLog.Error(err, "unable to create trust policy manager")
+			os.Exit(1)
+		}
+		reconciler.TrustManager = trustManager
+	}
+
+	if cfg.Webhook.PodMode != "" {
+		mode := podgate.Mode(cfg.Webhook.PodMode)
+		mgr.GetWebhookServer().Register(podgate.WebhookPath, &webhook.Admission{
+			Handler: podgate.NewWebhook(mgr.GetClient(), mgr.GetScheme(), mode, controller.AssociationPending, ctrl.Log.WithName("webhook").WithName("Pod")),
+		})
+		if mode == podgate.ModeReadinessGate {
+			reconciler.PodGate = podgate.NewReadinessGate(mgr.GetAPIReader(), mgr.GetClient().Status(), ctrl.Log.WithName("podgate"))
+		}
+	}
+
+	if cfg.Sharding.Enabled {
+		membership, err := newShardMembership(cfg)
+		if err != nil {
+			setupLog.Error(err, "unable to set up shard membership")
+			os.Exit(1)
+		}
+		if err := mgr.Add(membership); err != nil {
+			setupLog.Error(err, "unable to add shard membership")
+			os.Exit(1)
+		}
+		reconciler.Shard = membership
 	}
 
 	if err = reconciler.SetupWithManager(mgr); err != nil {
@@ -97,18 +278,99 @@ func main() {
 		os.Exit(1)
 	}
 
+	if configFile != "" {
+		if err := mgr.Add(config.NewWatcher(configFile, flag.CommandLine, store, ctrl.Log.WithName("config"))); err != nil {
+			setupLog.Error(err, "unable to set up configuration watcher")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	workqueueCheck := health.NewWorkqueueCheck(ctrlmetrics.Registry, cfg.Health.WorkqueueStallTimeout.Duration)
+	if err := mgr.AddHealthzCheck("workqueue", workqueueCheck.Check); err != nil {
+		setupLog.Error(err, "unable to set up workqueue health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	awsCheck := health.NewCachedCheck(awsClient.CheckAccess, cfg.Health.ReadinessCheckInterval.Duration, awsCheckTimeout)
+	if err := mgr.AddReadyzCheck("aws", awsCheck.Check); err != nil {
+		setupLog.Error(err, "unable to set up AWS ready check")
+		os.Exit(1)
+	}
 
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	setupLog.Info("starting manager", "clusterName", cfg.ClusterName, "region", cfg.AWSRegion)
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// discoverCluster fills in the cluster name and region that are not configured from the environment,
+// the kube-system ConfigMaps and the instance metadata of the node
+func discoverCluster(ctx context.Context, known discovery.Cluster) (discovery.Cluster, error) {
+	reader, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return known, fmt.Errorf("failed to create client: %w", err)
+	}
+	cluster := discovery.Discover(ctx, known, ctrl.Log.WithName("discovery"),
+		discovery.NewEnvSource(),
+		discovery.NewConfigMapSource(reader),
+		discovery.NewMetadataSource(),
+	)
+	if cluster.Name == "" {
+		return cluster, fmt.Errorf("cluster name not found, set --cluster-name")
+	}
+	if cluster.Region == "" {
+		return cluster, fmt.Errorf("AWS region not found, set --aws-region")
+	}
+	return cluster, nil
+}
+
+// retryPolicy converts the configured retry policy into the error handler's representation
+func retryPolicy(cfg *config.OperatorConfig) errorhandling.RetryPolicy {
+	return errorhandling.RetryPolicy{
+		MaxAttempts: cfg.RetryPolicy.MaxAttempts,
+		BaseDelay:   cfg.RetryPolicy.BaseDelay.Duration,
+		MaxDelay:    cfg.RetryPolicy.MaxDelay.Duration,
+	}
+}
+
+// newShardMembership creates the shard membership of this replica. Leases are read and written
+// directly against the API server so that the membership never lags behind the cache.
+func newShardMembership(cfg *config.OperatorConfig) (*sharding.Membership, error) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine shard identity: %w", err)
+		}
+		identity = hostname
+	}
+
+	namespace := cfg.Sharding.LeaseNamespace
+	if namespace == "" {
+		namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("shard lease namespace is not set, use --shard-lease-namespace or POD_NAMESPACE")
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shard membership client: %w", err)
+	}
+
+	return sharding.NewMembership(c, sharding.Options{
+		Identity:      identity,
+		Namespace:     namespace,
+		Group:         cfg.Sharding.Group,
+		LeaseDuration: cfg.Sharding.LeaseDuration.Duration,
+		RenewInterval: cfg.Sharding.RenewInterval.Duration,
+	}, ctrl.Log.WithName("sharding")), nil
+}
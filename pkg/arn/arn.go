@@ -0,0 +1,120 @@
+// Package arn parses and validates the IAM role ARNs used by Pod Identity Associations.
+//
+// Roles are only usable by an association in the partition of its cluster, so role ARNs are
+// checked against the partition derived from the operator's region: aws for the commercial
+// regions, aws-cn for the China regions and aws-us-gov for GovCloud.
+package arn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	awsarn "github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+const (
+	// PartitionAWS is the partition of the commercial regions
+	PartitionAWS = "aws"
+	// PartitionChina is the partition of the China regions
+	PartitionChina = "aws-cn"
+	// PartitionGovCloud is the partition of the AWS GovCloud (US) regions
+	PartitionGovCloud = "aws-us-gov"
+)
+
+var (
+	partitions       = map[string]bool{PartitionAWS: true, PartitionChina: true, PartitionGovCloud: true}
+	accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+	// roleNamePattern is the IAM role name syntax, see the IAM CreateRole API
+	roleNamePattern = regexp.MustCompile(`^[\w+=,.@-]{1,64}$`)
+)
+
+// Role is a parsed IAM role ARN
+type Role struct {
+	Partition string
+	AccountID string
+	// Path is the IAM path of the role, "/" when the role has none
+	Path string
+	Name string
+}
+
+// String returns the ARN of the role
+func (r Role) String() string {
+	return fmt.Sprintf("arn:%s:iam::%s:role%s%s", r.Partition, r.AccountID, r.Path, r.Name)
+}
+
+// PartitionForRegion returns the partition the region belongs to
+func PartitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionChina
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionGovCloud
+	}
+	return PartitionAWS
+}
+
+// IsPartition reports whether the partition is one the operator supports
+func IsPartition(partition string) bool {
+	return partitions[partition]
+}
+
+// ServicePrincipal returns the principal of the AWS service in the partition, such as
+// pods.eks.amazonaws.com, which ends in amazonaws.com.cn in the China regions
+func ServicePrincipal(partition, service string) string {
+	if partition == PartitionChina {
+		return service + ".amazonaws.com.cn"
+	}
+	return service + ".amazonaws.com"
+}
+
+// RoleARN returns the ARN of the role with the given name in the account
+func RoleARN(partition, accountID, name string) string {
+	return Role{Partition: partition, AccountID: accountID, Path: "/", Name: name}.String()
+}
+
+// ParseRole parses an IAM role ARN
+func ParseRole(value string) (Role, error) {
+	parsed, err := awsarn.Parse(value)
+	if err != nil {
+		return Role{}, fmt.Errorf("%q is not an ARN: %w", value, err)
+	}
+	switch {
+	case !partitions[parsed.Partition]:
+		return Role{}, fmt.Errorf("role ARN %q has unknown partition %q", value, parsed.Partition)
+	case parsed.Service != "iam":
+		return Role{}, fmt.Errorf("%q is not an IAM ARN", value)
+	case parsed.Region != "":
+		return Role{}, fmt.Errorf("role ARN %q must not have a region", value)
+	case !accountIDPattern.MatchString(parsed.AccountID):
+		return Role{}, fmt.Errorf("role ARN %q has invalid account ID %q", value, parsed.AccountID)
+	}
+
+	resource, ok := strings.CutPrefix(parsed.Resource, "role/")
+	if !ok {
+		return Role{}, fmt.Errorf("%q is not a role ARN", value)
+	}
+	slash := strings.LastIndex(resource, "/")
+	role := Role{
+		Partition: parsed.Partition,
+		AccountID: parsed.AccountID,
+		Path:      "/" + resource[:slash+1],
+		Name:      resource[slash+1:],
+	}
+	if !roleNamePattern.MatchString(role.Name) {
+		return Role{}, fmt.Errorf("role ARN %q has invalid role name %q", value, role.Name)
+	}
+	return role, nil
+}
+
+// ValidateRole parses an IAM role ARN and checks that the role is in the given partition
+func ValidateRole(value, partition string) (Role, error) {
+	role, err := ParseRole(value)
+	if err != nil {
+		return Role{}, err
+	}
+	if role.Partition != partition {
+		return Role{}, fmt.Errorf("role %q is in partition %s but the cluster is in partition %s", value, role.Partition, partition)
+	}
+	return role, nil
+}
@@ -0,0 +1,13 @@
+package arn_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestARN(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ARN Suite")
+}
@@ -0,0 +1,76 @@
+package arn_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/irenedo/pia-operator/pkg/arn"
+)
+
+var _ = Describe("ARN", func() {
+	Describe("PartitionForRegion", func() {
+		It("should return the partition of the region", func() {
+			Expect(arn.PartitionForRegion("eu-west-1")).To(Equal(arn.PartitionAWS))
+			Expect(arn.PartitionForRegion("cn-north-1")).To(Equal(arn.PartitionChina))
+			Expect(arn.PartitionForRegion("us-gov-west-1")).To(Equal(arn.PartitionGovCloud))
+		})
+	})
+
+	Describe("ParseRole", func() {
+		It("should parse a role ARN with a path", func() {
+			role, err := arn.ParseRole("arn:aws-us-gov:iam::123456789012:role/teams/payments/reader")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role).To(Equal(arn.Role{Partition: "aws-us-gov", AccountID: "123456789012", Path: "/teams/payments/", Name: "reader"}))
+			Expect(role.String()).To(Equal("arn:aws-us-gov:iam::123456789012:role/teams/payments/reader"))
+		})
+
+		It("should parse a role ARN without a path", func() {
+			role, err := arn.ParseRole("arn:aws-cn:iam::123456789012:role/reader")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.Path).To(Equal("/"))
+			Expect(role.String()).To(Equal("arn:aws-cn:iam::123456789012:role/reader"))
+		})
+
+		DescribeTable("should reject invalid role ARNs",
+			func(value, problem string) {
+				_, err := arn.ParseRole(value)
+				Expect(err).To(MatchError(ContainSubstring(problem)))
+			},
+			Entry("not an ARN", "reader", "is not an ARN"),
+			Entry("unknown partition", "arn:aws-iso:iam::123456789012:role/reader", "unknown partition"),
+			Entry("other service", "arn:aws:s3:::bucket", "is not an IAM ARN"),
+			Entry("with a region", "arn:aws:iam:eu-west-1:123456789012:role/reader", "must not have a region"),
+			Entry("invalid account", "arn:aws:iam::1234:role/reader", "invalid account ID"),
+			Entry("user ARN", "arn:aws:iam::123456789012:user/alice", "is not a role ARN"),
+			Entry("invalid role name", "arn:aws:iam::123456789012:role/read*er", "invalid role name"),
+		)
+	})
+
+	Describe("ValidateRole", func() {
+		It("should accept a role in the partition", func() {
+			_, err := arn.ValidateRole("arn:aws-cn:iam::123456789012:role/reader", arn.PartitionChina)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject a role of another partition", func() {
+			_, err := arn.ValidateRole("arn:aws:iam::123456789012:role/reader", arn.PartitionGovCloud)
+			Expect(err).To(MatchError(ContainSubstring("is in partition aws but the cluster is in partition aws-us-gov")))
+		})
+	})
+
+	Describe("RoleARN", func() {
+		It("should build the ARN of a role", func() {
+			Expect(arn.RoleARN(arn.PartitionGovCloud, "123456789012", "access")).To(Equal("arn:aws-us-gov:iam::123456789012:role/access"))
+		})
+	})
+
+	Describe("ServicePrincipal", func() {
+		It("should return the principal of the service in the partition", func() {
+			Expect(arn.ServicePrincipal(arn.PartitionAWS, "pods.eks")).To(Equal("pods.eks.amazonaws.com"))
+			Expect(arn.ServicePrincipal(arn.PartitionGovCloud, "pods.eks")).To(Equal("pods.eks.amazonaws.com"))
+			Expect(arn.ServicePrincipal(arn.PartitionChina, "pods.eks")).To(Equal("pods.eks.amazonaws.com.cn"))
+		})
+	})
+})
@@ -2,9 +2,13 @@ package awsclient
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -18,49 +22,70 @@ import (
 
 // Client implements the AWSClient interface using AWS SDK
 type Client struct {
-	eksClient   *eks.Client
+	eksClient   EKSAPI
 	clusterName string
 	region      string
 	log         logr.Logger
+	tagSource   func() map[string]string
+	// credentials are resolved by CheckAccess, nil when the client was created with an API
+	credentials aws.CredentialsProvider
 	KubeClient  k8sclient.DefaultServiceAccountClient
 }
 
+// Option configures optional behavior of the Client
+type Option func(*Client)
+
+// WithTagSource sets a function returning extra tags to add to every created association.
+// The function is called on each create so that configuration reloads apply without a restart.
+func WithTagSource(tags func() map[string]string) Option {
+	return func(c *Client) {
+		c.tagSource = tags
+	}
+}
+
 // NewClient creates a new AWS Pod Identity client
-func NewClient(ctx context.Context, clusterName, region string, log logr.Logger) (AWSClient, error) {
+func NewClient(ctx context.Context, clusterName, region string, log logr.Logger, opts ...Option) (AWSClient, error) {
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	eksClient := eks.NewFromConfig(cfg)
+	c := NewClientWithAPI(eks.NewFromConfig(cfg), clusterName, region, log, opts...)
+	c.credentials = cfg.Credentials
+	return c, nil
+}
 
+// NewClientWithAPI creates a new AWS Pod Identity client using the given EKS API
+func NewClientWithAPI(api EKSAPI, clusterName, region string, log logr.Logger, opts ...Option) *Client {
 	// kubeClient must be injected after construction
-	return &Client{
-		eksClient:   eksClient,
+	c := &Client{
+		eksClient:   api,
 		clusterName: clusterName,
 		region:      region,
 		log:         log,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // CreatePodIdentityAssociation creates a new AWS EKS Pod Identity Association that allows
 // a Kubernetes ServiceAccount to assume an IAM role without long-lived credentials.
 // Returns the association ID which is stored in the ServiceAccount's annotations.
-func (c *Client) CreatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool) (string, error) {
+func (c *Client) CreatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session SessionOptions) (string, error) {
 	log := c.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "operation", "create")
 
 	input := &eks.CreatePodIdentityAssociationInput{
 		ClusterName:        aws.String(c.clusterName),
 		Namespace:          aws.String(sa.Namespace),
 		ServiceAccount:     aws.String(sa.Name),
-		RoleArn:            aws.String(roleArn),       // Base role always goes to RoleArn
-		DisableSessionTags: aws.Bool(!taggingEnabled), // If tagging is disabled, disable session tags
-		Tags: map[string]string{
-			"managed-by":     "pia-operator",
-			"serviceaccount": sa.Name,
-			"namespace":      sa.Namespace,
-			"base-role":      roleArn,
-		},
+		RoleArn:            aws.String(roleArn),               // Base role always goes to RoleArn
+		DisableSessionTags: aws.Bool(!session.TaggingEnabled), // If tagging is disabled, disable session tags
+		Tags:               c.associationTags(sa, roleArn),
+	}
+	if session.Policy != "" {
+		input.Policy = aws.String(session.Policy)
 	}
 
 	// Set target role if assume role is provided
@@ -68,19 +93,21 @@ func (c *Client) CreatePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 		input.TargetRoleArn = aws.String(assumeRoleArn)
 		input.Tags["assume-role"] = assumeRoleArn
 	}
+	input.ClientRequestToken = aws.String(clientRequestToken(sa, input))
 
 	log.Info("Creating Pod Identity Association", "roleArn", roleArn, "targetRoleArn", assumeRoleArn, "clusterName", c.clusterName)
 
 	result, err := c.eksClient.CreatePodIdentityAssociation(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Pod Identity Association: %w", err)
+		err = wrapClusterError("create", err)
+		if errors.Is(err, ErrConflict) {
+			return c.adoptPodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session, err)
+		}
+		return "", err
 	}
 
 	associationID := aws.ToString(result.Association.AssociationId)
-	if sa.Annotations == nil {
-		sa.Annotations = make(map[string]string)
-	}
-	sa.Annotations["pia-operator.eks.aws.com/association-id"] = associationID
+	setAssociationID(sa, associationID)
 
 	log.Info("Successfully created Pod Identity Association",
 		"associationID", associationID)
@@ -88,10 +115,30 @@ func (c *Client) CreatePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 	return associationID, nil
 }
 
+// adoptPodIdentityAssociation takes over the association that already exists for the ServiceAccount
+// when creating one failed with ResourceInUseException, such as after its ID annotation was lost.
+// The association is updated in place with the requested roles and its ID is recorded again.
+func (c *Client) adoptPodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session SessionOptions, createErr error) (string, error) {
+	log := c.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "operation", "adopt")
+
+	association, err := c.findAssociationByServiceAccount(ctx, sa)
+	if err != nil {
+		if errors.Is(err, ErrAssociationNotFound) {
+			// The conflicting association is being deleted; the retry creates it again
+			return "", createErr
+		}
+		return "", fmt.Errorf("failed to find conflicting association: %w", err)
+	}
+
+	log.Info("Pod Identity Association already exists, adopting it", "associationID", association.ID)
+	setAssociationID(sa, association.ID)
+	return c.UpdatePodIdentityAssociation(ctx, sa, roleArn, assumeRoleArn, session)
+}
+
 // UpdatePodIdentityAssociation updates an existing AWS EKS Pod Identity Association with new role ARNs.
 // It finds the association by ID from ServiceAccount annotations or by searching all associations.
 // Returns the association ID after successful update.
-func (c *Client) UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool) (string, error) {
+func (c *Client) UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session SessionOptions) (string, error) {
 	log := c.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "operation", "update")
 
 	// Get the existing association ID
@@ -108,13 +155,10 @@ func (c *Client) UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 	input := &eks.UpdatePodIdentityAssociationInput{
 		ClusterName:        aws.String(c.clusterName),
 		AssociationId:      aws.String(associationID),
-		RoleArn:            aws.String(roleArn),       // Base role always goes to RoleArn
-		DisableSessionTags: aws.Bool(!taggingEnabled), // If tagging is disabled, disable session tags
-	}
-
-	// Set target role if assume role is provided
-	if assumeRoleArn != "" {
-		input.TargetRoleArn = aws.String(assumeRoleArn)
+		RoleArn:            aws.String(roleArn),               // Base role always goes to RoleArn
+		TargetRoleArn:      aws.String(assumeRoleArn),         // An empty target role removes the previous one
+		DisableSessionTags: aws.Bool(!session.TaggingEnabled), // If tagging is disabled, disable session tags
+		Policy:             aws.String(session.Policy),        // An empty policy removes the previous one
 	}
 
 	log.Info("Updating Pod Identity Association",
@@ -125,7 +169,7 @@ func (c *Client) UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 
 	_, err := c.eksClient.UpdatePodIdentityAssociation(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to update Pod Identity Association: %w", err)
+		return "", WrapError("update", err)
 	}
 
 	c.log.Info("Successfully updated Pod Identity Association",
@@ -145,7 +189,7 @@ func (c *Client) DeletePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 		association, err := c.findAssociationByServiceAccount(ctx, sa)
 		if err != nil {
 			// If association doesn't exist, consider it already deleted
-			if c.isNotFoundError(err) {
+			if errors.Is(err, ErrAssociationNotFound) {
 				c.log.Info("Pod Identity Association not found, considering it already deleted")
 				return nil
 			}
@@ -165,10 +209,10 @@ func (c *Client) DeletePodIdentityAssociation(ctx context.Context, sa *corev1.Se
 
 	_, err := c.eksClient.DeletePodIdentityAssociation(ctx, input)
 	if err != nil {
-		if c.isNotFoundError(err) {
+		if err = WrapError("delete", err); errors.Is(err, ErrAssociationNotFound) {
 			return nil
 		}
-		return fmt.Errorf("failed to delete Pod Identity Association: %w", err)
+		return err
 	}
 
 	return nil
@@ -188,10 +232,7 @@ func (c *Client) GetPodIdentityAssociation(ctx context.Context, sa *corev1.Servi
 
 		result, err := c.eksClient.DescribePodIdentityAssociation(ctx, input)
 		if err != nil {
-			if c.isNotFoundError(err) {
-				return nil, errors.New("Pod Identity Association not found")
-			}
-			return nil, fmt.Errorf("failed to describe Pod Identity Association: %w", err)
+			return nil, WrapError("describe", err)
 		}
 
 		return c.convertToAssociation(result.Association), nil
@@ -201,11 +242,50 @@ func (c *Client) GetPodIdentityAssociation(ctx context.Context, sa *corev1.Servi
 	return c.findAssociationByServiceAccount(ctx, sa)
 }
 
+// CheckAssociationReady describes the association once after a create or update and reports it as
+// CREATING until it is visible with the expected roles and session policy. EKS applies writes to Pod
+// Identity Associations asynchronously, so right after a write the association may not be found yet
+// or may still report the previous roles or policy. The caller requeues while it is not ready, instead
+// of blocking a reconcile worker.
+func (c *Client) CheckAssociationReady(ctx context.Context, associationID, roleArn, assumeRoleArn string, session SessionOptions) (*PodIdentityAssociation, error) {
+	log := c.log.WithValues("associationID", associationID, "operation", "check")
+
+	pending := &PodIdentityAssociation{
+		ID:          associationID,
+		ClusterName: c.clusterName,
+		Status:      string(AssociationStatusCreating),
+	}
+	result, err := c.eksClient.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+		ClusterName:   aws.String(c.clusterName),
+		AssociationId: aws.String(associationID),
+	})
+	if err != nil {
+		if err = WrapError("describe", err); errors.Is(err, ErrAssociationNotFound) {
+			log.V(1).Info("Pod Identity Association not visible yet")
+			return pending, nil
+		}
+		return nil, err
+	}
+
+	association := c.convertToAssociation(result.Association)
+	if association.RoleArn != roleArn || association.TargetRoleArn != assumeRoleArn {
+		log.V(1).Info("Pod Identity Association does not report the requested roles yet",
+			"roleArn", association.RoleArn, "targetRoleArn", association.TargetRoleArn)
+		association.Status = string(AssociationStatusCreating)
+	} else if !SamePolicy(association.SessionPolicy, session.Policy) {
+		log.V(1).Info("Pod Identity Association does not report the requested session policy yet")
+		association.Status = string(AssociationStatusCreating)
+	}
+
+	log.Info("Pod Identity Association readiness", "status", association.Status)
+	return association, nil
+}
+
 // AssociationExists checks if a Pod Identity Association exists for the given ServiceAccount
 func (c *Client) AssociationExists(ctx context.Context, sa *corev1.ServiceAccount) (bool, error) {
 	_, err := c.GetPodIdentityAssociation(ctx, sa)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, ErrAssociationNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -215,31 +295,59 @@ func (c *Client) AssociationExists(ctx context.Context, sa *corev1.ServiceAccoun
 
 // ListPodIdentityAssociations lists all Pod Identity Associations for the cluster
 func (c *Client) ListPodIdentityAssociations(ctx context.Context) ([]*PodIdentityAssociation, error) {
-	input := &eks.ListPodIdentityAssociationsInput{
+	associations, err := c.listPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
 		ClusterName: aws.String(c.clusterName),
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	c.log.Info("Listed Pod Identity Associations", "count", len(associations))
+	return associations, nil
+}
+
+func (c *Client) listPodIdentityAssociations(ctx context.Context, input *eks.ListPodIdentityAssociationsInput) ([]*PodIdentityAssociation, error) {
 	var associations []*PodIdentityAssociation
 	paginator := eks.NewListPodIdentityAssociationsPaginator(c.eksClient, input)
 
 	for paginator.HasMorePages() {
 		result, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list Pod Identity Associations: %w", err)
+			return nil, wrapClusterError("list", err)
 		}
 
 		for _, assoc := range result.Associations {
 			associations = append(associations, c.convertToAssociationSummary(&assoc))
 		}
 	}
-
-	c.log.Info("Listed Pod Identity Associations", "count", len(associations))
 	return associations, nil
 }
 
-// findAssociationByServiceAccount finds an association by service account details
+// CheckAccess checks that AWS credentials resolve and that the associations of the cluster can be
+// listed, with the cheapest possible list call
+func (c *Client) CheckAccess(ctx context.Context) error {
+	if c.credentials != nil {
+		if _, err := c.credentials.Retrieve(ctx); err != nil {
+			return fmt.Errorf("failed to resolve AWS credentials: %w", err)
+		}
+	}
+	if _, err := c.eksClient.ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
+		ClusterName: aws.String(c.clusterName),
+		MaxResults:  aws.Int32(1),
+	}); err != nil {
+		return wrapClusterError("list", err)
+	}
+	return nil
+}
+
+// findAssociationByServiceAccount finds an association by service account details,
+// listing only the associations of its namespace and name
 func (c *Client) findAssociationByServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) (*PodIdentityAssociation, error) {
-	associations, err := c.ListPodIdentityAssociations(ctx)
+	associations, err := c.listPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
+		ClusterName:    aws.String(c.clusterName),
+		Namespace:      aws.String(sa.Namespace),
+		ServiceAccount: aws.String(sa.Name),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -250,24 +358,65 @@ func (c *Client) findAssociationByServiceAccount(ctx context.Context, sa *corev1
 		}
 	}
 
-	return nil, fmt.Errorf("Pod Identity Association not found for ServiceAccount %s/%s", sa.Namespace, sa.Name)
+	return nil, fmt.Errorf("%w for ServiceAccount %s/%s", ErrAssociationNotFound, sa.Namespace, sa.Name)
 }
 
-// isNotFoundError checks if the error is a not found error
-func (c *Client) isNotFoundError(err error) bool {
-	if err == nil {
-		return false
+// clientRequestToken derives the idempotency token of a create from the UID of the ServiceAccount,
+// its request nonce and every parameter of the request, tags included. A create retried after its
+// response was lost, such as when the operator stopped before recording the association ID, returns
+// the association created the first time, while a create asking for anything different gets a token
+// of its own. The nonce is replaced whenever the association is deleted, so that creating it again
+// does not return the deleted association.
+func clientRequestToken(sa *corev1.ServiceAccount, input *eks.CreatePodIdentityAssociationInput) string {
+	fields := []string{
+		string(sa.UID), sa.Annotations["pia-operator.eks.aws.com/request-nonce"],
+		aws.ToString(input.ClusterName), aws.ToString(input.Namespace), aws.ToString(input.ServiceAccount),
+		aws.ToString(input.RoleArn), aws.ToString(input.TargetRoleArn),
+		fmt.Sprint(aws.ToBool(input.DisableSessionTags)), aws.ToString(input.Policy),
+	}
+	keys := make([]string, 0, len(input.Tags))
+	for key := range input.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, key, input.Tags[key])
 	}
 
-	var resourceNotFound *types.ResourceNotFoundException
-	if errors.As(err, &resourceNotFound) {
-		return true
+	hash := sha256.New()
+	for _, field := range fields {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
 	}
+	// EKS accepts tokens of up to 64 characters
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// setAssociationID records the ID of the association on the ServiceAccount
+func setAssociationID(sa *corev1.ServiceAccount, associationID string) {
+	if sa.Annotations == nil {
+		sa.Annotations = make(map[string]string)
+	}
+	sa.Annotations["pia-operator.eks.aws.com/association-id"] = associationID
+}
 
-	return strings.Contains(err.Error(), "ResourceNotFoundException") ||
-		strings.Contains(err.Error(), "not found") ||
-		strings.Contains(err.Error(), "NotFound") ||
-		strings.Contains(strings.ToLower(err.Error()), "notfound")
+// associationTags returns the configured extra tags merged with the tags the operator always sets.
+// Operator tags win on key collisions so associations can always be traced back to their ServiceAccount.
+func (c *Client) associationTags(sa *corev1.ServiceAccount, roleArn string) map[string]string {
+	tags := make(map[string]string)
+	if c.tagSource != nil {
+		for k, v := range c.tagSource() {
+			tags[k] = v
+		}
+	}
+	tags["managed-by"] = "pia-operator"
+	tags["serviceaccount"] = sa.Name
+	tags["namespace"] = sa.Namespace
+	tags["base-role"] = roleArn
+	if sa.UID != "" {
+		tags["serviceaccount-uid"] = string(sa.UID)
+	}
+	return tags
 }
 
 // convertToAssociation converts AWS PodIdentityAssociation to our struct
@@ -278,13 +427,38 @@ func (c *Client) convertToAssociation(assoc *types.PodIdentityAssociation) *PodI
 		Namespace:          aws.ToString(assoc.Namespace),
 		ServiceAccountName: aws.ToString(assoc.ServiceAccount),
 		RoleArn:            aws.ToString(assoc.RoleArn),
+		TargetRoleArn:      aws.ToString(assoc.TargetRoleArn),
+		SessionPolicy:      aws.ToString(assoc.Policy),
 		Tags:               assoc.Tags,
-		Status:             "ACTIVE", // Default status since field doesn't exist in current SDK
+		Status:             string(associationStatus(assoc)),
 		CreatedAt:          convertTimeToString(assoc.CreatedAt),
 		ModifiedAt:         convertTimeToString(assoc.ModifiedAt),
 	}
 }
 
+// associationStatus derives the status of a described association. The EKS API has no lifecycle
+// status for Pod Identity Associations: once DescribePodIdentityAssociation returns it, it is in use.
+func associationStatus(assoc *types.PodIdentityAssociation) AssociationStatus {
+	if assoc == nil || aws.ToString(assoc.AssociationId) == "" || aws.ToString(assoc.RoleArn) == "" {
+		return AssociationStatusCreating
+	}
+	return AssociationStatusActive
+}
+
+// SamePolicy reports whether two JSON policy documents are equal regardless of formatting, since EKS
+// may not return a session policy byte for byte as it was sent. Documents that are not valid JSON are
+// compared as strings.
+func SamePolicy(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var docA, docB interface{}
+	if json.Unmarshal([]byte(a), &docA) != nil || json.Unmarshal([]byte(b), &docB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(docA, docB)
+}
+
 // convertToAssociationSummary converts AWS PodIdentityAssociationSummary to our struct
 func (c *Client) convertToAssociationSummary(assoc *types.PodIdentityAssociationSummary) *PodIdentityAssociation {
 	return &PodIdentityAssociation{
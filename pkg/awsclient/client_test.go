@@ -0,0 +1,250 @@
+package awsclient_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/awsclient"
+	mocks "github.com/irenedo/pia-operator/pkg/awsclient/mocks"
+)
+
+var _ = Describe("Client", func() {
+	const (
+		clusterName = "test-cluster"
+		roleArn     = "arn:aws:iam::123456789012:role/test-role"
+	)
+
+	var (
+		ctx     context.Context
+		mockEKS *mocks.MockEKSAPI
+		client  *awsclient.Client
+		sa      *corev1.ServiceAccount
+	)
+
+	inUse := &types.ResourceInUseException{Message: aws.String("Association already exists")}
+
+	// listedFor matches the filtered list call looking up the association of the ServiceAccount
+	listedFor := func(namespace, name string) interface{} {
+		return mock.MatchedBy(func(input *eks.ListPodIdentityAssociationsInput) bool {
+			return aws.ToString(input.ClusterName) == clusterName &&
+				aws.ToString(input.Namespace) == namespace &&
+				aws.ToString(input.ServiceAccount) == name
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockEKS = mocks.NewMockEKSAPI(GinkgoT())
+		client = awsclient.NewClientWithAPI(mockEKS, clusterName, "eu-west-1", log.Log.WithName("test"))
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sa",
+			Namespace: "default",
+		}}
+	})
+
+	Describe("CreatePodIdentityAssociation", func() {
+		It("should record the ID of the created association", func() {
+			mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).Return(&eks.CreatePodIdentityAssociationOutput{
+				Association: &types.PodIdentityAssociation{AssociationId: aws.String("a-new")},
+			}, nil)
+
+			associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(associationID).To(Equal("a-new"))
+			Expect(sa.Annotations).To(HaveKeyWithValue("pia-operator.eks.aws.com/association-id", "a-new"))
+		})
+
+		It("should send the same client request token when a create is retried", func() {
+			var tokens []string
+			mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tokens = append(tokens, aws.ToString(args.Get(1).(*eks.CreatePodIdentityAssociationInput).ClientRequestToken))
+			}).Return(&eks.CreatePodIdentityAssociationOutput{
+				Association: &types.PodIdentityAssociation{AssociationId: aws.String("a-new")},
+			}, nil)
+			tags := map[string]string{"team": "platform"}
+			client = awsclient.NewClientWithAPI(mockEKS, clusterName, "eu-west-1", log.Log.WithName("test"),
+				awsclient.WithTagSource(func() map[string]string { return tags }))
+			sa.UID, sa.ResourceVersion = "uid-1", "100"
+			create := func() {
+				_, err := client.CreatePodIdentityAssociation(ctx, sa.DeepCopy(), roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			create()
+			sa.ResourceVersion = "101"
+			create()
+			tags["team"] = "payments"
+			create()
+			sa.UID = "uid-2"
+			create()
+			sa.Annotations = map[string]string{"pia-operator.eks.aws.com/request-nonce": "recreated"}
+			create()
+
+			Expect(tokens).To(HaveLen(5))
+			Expect(tokens[0]).To(HaveLen(64))
+			Expect(tokens[1]).To(Equal(tokens[0]))
+			Expect(tokens[2]).ToNot(Equal(tokens[0]))
+			Expect(tokens[3]).ToNot(BeElementOf(tokens[:3]))
+			Expect(tokens[4]).ToNot(BeElementOf(tokens[:4]))
+		})
+
+		Context("when an association already exists for the ServiceAccount", func() {
+			It("should adopt and update the existing association", func() {
+				mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).Return(nil, inUse)
+				mockEKS.On("ListPodIdentityAssociations", ctx, listedFor("default", "test-sa"), mock.Anything).Return(&eks.ListPodIdentityAssociationsOutput{
+					Associations: []types.PodIdentityAssociationSummary{{
+						AssociationId:  aws.String("a-existing"),
+						ClusterName:    aws.String(clusterName),
+						Namespace:      aws.String("default"),
+						ServiceAccount: aws.String("test-sa"),
+					}},
+				}, nil)
+				mockEKS.On("UpdatePodIdentityAssociation", ctx, mock.MatchedBy(func(input *eks.UpdatePodIdentityAssociationInput) bool {
+					return aws.ToString(input.AssociationId) == "a-existing" &&
+						aws.ToString(input.RoleArn) == roleArn &&
+						aws.ToString(input.TargetRoleArn) == "arn:aws:iam::987654321098:role/target-role" &&
+						!aws.ToBool(input.DisableSessionTags)
+				})).Return(&eks.UpdatePodIdentityAssociationOutput{}, nil)
+
+				associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "arn:aws:iam::987654321098:role/target-role", awsclient.SessionOptions{TaggingEnabled: true})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(associationID).To(Equal("a-existing"))
+				Expect(sa.Annotations).To(HaveKeyWithValue("pia-operator.eks.aws.com/association-id", "a-existing"))
+			})
+
+			It("should return the conflict when the existing association is gone", func() {
+				mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).Return(nil, inUse)
+				mockEKS.On("ListPodIdentityAssociations", ctx, listedFor("default", "test-sa"), mock.Anything).
+					Return(&eks.ListPodIdentityAssociationsOutput{}, nil)
+
+				associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+
+				Expect(errors.Is(err, awsclient.ErrConflict)).To(BeTrue())
+				Expect(associationID).To(BeEmpty())
+				Expect(sa.Annotations).ToNot(HaveKey("pia-operator.eks.aws.com/association-id"))
+			})
+
+			It("should return the error of the update of the adopted association", func() {
+				mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).Return(nil, inUse)
+				mockEKS.On("ListPodIdentityAssociations", ctx, listedFor("default", "test-sa"), mock.Anything).Return(&eks.ListPodIdentityAssociationsOutput{
+					Associations: []types.PodIdentityAssociationSummary{{
+						AssociationId:  aws.String("a-existing"),
+						Namespace:      aws.String("default"),
+						ServiceAccount: aws.String("test-sa"),
+					}},
+				}, nil)
+				mockEKS.On("UpdatePodIdentityAssociation", ctx, mock.Anything).
+					Return(nil, &types.ThrottlingException{Message: aws.String("Rate exceeded")})
+
+				_, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+
+				Expect(errors.Is(err, awsclient.ErrThrottled)).To(BeTrue())
+			})
+		})
+
+		It("should not look up associations for other errors", func() {
+			mockEKS.On("CreatePodIdentityAssociation", ctx, mock.Anything).
+				Return(nil, &types.InvalidParameterException{Message: aws.String("invalid role")})
+
+			_, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, awsclient.ErrConflict)).To(BeFalse())
+			mockEKS.AssertNotCalled(GinkgoT(), "ListPodIdentityAssociations", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("CheckAssociationReady", func() {
+		const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+
+		describeWithPolicy := func(sessionPolicy string) {
+			mockEKS.On("DescribePodIdentityAssociation", mock.Anything, mock.Anything).Return(&eks.DescribePodIdentityAssociationOutput{
+				Association: &types.PodIdentityAssociation{
+					AssociationId: aws.String("a-12345"),
+					RoleArn:       aws.String(roleArn),
+					Policy:        aws.String(sessionPolicy),
+				},
+			}, nil)
+		}
+
+		It("should accept the session policy formatted differently", func() {
+			describeWithPolicy("{\n  \"Statement\": [{\"Resource\": \"*\", \"Action\": \"s3:GetObject\", \"Effect\": \"Allow\"}],\n  \"Version\": \"2012-10-17\"\n}")
+
+			association, err := client.CheckAssociationReady(ctx, "a-12345", roleArn, "", awsclient.SessionOptions{Policy: policy})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(association.Ready()).To(BeTrue())
+		})
+
+		It("should report an association with a different session policy as not ready", func() {
+			describeWithPolicy(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`)
+
+			association, err := client.CheckAssociationReady(ctx, "a-12345", roleArn, "", awsclient.SessionOptions{Policy: policy})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(association.Status).To(Equal(string(awsclient.AssociationStatusCreating)))
+			Expect(association.SessionPolicy).ToNot(BeEmpty())
+		})
+
+		It("should describe an association that is not visible yet once and report it as creating", func() {
+			mockEKS.On("DescribePodIdentityAssociation", mock.Anything, mock.Anything).
+				Return(nil, &types.ResourceNotFoundException{Message: aws.String("No Pod Identity Association found for id: a-12345.")})
+
+			association, err := client.CheckAssociationReady(ctx, "a-12345", roleArn, "", awsclient.SessionOptions{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(association.Status).To(Equal(string(awsclient.AssociationStatusCreating)))
+			mockEKS.AssertNumberOfCalls(GinkgoT(), "DescribePodIdentityAssociation", 1)
+		})
+
+		It("should report an association whose session policy was removed as not ready", func() {
+			describeWithPolicy("")
+
+			association, err := client.CheckAssociationReady(ctx, "a-12345", roleArn, "", awsclient.SessionOptions{Policy: policy})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(association.Ready()).To(BeFalse())
+		})
+	})
+
+	Describe("CheckAccess", func() {
+		It("should list at most one association of the cluster", func() {
+			mockEKS.On("ListPodIdentityAssociations", ctx, mock.MatchedBy(func(input *eks.ListPodIdentityAssociationsInput) bool {
+				return aws.ToString(input.ClusterName) == clusterName && aws.ToInt32(input.MaxResults) == 1
+			})).Return(&eks.ListPodIdentityAssociationsOutput{}, nil)
+
+			Expect(client.CheckAccess(ctx)).To(Succeed())
+		})
+
+		It("should report a cluster that does not exist", func() {
+			mockEKS.On("ListPodIdentityAssociations", ctx, mock.Anything).
+				Return(nil, &types.ResourceNotFoundException{Message: aws.String("No cluster found for name: test-cluster.")})
+
+			Expect(client.CheckAccess(ctx)).To(MatchError(ContainSubstring("cluster not found")))
+		})
+	})
+
+	Describe("AssociationExists", func() {
+		It("should only list the associations of the ServiceAccount", func() {
+			mockEKS.On("ListPodIdentityAssociations", ctx, listedFor("default", "test-sa"), mock.Anything).
+				Return(&eks.ListPodIdentityAssociationsOutput{}, nil)
+
+			exists, err := client.AssociationExists(ctx, sa)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package awsclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	// ErrAssociationNotFound is returned when the Pod Identity Association of a ServiceAccount does not exist
+	ErrAssociationNotFound = errors.New("Pod Identity Association not found")
+	// ErrConflict is returned when the association is in use or was changed concurrently
+	ErrConflict = errors.New("Pod Identity Association conflict")
+	// ErrThrottled is returned when the EKS API rejected the request because of its rate limits
+	ErrThrottled = errors.New("EKS API request throttled")
+	// ErrAccessDenied is returned when the operator is not allowed to call the EKS API, or its
+	// credentials are not recognized. Both can be fixed while the operator runs.
+	ErrAccessDenied = errors.New("EKS API access denied")
+)
+
+// Error is an EKS API error classified as one of the sentinel errors of this package.
+// errors.Is matches the sentinel, and errors.As still reaches the SDK error.
+type Error struct {
+	Operation string
+	Kind      error
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to %s Pod Identity Association: %v", e.Operation, e.Err)
+}
+
+// Is reports whether target is the sentinel error the EKS API error was classified as
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the SDK error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError classifies an error returned by the EKS API for operation. Errors that match none
+// of the sentinel errors are wrapped with fmt.Errorf.
+func WrapError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := classifyAPIError(err); kind != nil {
+		return &Error{Operation: operation, Kind: kind, Err: err}
+	}
+	return fmt.Errorf("failed to %s Pod Identity Association: %w", operation, err)
+}
+
+// wrapClusterError classifies an error of an operation on the cluster rather than on an existing
+// association, for which ResourceNotFoundException means that the cluster does not exist
+func wrapClusterError(operation string, err error) error {
+	if errors.Is(classifyAPIError(err), ErrAssociationNotFound) {
+		return fmt.Errorf("failed to %s Pod Identity Association, cluster not found: %w", operation, err)
+	}
+	return WrapError(operation, err)
+}
+
+// classifyAPIError maps the typed EKS exceptions, and the error codes of generic API errors
+// such as throttling, to the sentinel errors
+func classifyAPIError(err error) error {
+	var (
+		notFound     *types.ResourceNotFoundException
+		inUse        *types.ResourceInUseException
+		throttled    *types.ThrottlingException
+		accessDenied *types.AccessDeniedException
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return ErrAssociationNotFound
+	case errors.As(err, &inUse):
+		return ErrConflict
+	case errors.As(err, &throttled):
+		return ErrThrottled
+	case errors.As(err, &accessDenied):
+		return ErrAccessDenied
+	}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	switch apiErr.ErrorCode() {
+	case "Throttling", "ThrottlingException", "TooManyRequestsException", "RequestLimitExceeded":
+		return ErrThrottled
+	case "AccessDenied", "AccessDeniedException", "UnrecognizedClientException":
+		return ErrAccessDenied
+	case "ConcurrentModificationException":
+		return ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package awsclient_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/irenedo/pia-operator/pkg/awsclient"
+)
+
+var _ = Describe("WrapError", func() {
+	It("should return nil for a nil error", func() {
+		Expect(awsclient.WrapError("describe", nil)).To(Succeed())
+	})
+
+	DescribeTable("should classify EKS API errors",
+		func(apiErr error, sentinel error) {
+			err := awsclient.WrapError("describe", apiErr)
+
+			Expect(errors.Is(err, sentinel)).To(BeTrue())
+			Expect(errors.Is(err, apiErr)).To(BeTrue())
+		},
+		Entry("missing association", &types.ResourceNotFoundException{Message: aws.String("not found")}, awsclient.ErrAssociationNotFound),
+		Entry("association in use", &types.ResourceInUseException{Message: aws.String("in use")}, awsclient.ErrConflict),
+		Entry("throttling exception", &types.ThrottlingException{Message: aws.String("slow down")}, awsclient.ErrThrottled),
+		Entry("throttling error code", &smithy.GenericAPIError{Code: "TooManyRequestsException"}, awsclient.ErrThrottled),
+		Entry("access denied exception", &types.AccessDeniedException{Message: aws.String("denied")}, awsclient.ErrAccessDenied),
+		Entry("access denied error code", &smithy.GenericAPIError{Code: "AccessDenied"}, awsclient.ErrAccessDenied),
+	)
+
+	It("should keep the SDK error reachable with errors.As", func() {
+		err := awsclient.WrapError("delete", fmt.Errorf("operation error: %w", &types.ResourceNotFoundException{Message: aws.String("gone")}))
+
+		var notFound *types.ResourceNotFoundException
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+		Expect(aws.ToString(notFound.Message)).To(Equal("gone"))
+	})
+
+	It("should not treat errors mentioning not found as a missing association", func() {
+		err := awsclient.WrapError("update", &types.InvalidParameterException{Message: aws.String("role arn:aws:iam::123456789012:role/missing not found")})
+
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, awsclient.ErrAssociationNotFound)).To(BeFalse())
+		Expect(errors.Is(err, awsclient.ErrConflict)).To(BeFalse())
+	})
+})
@@ -20,17 +20,38 @@ package awsclient
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // AWSClient interface for Pod Identity operations (consolidated)
 type AWSClient interface {
-	CreatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool) (string, error)
-	UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, taggingEnabled bool) (string, error)
+	CreatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session SessionOptions) (string, error)
+	UpdatePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount, roleArn, assumeRoleArn string, session SessionOptions) (string, error)
 	DeletePodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount) error
 	AssociationExists(ctx context.Context, sa *corev1.ServiceAccount) (bool, error)
 	GetPodIdentityAssociation(ctx context.Context, sa *corev1.ServiceAccount) (*PodIdentityAssociation, error)
 	ListPodIdentityAssociations(ctx context.Context) ([]*PodIdentityAssociation, error)
+	CheckAssociationReady(ctx context.Context, associationID, roleArn, assumeRoleArn string, session SessionOptions) (*PodIdentityAssociation, error)
+	CheckAccess(ctx context.Context) error
+}
+
+// EKSAPI is the subset of the EKS API used by the Client
+type EKSAPI interface {
+	CreatePodIdentityAssociation(ctx context.Context, params *eks.CreatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error)
+	UpdatePodIdentityAssociation(ctx context.Context, params *eks.UpdatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.UpdatePodIdentityAssociationOutput, error)
+	DeletePodIdentityAssociation(ctx context.Context, params *eks.DeletePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DeletePodIdentityAssociationOutput, error)
+	DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error)
+	ListPodIdentityAssociations(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error)
+}
+
+// SessionOptions configures the role session of the pods using an association
+type SessionOptions struct {
+	// TaggingEnabled keeps the session tags EKS Pod Identity adds to the role session
+	TaggingEnabled bool
+	// Policy is a JSON session policy that restricts the permissions of the role session.
+	// EKS only accepts it with session tags disabled, and applies it to the target role when there is one.
+	Policy string
 }
 
 // PodIdentityAssociation represents a Pod Identity Association
@@ -40,6 +61,8 @@ type PodIdentityAssociation struct {
 	Namespace          string
 	ServiceAccountName string
 	RoleArn            string
+	TargetRoleArn      string
+	SessionPolicy      string
 	AssumeRolePolicy   string
 	Tags               map[string]string
 	Status             string
@@ -56,3 +79,8 @@ const (
 	AssociationStatusDeleting AssociationStatus = "DELETING"
 	AssociationStatusFailed   AssociationStatus = "FAILED"
 )
+
+// Ready reports whether the association can be used by pods
+func (a *PodIdentityAssociation) Ready() bool {
+	return a != nil && a.Status == string(AssociationStatusActive)
+}
@@ -105,9 +105,146 @@ func (_c *MockAWSClient_AssociationExists_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
+// CheckAccess provides a mock function for the type MockAWSClient
+func (_mock *MockAWSClient) CheckAccess(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckAccess")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAWSClient_CheckAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckAccess'
+type MockAWSClient_CheckAccess_Call struct {
+	*mock.Call
+}
+
+// CheckAccess is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAWSClient_Expecter) CheckAccess(ctx interface{}) *MockAWSClient_CheckAccess_Call {
+	return &MockAWSClient_CheckAccess_Call{Call: _e.mock.On("CheckAccess", ctx)}
+}
+
+func (_c *MockAWSClient_CheckAccess_Call) Run(run func(ctx context.Context)) *MockAWSClient_CheckAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAWSClient_CheckAccess_Call) Return(err error) *MockAWSClient_CheckAccess_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAWSClient_CheckAccess_Call) RunAndReturn(run func(ctx context.Context) error) *MockAWSClient_CheckAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckAssociationReady provides a mock function for the type MockAWSClient
+func (_mock *MockAWSClient) CheckAssociationReady(ctx context.Context, associationID string, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (*awsclient.PodIdentityAssociation, error) {
+	ret := _mock.Called(ctx, associationID, roleArn, assumeRoleArn, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckAssociationReady")
+	}
+
+	var r0 *awsclient.PodIdentityAssociation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, awsclient.SessionOptions) (*awsclient.PodIdentityAssociation, error)); ok {
+		return returnFunc(ctx, associationID, roleArn, assumeRoleArn, session)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, awsclient.SessionOptions) *awsclient.PodIdentityAssociation); ok {
+		r0 = returnFunc(ctx, associationID, roleArn, assumeRoleArn, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*awsclient.PodIdentityAssociation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, awsclient.SessionOptions) error); ok {
+		r1 = returnFunc(ctx, associationID, roleArn, assumeRoleArn, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAWSClient_CheckAssociationReady_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckAssociationReady'
+type MockAWSClient_CheckAssociationReady_Call struct {
+	*mock.Call
+}
+
+// CheckAssociationReady is a helper method to define mock.On call
+//   - ctx context.Context
+//   - associationID string
+//   - roleArn string
+//   - assumeRoleArn string
+//   - session awsclient.SessionOptions
+func (_e *MockAWSClient_Expecter) CheckAssociationReady(ctx interface{}, associationID interface{}, roleArn interface{}, assumeRoleArn interface{}, session interface{}) *MockAWSClient_CheckAssociationReady_Call {
+	return &MockAWSClient_CheckAssociationReady_Call{Call: _e.mock.On("CheckAssociationReady", ctx, associationID, roleArn, assumeRoleArn, session)}
+}
+
+func (_c *MockAWSClient_CheckAssociationReady_Call) Run(run func(ctx context.Context, associationID string, roleArn string, assumeRoleArn string, session awsclient.SessionOptions)) *MockAWSClient_CheckAssociationReady_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 awsclient.SessionOptions
+		if args[4] != nil {
+			arg4 = args[4].(awsclient.SessionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAWSClient_CheckAssociationReady_Call) Return(podIdentityAssociation *awsclient.PodIdentityAssociation, err error) *MockAWSClient_CheckAssociationReady_Call {
+	_c.Call.Return(podIdentityAssociation, err)
+	return _c
+}
+
+func (_c *MockAWSClient_CheckAssociationReady_Call) RunAndReturn(run func(ctx context.Context, associationID string, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (*awsclient.PodIdentityAssociation, error)) *MockAWSClient_CheckAssociationReady_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePodIdentityAssociation provides a mock function for the type MockAWSClient
-func (_mock *MockAWSClient) CreatePodIdentityAssociation(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool) (string, error) {
-	ret := _mock.Called(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+func (_mock *MockAWSClient) CreatePodIdentityAssociation(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (string, error) {
+	ret := _mock.Called(ctx, sa, roleArn, assumeRoleArn, session)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreatePodIdentityAssociation")
@@ -115,16 +252,16 @@ func (_mock *MockAWSClient) CreatePodIdentityAssociation(ctx context.Context, sa
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, bool) (string, error)); ok {
-		return returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) (string, error)); ok {
+		return returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, bool) string); ok {
-		r0 = returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) string); ok {
+		r0 = returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount, string, string, bool) error); ok {
-		r1 = returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) error); ok {
+		r1 = returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -141,12 +278,12 @@ type MockAWSClient_CreatePodIdentityAssociation_Call struct {
 //   - sa *v1.ServiceAccount
 //   - roleArn string
 //   - assumeRoleArn string
-//   - taggingEnabled bool
-func (_e *MockAWSClient_Expecter) CreatePodIdentityAssociation(ctx interface{}, sa interface{}, roleArn interface{}, assumeRoleArn interface{}, taggingEnabled interface{}) *MockAWSClient_CreatePodIdentityAssociation_Call {
-	return &MockAWSClient_CreatePodIdentityAssociation_Call{Call: _e.mock.On("CreatePodIdentityAssociation", ctx, sa, roleArn, assumeRoleArn, taggingEnabled)}
+//   - session awsclient.SessionOptions
+func (_e *MockAWSClient_Expecter) CreatePodIdentityAssociation(ctx interface{}, sa interface{}, roleArn interface{}, assumeRoleArn interface{}, session interface{}) *MockAWSClient_CreatePodIdentityAssociation_Call {
+	return &MockAWSClient_CreatePodIdentityAssociation_Call{Call: _e.mock.On("CreatePodIdentityAssociation", ctx, sa, roleArn, assumeRoleArn, session)}
 }
 
-func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool)) *MockAWSClient_CreatePodIdentityAssociation_Call {
+func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions)) *MockAWSClient_CreatePodIdentityAssociation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -164,9 +301,9 @@ func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) Run(run func(ctx cont
 		if args[3] != nil {
 			arg3 = args[3].(string)
 		}
-		var arg4 bool
+		var arg4 awsclient.SessionOptions
 		if args[4] != nil {
-			arg4 = args[4].(bool)
+			arg4 = args[4].(awsclient.SessionOptions)
 		}
 		run(
 			arg0,
@@ -184,7 +321,7 @@ func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) Return(s string, err
 	return _c
 }
 
-func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool) (string, error)) *MockAWSClient_CreatePodIdentityAssociation_Call {
+func (_c *MockAWSClient_CreatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (string, error)) *MockAWSClient_CreatePodIdentityAssociation_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -377,8 +514,8 @@ func (_c *MockAWSClient_ListPodIdentityAssociations_Call) RunAndReturn(run func(
 }
 
 // UpdatePodIdentityAssociation provides a mock function for the type MockAWSClient
-func (_mock *MockAWSClient) UpdatePodIdentityAssociation(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool) (string, error) {
-	ret := _mock.Called(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+func (_mock *MockAWSClient) UpdatePodIdentityAssociation(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (string, error) {
+	ret := _mock.Called(ctx, sa, roleArn, assumeRoleArn, session)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePodIdentityAssociation")
@@ -386,16 +523,16 @@ func (_mock *MockAWSClient) UpdatePodIdentityAssociation(ctx context.Context, sa
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, bool) (string, error)); ok {
-		return returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) (string, error)); ok {
+		return returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, bool) string); ok {
-		r0 = returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) string); ok {
+		r0 = returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount, string, string, bool) error); ok {
-		r1 = returnFunc(ctx, sa, roleArn, assumeRoleArn, taggingEnabled)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount, string, string, awsclient.SessionOptions) error); ok {
+		r1 = returnFunc(ctx, sa, roleArn, assumeRoleArn, session)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -412,12 +549,12 @@ type MockAWSClient_UpdatePodIdentityAssociation_Call struct {
 //   - sa *v1.ServiceAccount
 //   - roleArn string
 //   - assumeRoleArn string
-//   - taggingEnabled bool
-func (_e *MockAWSClient_Expecter) UpdatePodIdentityAssociation(ctx interface{}, sa interface{}, roleArn interface{}, assumeRoleArn interface{}, taggingEnabled interface{}) *MockAWSClient_UpdatePodIdentityAssociation_Call {
-	return &MockAWSClient_UpdatePodIdentityAssociation_Call{Call: _e.mock.On("UpdatePodIdentityAssociation", ctx, sa, roleArn, assumeRoleArn, taggingEnabled)}
+//   - session awsclient.SessionOptions
+func (_e *MockAWSClient_Expecter) UpdatePodIdentityAssociation(ctx interface{}, sa interface{}, roleArn interface{}, assumeRoleArn interface{}, session interface{}) *MockAWSClient_UpdatePodIdentityAssociation_Call {
+	return &MockAWSClient_UpdatePodIdentityAssociation_Call{Call: _e.mock.On("UpdatePodIdentityAssociation", ctx, sa, roleArn, assumeRoleArn, session)}
 }
 
-func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool)) *MockAWSClient_UpdatePodIdentityAssociation_Call {
+func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions)) *MockAWSClient_UpdatePodIdentityAssociation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -435,9 +572,9 @@ func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) Run(run func(ctx cont
 		if args[3] != nil {
 			arg3 = args[3].(string)
 		}
-		var arg4 bool
+		var arg4 awsclient.SessionOptions
 		if args[4] != nil {
-			arg4 = args[4].(bool)
+			arg4 = args[4].(awsclient.SessionOptions)
 		}
 		run(
 			arg0,
@@ -455,7 +592,7 @@ func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) Return(s string, err
 	return _c
 }
 
-func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, taggingEnabled bool) (string, error)) *MockAWSClient_UpdatePodIdentityAssociation_Call {
+func (_c *MockAWSClient_UpdatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, assumeRoleArn string, session awsclient.SessionOptions) (string, error)) *MockAWSClient_UpdatePodIdentityAssociation_Call {
 	_c.Call.Return(run)
 	return _c
 }
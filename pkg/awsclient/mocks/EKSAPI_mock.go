@@ -0,0 +1,454 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockEKSAPI creates a new instance of MockEKSAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEKSAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEKSAPI {
+	mock := &MockEKSAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockEKSAPI is an autogenerated mock type for the EKSAPI type
+type MockEKSAPI struct {
+	mock.Mock
+}
+
+type MockEKSAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEKSAPI) EXPECT() *MockEKSAPI_Expecter {
+	return &MockEKSAPI_Expecter{mock: &_m.Mock}
+}
+
+// CreatePodIdentityAssociation provides a mock function for the type MockEKSAPI
+func (_mock *MockEKSAPI) CreatePodIdentityAssociation(ctx context.Context, params *eks.CreatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePodIdentityAssociation")
+	}
+
+	var r0 *eks.CreatePodIdentityAssociationOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.CreatePodIdentityAssociationInput, ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.CreatePodIdentityAssociationInput, ...func(*eks.Options)) *eks.CreatePodIdentityAssociationOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eks.CreatePodIdentityAssociationOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *eks.CreatePodIdentityAssociationInput, ...func(*eks.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEKSAPI_CreatePodIdentityAssociation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePodIdentityAssociation'
+type MockEKSAPI_CreatePodIdentityAssociation_Call struct {
+	*mock.Call
+}
+
+// CreatePodIdentityAssociation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *eks.CreatePodIdentityAssociationInput
+//   - optFns ...func(*eks.Options)
+func (_e *MockEKSAPI_Expecter) CreatePodIdentityAssociation(ctx interface{}, params interface{}, optFns ...interface{}) *MockEKSAPI_CreatePodIdentityAssociation_Call {
+	return &MockEKSAPI_CreatePodIdentityAssociation_Call{Call: _e.mock.On("CreatePodIdentityAssociation",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEKSAPI_CreatePodIdentityAssociation_Call) Run(run func(ctx context.Context, params *eks.CreatePodIdentityAssociationInput, optFns ...func(*eks.Options))) *MockEKSAPI_CreatePodIdentityAssociation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *eks.CreatePodIdentityAssociationInput
+		if args[1] != nil {
+			arg1 = args[1].(*eks.CreatePodIdentityAssociationInput)
+		}
+		var arg2 []func(*eks.Options)
+		var variadicArgs []func(*eks.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*eks.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEKSAPI_CreatePodIdentityAssociation_Call) Return(createPodIdentityAssociationOutput *eks.CreatePodIdentityAssociationOutput, err error) *MockEKSAPI_CreatePodIdentityAssociation_Call {
+	_c.Call.Return(createPodIdentityAssociationOutput, err)
+	return _c
+}
+
+func (_c *MockEKSAPI_CreatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, params *eks.CreatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error)) *MockEKSAPI_CreatePodIdentityAssociation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePodIdentityAssociation provides a mock function for the type MockEKSAPI
+func (_mock *MockEKSAPI) DeletePodIdentityAssociation(ctx context.Context, params *eks.DeletePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DeletePodIdentityAssociationOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePodIdentityAssociation")
+	}
+
+	var r0 *eks.DeletePodIdentityAssociationOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.DeletePodIdentityAssociationInput, ...func(*eks.Options)) (*eks.DeletePodIdentityAssociationOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.DeletePodIdentityAssociationInput, ...func(*eks.Options)) *eks.DeletePodIdentityAssociationOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eks.DeletePodIdentityAssociationOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *eks.DeletePodIdentityAssociationInput, ...func(*eks.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEKSAPI_DeletePodIdentityAssociation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePodIdentityAssociation'
+type MockEKSAPI_DeletePodIdentityAssociation_Call struct {
+	*mock.Call
+}
+
+// DeletePodIdentityAssociation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *eks.DeletePodIdentityAssociationInput
+//   - optFns ...func(*eks.Options)
+func (_e *MockEKSAPI_Expecter) DeletePodIdentityAssociation(ctx interface{}, params interface{}, optFns ...interface{}) *MockEKSAPI_DeletePodIdentityAssociation_Call {
+	return &MockEKSAPI_DeletePodIdentityAssociation_Call{Call: _e.mock.On("DeletePodIdentityAssociation",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEKSAPI_DeletePodIdentityAssociation_Call) Run(run func(ctx context.Context, params *eks.DeletePodIdentityAssociationInput, optFns ...func(*eks.Options))) *MockEKSAPI_DeletePodIdentityAssociation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *eks.DeletePodIdentityAssociationInput
+		if args[1] != nil {
+			arg1 = args[1].(*eks.DeletePodIdentityAssociationInput)
+		}
+		var arg2 []func(*eks.Options)
+		var variadicArgs []func(*eks.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*eks.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEKSAPI_DeletePodIdentityAssociation_Call) Return(deletePodIdentityAssociationOutput *eks.DeletePodIdentityAssociationOutput, err error) *MockEKSAPI_DeletePodIdentityAssociation_Call {
+	_c.Call.Return(deletePodIdentityAssociationOutput, err)
+	return _c
+}
+
+func (_c *MockEKSAPI_DeletePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, params *eks.DeletePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DeletePodIdentityAssociationOutput, error)) *MockEKSAPI_DeletePodIdentityAssociation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribePodIdentityAssociation provides a mock function for the type MockEKSAPI
+func (_mock *MockEKSAPI) DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribePodIdentityAssociation")
+	}
+
+	var r0 *eks.DescribePodIdentityAssociationOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.DescribePodIdentityAssociationInput, ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.DescribePodIdentityAssociationInput, ...func(*eks.Options)) *eks.DescribePodIdentityAssociationOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eks.DescribePodIdentityAssociationOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *eks.DescribePodIdentityAssociationInput, ...func(*eks.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEKSAPI_DescribePodIdentityAssociation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribePodIdentityAssociation'
+type MockEKSAPI_DescribePodIdentityAssociation_Call struct {
+	*mock.Call
+}
+
+// DescribePodIdentityAssociation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *eks.DescribePodIdentityAssociationInput
+//   - optFns ...func(*eks.Options)
+func (_e *MockEKSAPI_Expecter) DescribePodIdentityAssociation(ctx interface{}, params interface{}, optFns ...interface{}) *MockEKSAPI_DescribePodIdentityAssociation_Call {
+	return &MockEKSAPI_DescribePodIdentityAssociation_Call{Call: _e.mock.On("DescribePodIdentityAssociation",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEKSAPI_DescribePodIdentityAssociation_Call) Run(run func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options))) *MockEKSAPI_DescribePodIdentityAssociation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *eks.DescribePodIdentityAssociationInput
+		if args[1] != nil {
+			arg1 = args[1].(*eks.DescribePodIdentityAssociationInput)
+		}
+		var arg2 []func(*eks.Options)
+		var variadicArgs []func(*eks.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*eks.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEKSAPI_DescribePodIdentityAssociation_Call) Return(describePodIdentityAssociationOutput *eks.DescribePodIdentityAssociationOutput, err error) *MockEKSAPI_DescribePodIdentityAssociation_Call {
+	_c.Call.Return(describePodIdentityAssociationOutput, err)
+	return _c
+}
+
+func (_c *MockEKSAPI_DescribePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error)) *MockEKSAPI_DescribePodIdentityAssociation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPodIdentityAssociations provides a mock function for the type MockEKSAPI
+func (_mock *MockEKSAPI) ListPodIdentityAssociations(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPodIdentityAssociations")
+	}
+
+	var r0 *eks.ListPodIdentityAssociationsOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.ListPodIdentityAssociationsInput, ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.ListPodIdentityAssociationsInput, ...func(*eks.Options)) *eks.ListPodIdentityAssociationsOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eks.ListPodIdentityAssociationsOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *eks.ListPodIdentityAssociationsInput, ...func(*eks.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEKSAPI_ListPodIdentityAssociations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPodIdentityAssociations'
+type MockEKSAPI_ListPodIdentityAssociations_Call struct {
+	*mock.Call
+}
+
+// ListPodIdentityAssociations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *eks.ListPodIdentityAssociationsInput
+//   - optFns ...func(*eks.Options)
+func (_e *MockEKSAPI_Expecter) ListPodIdentityAssociations(ctx interface{}, params interface{}, optFns ...interface{}) *MockEKSAPI_ListPodIdentityAssociations_Call {
+	return &MockEKSAPI_ListPodIdentityAssociations_Call{Call: _e.mock.On("ListPodIdentityAssociations",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEKSAPI_ListPodIdentityAssociations_Call) Run(run func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options))) *MockEKSAPI_ListPodIdentityAssociations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *eks.ListPodIdentityAssociationsInput
+		if args[1] != nil {
+			arg1 = args[1].(*eks.ListPodIdentityAssociationsInput)
+		}
+		var arg2 []func(*eks.Options)
+		var variadicArgs []func(*eks.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*eks.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEKSAPI_ListPodIdentityAssociations_Call) Return(listPodIdentityAssociationsOutput *eks.ListPodIdentityAssociationsOutput, err error) *MockEKSAPI_ListPodIdentityAssociations_Call {
+	_c.Call.Return(listPodIdentityAssociationsOutput, err)
+	return _c
+}
+
+func (_c *MockEKSAPI_ListPodIdentityAssociations_Call) RunAndReturn(run func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error)) *MockEKSAPI_ListPodIdentityAssociations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePodIdentityAssociation provides a mock function for the type MockEKSAPI
+func (_mock *MockEKSAPI) UpdatePodIdentityAssociation(ctx context.Context, params *eks.UpdatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.UpdatePodIdentityAssociationOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePodIdentityAssociation")
+	}
+
+	var r0 *eks.UpdatePodIdentityAssociationOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.UpdatePodIdentityAssociationInput, ...func(*eks.Options)) (*eks.UpdatePodIdentityAssociationOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *eks.UpdatePodIdentityAssociationInput, ...func(*eks.Options)) *eks.UpdatePodIdentityAssociationOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eks.UpdatePodIdentityAssociationOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *eks.UpdatePodIdentityAssociationInput, ...func(*eks.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEKSAPI_UpdatePodIdentityAssociation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePodIdentityAssociation'
+type MockEKSAPI_UpdatePodIdentityAssociation_Call struct {
+	*mock.Call
+}
+
+// UpdatePodIdentityAssociation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *eks.UpdatePodIdentityAssociationInput
+//   - optFns ...func(*eks.Options)
+func (_e *MockEKSAPI_Expecter) UpdatePodIdentityAssociation(ctx interface{}, params interface{}, optFns ...interface{}) *MockEKSAPI_UpdatePodIdentityAssociation_Call {
+	return &MockEKSAPI_UpdatePodIdentityAssociation_Call{Call: _e.mock.On("UpdatePodIdentityAssociation",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEKSAPI_UpdatePodIdentityAssociation_Call) Run(run func(ctx context.Context, params *eks.UpdatePodIdentityAssociationInput, optFns ...func(*eks.Options))) *MockEKSAPI_UpdatePodIdentityAssociation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *eks.UpdatePodIdentityAssociationInput
+		if args[1] != nil {
+			arg1 = args[1].(*eks.UpdatePodIdentityAssociationInput)
+		}
+		var arg2 []func(*eks.Options)
+		var variadicArgs []func(*eks.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*eks.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEKSAPI_UpdatePodIdentityAssociation_Call) Return(updatePodIdentityAssociationOutput *eks.UpdatePodIdentityAssociationOutput, err error) *MockEKSAPI_UpdatePodIdentityAssociation_Call {
+	_c.Call.Return(updatePodIdentityAssociationOutput, err)
+	return _c
+}
+
+func (_c *MockEKSAPI_UpdatePodIdentityAssociation_Call) RunAndReturn(run func(ctx context.Context, params *eks.UpdatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.UpdatePodIdentityAssociationOutput, error)) *MockEKSAPI_UpdatePodIdentityAssociation_Call {
+	_c.Call.Return(run)
+	return _c
+}
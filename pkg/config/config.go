@@ -0,0 +1,599 @@
+// Package config provides the versioned configuration file format for the PIA operator.
+//
+// The configuration follows the ControllerManagerConfig style: a YAML document with
+// apiVersion and kind fields that holds both manager settings (metrics, health probes,
+// leader election) and operator settings (cluster, region, retry policy, tags, scope,
+// concurrency and feature gates). Values are loaded from a file, overridden by any
+// command-line flags that were set explicitly, and validated before the manager starts.
+//
+// A subset of the fields can be changed while the operator is running: the Watcher
+// reloads the file when it changes and publishes the reloadable fields through a Store,
+// while changes to any other field are reported as requiring a restart.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the only configuration file version understood by the operator
+	APIVersion = "config.pia-operator.eks.aws.com/v1alpha1"
+	// Kind is the kind of the configuration document
+	Kind = "OperatorConfig"
+
+	// Default values used when neither the file nor the flags set a field
+	DefaultMetricsBindAddress      = ":8080"
+	DefaultHealthProbeBindAddress  = ":8081"
+	DefaultReadinessCheckInterval  = 30 * time.Second
+	DefaultWorkqueueStallTimeout   = 10 * time.Minute
+	DefaultLeaderElectionID        = "pia-operator.eks.aws.com"
+	DefaultMaxConcurrentReconciles = 1
+	DefaultShutdownGracePeriod     = 30 * time.Second
+	DefaultOrphanSweepInterval     = 10 * time.Minute
+	DefaultRetryMaxAttempts        = 5
+	DefaultRetryBaseDelay          = 30 * time.Second
+	DefaultRetryMaxDelay           = 5 * time.Minute
+	DefaultMaxRestartsPerMinute    = 10
+	DefaultWebhookPort             = 9443
+	DefaultShardGroup              = "pia-operator"
+	DefaultShardLeaseDuration      = 30 * time.Second
+	DefaultShardRenewInterval      = 10 * time.Second
+	DefaultRoleNameTemplate        = "{{.ClusterName}}-{{.Namespace}}-{{.ServiceAccount}}"
+	DefaultRolePath                = "/"
+	DefaultRoleDeletionPolicy      = "Delete"
+
+	DefaultNamespaceTerminationConcurrency = 10
+	DefaultNamespaceTerminationTimeout     = 5 * time.Minute
+
+	// maxUserTags leaves room for the tags the operator always sets on associations
+	maxUserTags = 45
+)
+
+// reservedTagKeys are set by the operator on every association and cannot be overridden
+var reservedTagKeys = map[string]bool{
+	"managed-by":     true,
+	"serviceaccount": true,
+	"namespace":      true,
+	"base-role":      true,
+	"assume-role":    true,
+	// Identify the ServiceAccount and scope of an association for the orphan sweep
+	"serviceaccount-uid":      true,
+	"serviceaccount-selector": true,
+}
+
+// awsRegionPattern matches region names such as eu-west-1, cn-north-1 and us-gov-west-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// FeatureWorkloadRollout lets the operator restart the workloads of ServiceAccounts annotated with
+// rollout-on-change. Disabling it stops all restarts without restarting the operator.
+const FeatureWorkloadRollout = "WorkloadRollout"
+
+// knownFeatureGates lists the feature gates understood by this version of the operator with their defaults
+var knownFeatureGates = map[string]bool{
+	FeatureWorkloadRollout: true,
+}
+
+// OperatorConfig is the configuration file format for the PIA operator
+type OperatorConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ClusterName is the EKS cluster the associations are created in
+	ClusterName string `json:"clusterName,omitempty"`
+	// AWSRegion is the region of the EKS cluster, detected from the environment when unset. It
+	// also selects the partition role ARNs have to be in.
+	AWSRegion string `json:"awsRegion,omitempty"`
+
+	Health           HealthConfig           `json:"health,omitempty"`
+	Metrics          MetricsConfig          `json:"metrics,omitempty"`
+	LeaderElection   LeaderElectionConfig   `json:"leaderElection,omitempty"`
+	Controller       ControllerConfig       `json:"controller,omitempty"`
+	RetryPolicy      RetryPolicyConfig      `json:"retryPolicy,omitempty"`
+	Scope            ScopeConfig            `json:"scope,omitempty"`
+	Sharding         ShardingConfig         `json:"sharding,omitempty"`
+	Rollout          RolloutConfig          `json:"rollout,omitempty"`
+	Webhook          WebhookConfig          `json:"webhook,omitempty"`
+	RoleProvisioning RoleProvisioningConfig `json:"roleProvisioning,omitempty"`
+	TrustPolicy      TrustPolicyConfig      `json:"trustPolicy,omitempty"`
+
+	NamespaceTermination NamespaceTerminationConfig `json:"namespaceTermination,omitempty"`
+	RoleDefaults         RoleDefaultsConfig         `json:"roleDefaults,omitempty"`
+	AccountRegistry      AccountRegistryConfig      `json:"accountRegistry,omitempty"`
+
+	// Tags are added to every Pod Identity Association created by the operator
+	Tags map[string]string `json:"tags,omitempty"`
+	// FeatureGates enables or disables optional operator behavior by name
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// HealthConfig configures the health probe endpoint
+type HealthConfig struct {
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+	// ReadinessCheckInterval is how long the result of the AWS readiness check is reused
+	ReadinessCheckInterval metav1.Duration `json:"readinessCheckInterval,omitempty"`
+	// WorkqueueStallTimeout is how long a workqueue may hold items without finishing any, or a
+	// reconcile may run, before the liveness check fails
+	WorkqueueStallTimeout metav1.Duration `json:"workqueueStallTimeout,omitempty"`
+}
+
+// MetricsConfig configures the metrics endpoint
+type MetricsConfig struct {
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// LeaderElectionConfig configures leader election between operator replicas
+type LeaderElectionConfig struct {
+	LeaderElect  bool   `json:"leaderElect,omitempty"`
+	ResourceName string `json:"resourceName,omitempty"`
+}
+
+// ControllerConfig configures the ServiceAccount controller
+type ControllerConfig struct {
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// ShutdownGracePeriod is how long in-flight reconciles may keep running after a shutdown signal,
+	// so that their AWS operations finish and are recorded; zero cancels them immediately
+	ShutdownGracePeriod metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+	// OrphanSweepInterval is how often the associations of ServiceAccounts that no longer exist are
+	// looked for and deleted; zero disables the sweep
+	OrphanSweepInterval metav1.Duration `json:"orphanSweepInterval,omitempty"`
+}
+
+// RetryPolicyConfig configures how failed AWS and Kubernetes operations are retried
+type RetryPolicyConfig struct {
+	MaxAttempts int             `json:"maxAttempts,omitempty"`
+	BaseDelay   metav1.Duration `json:"baseDelay,omitempty"`
+	MaxDelay    metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+// ScopeConfig restricts the ServiceAccounts handled by the operator.
+// All configured restrictions must match for a ServiceAccount to be handled.
+type ScopeConfig struct {
+	// Namespaces limits the operator to the listed namespaces; empty means all namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector is a label selector the ServiceAccount's namespace must match
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
+	// ServiceAccountSelector is a label selector the ServiceAccount must match
+	ServiceAccountSelector string `json:"serviceAccountSelector,omitempty"`
+}
+
+// ShardingConfig splits the namespaces between several active operator replicas.
+// Sharding replaces leader election, so both cannot be enabled at the same time.
+type ShardingConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// LeaseNamespace holds the shard membership Leases; defaults to the operator namespace
+	LeaseNamespace string `json:"leaseNamespace,omitempty"`
+	// Group separates operator deployments sharing the lease namespace
+	Group string `json:"group,omitempty"`
+	// LeaseDuration is how long a replica stays a member without renewing its Lease
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+	// RenewInterval is how often each replica renews its Lease and re-reads the membership
+	RenewInterval metav1.Duration `json:"renewInterval,omitempty"`
+}
+
+// RolloutConfig configures the workload restarts of ServiceAccounts annotated with rollout-on-change
+type RolloutConfig struct {
+	// MaxRestartsPerMinute limits how many workloads are restarted per minute across all ServiceAccounts
+	MaxRestartsPerMinute int `json:"maxRestartsPerMinute,omitempty"`
+}
+
+// WebhookConfig configures the pod admission webhook that holds back pods until their association is ready
+type WebhookConfig struct {
+	// PodMode is "deny" or "readiness-gate"; empty disables the webhook
+	PodMode string `json:"podMode,omitempty"`
+	// Port the webhook server listens on
+	Port int `json:"port,omitempty"`
+	// CertDir holds tls.crt and tls.key for the webhook server; empty uses the controller-runtime default
+	CertDir string `json:"certDir,omitempty"`
+}
+
+// RoleProvisioningConfig configures the IAM roles the operator creates for ServiceAccounts
+// annotated with managed-role-policy or managed-role-policy-arns
+type RoleProvisioningConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// NameTemplate is a Go template rendering the role name from .ClusterName, .Namespace and .ServiceAccount
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	// Path is the IAM path of the created roles
+	Path string `json:"path,omitempty"`
+	// PermissionsBoundary is the ARN of the managed policy set as permissions boundary of every role
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
+	// DeletionPolicy is Delete or Retain, and applies when a ServiceAccount stops using its role
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// TrustPolicyConfig configures the trust policy updates of the target roles of assume-role annotations
+type TrustPolicyConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// AccessRoleName is the role assumed in the target role's account to change its trust policy;
+	// empty uses the operator's own credentials
+	AccessRoleName string `json:"accessRoleName,omitempty"`
+}
+
+// NamespaceTerminationConfig configures how the associations of a terminating namespace are deleted
+type NamespaceTerminationConfig struct {
+	// Concurrency is the number of associations of a namespace deleted in parallel
+	Concurrency int `json:"concurrency,omitempty"`
+	// Timeout is how long after a namespace started terminating the finalizers of its ServiceAccounts are
+	// released even if their associations could not be deleted; zero never releases them
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RoleDefaultsConfig configures the roles of bindings with an empty role annotation. Both roles may be
+// Go templates using .ClusterName, .Region, .Namespace, .ServiceAccount, .NamespaceLabels and .AccountID.
+type RoleDefaultsConfig struct {
+	// Role is the role ARN of the binding; empty requires a role on every binding
+	Role string `json:"role,omitempty"`
+	// AssumeRole is the target role ARN of bindings without an assume-role annotation
+	AssumeRole string `json:"assumeRole,omitempty"`
+}
+
+// AccountRegistryConfig configures the ConfigMap mapping account aliases to account IDs, which lets
+// roles be given as <alias>:role/<name>
+type AccountRegistryConfig struct {
+	// ConfigMap is the name of the registry; empty disables account aliases
+	ConfigMap string `json:"configMap,omitempty"`
+	// Namespace of the registry; defaults to the operator namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Default returns a configuration with every field set to its default value
+func Default() *OperatorConfig {
+	return &OperatorConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+		},
+		Health: HealthConfig{
+			HealthProbeBindAddress: DefaultHealthProbeBindAddress,
+			ReadinessCheckInterval: metav1.Duration{Duration: DefaultReadinessCheckInterval},
+			WorkqueueStallTimeout:  metav1.Duration{Duration: DefaultWorkqueueStallTimeout},
+		},
+		Metrics: MetricsConfig{
+			BindAddress: DefaultMetricsBindAddress,
+		},
+		LeaderElection: LeaderElectionConfig{
+			ResourceName: DefaultLeaderElectionID,
+		},
+		Controller: ControllerConfig{
+			MaxConcurrentReconciles: DefaultMaxConcurrentReconciles,
+			ShutdownGracePeriod:     metav1.Duration{Duration: DefaultShutdownGracePeriod},
+			OrphanSweepInterval:     metav1.Duration{Duration: DefaultOrphanSweepInterval},
+		},
+		RetryPolicy: RetryPolicyConfig{
+			MaxAttempts: DefaultRetryMaxAttempts,
+			BaseDelay:   metav1.Duration{Duration: DefaultRetryBaseDelay},
+			MaxDelay:    metav1.Duration{Duration: DefaultRetryMaxDelay},
+		},
+		Webhook: WebhookConfig{
+			Port: DefaultWebhookPort,
+		},
+		Rollout: RolloutConfig{
+			MaxRestartsPerMinute: DefaultMaxRestartsPerMinute,
+		},
+		RoleProvisioning: RoleProvisioningConfig{
+			NameTemplate:   DefaultRoleNameTemplate,
+			Path:           DefaultRolePath,
+			DeletionPolicy: DefaultRoleDeletionPolicy,
+		},
+		Sharding: ShardingConfig{
+			Group:         DefaultShardGroup,
+			LeaseDuration: metav1.Duration{Duration: DefaultShardLeaseDuration},
+			RenewInterval: metav1.Duration{Duration: DefaultShardRenewInterval},
+		},
+		NamespaceTermination: NamespaceTerminationConfig{
+			Concurrency: DefaultNamespaceTerminationConcurrency,
+			Timeout:     metav1.Duration{Duration: DefaultNamespaceTerminationTimeout},
+		},
+	}
+}
+
+// Load reads the configuration file at path on top of the defaults.
+// An empty path returns the defaults unchanged.
+func Load(path string) (*OperatorConfig, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// flagOverride parses the value of a flag into the configuration field it overrides
+type flagOverride func(c *OperatorConfig, value string) error
+
+// flagOverrides maps every flag that overrides a configuration field to its setter. Flags that
+// are not listed, such as --config and --dev-mode, do not correspond to a field.
+var flagOverrides = map[string]flagOverride{
+	"cluster-name":                      stringField(func(c *OperatorConfig) *string { return &c.ClusterName }),
+	"aws-region":                        stringField(func(c *OperatorConfig) *string { return &c.AWSRegion }),
+	"metrics-bind-address":              stringField(func(c *OperatorConfig) *string { return &c.Metrics.BindAddress }),
+	"health-probe-bind-address":         stringField(func(c *OperatorConfig) *string { return &c.Health.HealthProbeBindAddress }),
+	"leader-elect":                      boolField(func(c *OperatorConfig) *bool { return &c.LeaderElection.LeaderElect }),
+	"watch-namespaces":                  listField(func(c *OperatorConfig) *[]string { return &c.Scope.Namespaces }),
+	"namespace-selector":                stringField(func(c *OperatorConfig) *string { return &c.Scope.NamespaceSelector }),
+	"serviceaccount-selector":           stringField(func(c *OperatorConfig) *string { return &c.Scope.ServiceAccountSelector }),
+	"max-concurrent-reconciles":         intField(func(c *OperatorConfig) *int { return &c.Controller.MaxConcurrentReconciles }),
+	"shutdown-grace-period":             durationField(func(c *OperatorConfig) *metav1.Duration { return &c.Controller.ShutdownGracePeriod }),
+	"orphan-sweep-interval":             durationField(func(c *OperatorConfig) *metav1.Duration { return &c.Controller.OrphanSweepInterval }),
+	"rollout-max-restarts-per-minute":   intField(func(c *OperatorConfig) *int { return &c.Rollout.MaxRestartsPerMinute }),
+	"pod-webhook-mode":                  stringField(func(c *OperatorConfig) *string { return &c.Webhook.PodMode }),
+	"webhook-port":                      intField(func(c *OperatorConfig) *int { return &c.Webhook.Port }),
+	"webhook-cert-dir":                  stringField(func(c *OperatorConfig) *string { return &c.Webhook.CertDir }),
+	"sharding":                          boolField(func(c *OperatorConfig) *bool { return &c.Sharding.Enabled }),
+	"shard-lease-namespace":             stringField(func(c *OperatorConfig) *string { return &c.Sharding.LeaseNamespace }),
+	"role-provisioning":                 boolField(func(c *OperatorConfig) *bool { return &c.RoleProvisioning.Enabled }),
+	"manage-trust-policy":               boolField(func(c *OperatorConfig) *bool { return &c.TrustPolicy.Enabled }),
+	"trust-policy-access-role":          stringField(func(c *OperatorConfig) *string { return &c.TrustPolicy.AccessRoleName }),
+	"namespace-termination-concurrency": intField(func(c *OperatorConfig) *int { return &c.NamespaceTermination.Concurrency }),
+	"namespace-termination-timeout":     durationField(func(c *OperatorConfig) *metav1.Duration { return &c.NamespaceTermination.Timeout }),
+	"account-registry":                  stringField(func(c *OperatorConfig) *string { return &c.AccountRegistry.ConfigMap }),
+}
+
+func stringField(field func(*OperatorConfig) *string) flagOverride {
+	return func(c *OperatorConfig, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+// listField splits a comma separated flag value
+func listField(field func(*OperatorConfig) *[]string) flagOverride {
+	return func(c *OperatorConfig, value string) error {
+		*field(c) = splitList(value)
+		return nil
+	}
+}
+
+func intField(field func(*OperatorConfig) *int) flagOverride {
+	return func(c *OperatorConfig, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func boolField(field func(*OperatorConfig) *bool) flagOverride {
+	return func(c *OperatorConfig, value string) error {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = enabled
+		return nil
+	}
+}
+
+func durationField(field func(*OperatorConfig) *metav1.Duration) flagOverride {
+	return func(c *OperatorConfig, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = metav1.Duration{Duration: d}
+		return nil
+	}
+}
+
+// ApplyFlagOverrides copies the value of every flag that was set explicitly on the
+// command line into the configuration, so that flags always take precedence over the file.
+func (c *OperatorConfig) ApplyFlagOverrides(fs *flag.FlagSet) error {
+	var errs []string
+	fs.Visit(func(f *flag.Flag) {
+		override, ok := flagOverrides[f.Name]
+		if !ok {
+			return
+		}
+		value := f.Value.String()
+		if err := override(c, value); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value %q for flag --%s", value, f.Name))
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate checks the configuration for missing or inconsistent values
+func (c *OperatorConfig) Validate() error {
+	var errs []string
+
+	if c.APIVersion != "" && c.APIVersion != APIVersion {
+		errs = append(errs, fmt.Sprintf("unsupported apiVersion %q, expected %q", c.APIVersion, APIVersion))
+	}
+	if c.Kind != "" && c.Kind != Kind {
+		errs = append(errs, fmt.Sprintf("unsupported kind %q, expected %q", c.Kind, Kind))
+	}
+	if c.ClusterName == "" {
+		errs = append(errs, "clusterName is required")
+	}
+	if c.AWSRegion == "" {
+		errs = append(errs, "awsRegion is required")
+	} else if !awsRegionPattern.MatchString(c.AWSRegion) {
+		errs = append(errs, fmt.Sprintf("awsRegion %q is not an AWS region", c.AWSRegion))
+	}
+	if c.LeaderElection.LeaderElect && c.LeaderElection.ResourceName == "" {
+		errs = append(errs, "leaderElection.resourceName is required when leader election is enabled")
+	}
+	if c.Controller.MaxConcurrentReconciles < 1 {
+		errs = append(errs, "controller.maxConcurrentReconciles must be at least 1")
+	}
+	if c.Controller.ShutdownGracePeriod.Duration < 0 {
+		errs = append(errs, "controller.shutdownGracePeriod must not be negative")
+	}
+	if c.Controller.OrphanSweepInterval.Duration < 0 {
+		errs = append(errs, "controller.orphanSweepInterval must not be negative")
+	}
+	if c.NamespaceTermination.Concurrency < 1 {
+		errs = append(errs, "namespaceTermination.concurrency must be at least 1")
+	}
+	if c.NamespaceTermination.Timeout.Duration < 0 {
+		errs = append(errs, "namespaceTermination.timeout must not be negative")
+	}
+	if c.Health.ReadinessCheckInterval.Duration <= 0 {
+		errs = append(errs, "health.readinessCheckInterval must be positive")
+	}
+	if c.Health.WorkqueueStallTimeout.Duration <= 0 {
+		errs = append(errs, "health.workqueueStallTimeout must be positive")
+	}
+	if c.Rollout.MaxRestartsPerMinute < 1 {
+		errs = append(errs, "rollout.maxRestartsPerMinute must be at least 1")
+	}
+	if c.Webhook.PodMode != "" && c.Webhook.PodMode != "deny" && c.Webhook.PodMode != "readiness-gate" {
+		errs = append(errs, fmt.Sprintf("webhook.podMode: unsupported mode %q, expected deny or readiness-gate", c.Webhook.PodMode))
+	}
+	if c.Webhook.Port < 1 || c.Webhook.Port > 65535 {
+		errs = append(errs, "webhook.port must be between 1 and 65535")
+	}
+	if c.RetryPolicy.MaxAttempts < 0 {
+		errs = append(errs, "retryPolicy.maxAttempts must not be negative")
+	}
+	if c.RetryPolicy.BaseDelay.Duration <= 0 {
+		errs = append(errs, "retryPolicy.baseDelay must be positive")
+	}
+	if c.RetryPolicy.MaxDelay.Duration < c.RetryPolicy.BaseDelay.Duration {
+		errs = append(errs, "retryPolicy.maxDelay must not be lower than retryPolicy.baseDelay")
+	}
+	for _, ns := range c.Scope.Namespaces {
+		if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+			errs = append(errs, fmt.Sprintf("scope.namespaces: invalid namespace %q: %s", ns, strings.Join(msgs, ", ")))
+		}
+	}
+	if _, err := labels.Parse(c.Scope.NamespaceSelector); err != nil {
+		errs = append(errs, fmt.Sprintf("scope.namespaceSelector: %v", err))
+	}
+	if _, err := labels.Parse(c.Scope.ServiceAccountSelector); err != nil {
+		errs = append(errs, fmt.Sprintf("scope.serviceAccountSelector: %v", err))
+	}
+	if c.Sharding.Enabled {
+		if c.LeaderElection.LeaderElect {
+			errs = append(errs, "sharding and leader election cannot be enabled at the same time")
+		}
+		if c.Sharding.Group == "" {
+			errs = append(errs, "sharding.group is required when sharding is enabled")
+		}
+		if c.Sharding.RenewInterval.Duration <= 0 {
+			errs = append(errs, "sharding.renewInterval must be positive")
+		}
+		if c.Sharding.LeaseDuration.Duration <= c.Sharding.RenewInterval.Duration {
+			errs = append(errs, "sharding.leaseDuration must be greater than sharding.renewInterval")
+		}
+	}
+	if c.RoleProvisioning.Enabled {
+		if _, err := template.New("roleName").Parse(c.RoleProvisioning.NameTemplate); err != nil || c.RoleProvisioning.NameTemplate == "" {
+			errs = append(errs, "roleProvisioning.nameTemplate must be a valid non-empty template")
+		}
+		if !strings.HasPrefix(c.RoleProvisioning.Path, "/") || !strings.HasSuffix(c.RoleProvisioning.Path, "/") {
+			errs = append(errs, "roleProvisioning.path must begin and end with /")
+		}
+		if c.RoleProvisioning.DeletionPolicy != "Delete" && c.RoleProvisioning.DeletionPolicy != "Retain" {
+			errs = append(errs, fmt.Sprintf("roleProvisioning.deletionPolicy: unsupported policy %q, expected Delete or Retain", c.RoleProvisioning.DeletionPolicy))
+		}
+	}
+	for _, role := range [][2]string{{"roleDefaults.role", c.RoleDefaults.Role}, {"roleDefaults.assumeRole", c.RoleDefaults.AssumeRole}} {
+		if _, err := template.New(role[0]).Parse(role[1]); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid template: %v", role[0], err))
+		}
+	}
+	if c.RoleDefaults.AssumeRole != "" && c.RoleDefaults.Role == "" {
+		errs = append(errs, "roleDefaults.assumeRole requires roleDefaults.role")
+	}
+	if name := c.AccountRegistry.ConfigMap; name != "" {
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			errs = append(errs, fmt.Sprintf("accountRegistry.configMap: invalid name %q: %s", name, strings.Join(msgs, ", ")))
+		}
+	}
+	errs = append(errs, validateTags(c.Tags)...)
+	for name := range c.FeatureGates {
+		if _, ok := knownFeatureGates[name]; !ok {
+			errs = append(errs, fmt.Sprintf("featureGates: unknown feature gate %q", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid operator configuration: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateTags checks user supplied tags against the EKS tagging restrictions
+func validateTags(tags map[string]string) []string {
+	var errs []string
+	if len(tags) > maxUserTags {
+		errs = append(errs, fmt.Sprintf("tags: at most %d tags are allowed", maxUserTags))
+	}
+	for key, value := range tags {
+		switch {
+		case key == "":
+			errs = append(errs, "tags: empty tag key")
+		case reservedTagKeys[key]:
+			errs = append(errs, fmt.Sprintf("tags: key %q is reserved by the operator", key))
+		case strings.HasPrefix(strings.ToLower(key), "aws:"):
+			errs = append(errs, fmt.Sprintf("tags: key %q uses the reserved aws: prefix", key))
+		case len(key) > 128:
+			errs = append(errs, fmt.Sprintf("tags: key %q is longer than 128 characters", key))
+		case len(value) > 256:
+			errs = append(errs, fmt.Sprintf("tags: value for key %q is longer than 256 characters", key))
+		}
+	}
+	return errs
+}
+
+// FeatureEnabled reports whether the named feature gate is enabled, falling back to its default
+func (c *OperatorConfig) FeatureEnabled(name string) bool {
+	if enabled, ok := c.FeatureGates[name]; ok {
+		return enabled
+	}
+	return knownFeatureGates[name]
+}
+
+// DeepCopy returns a copy of the configuration that shares no maps or slices with the original
+func (c *OperatorConfig) DeepCopy() *OperatorConfig {
+	out := *c
+	out.Scope.Namespaces = append([]string(nil), c.Scope.Namespaces...)
+	out.Tags = copyMap(c.Tags)
+	out.FeatureGates = copyMap(c.FeatureGates)
+	return &out
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func copyMap[V any](in map[string]V) map[string]V {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]V, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,374 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/config"
+)
+
+var _ = Describe("OperatorConfig", func() {
+	var (
+		dir        string
+		configPath string
+	)
+
+	writeConfig := func(content string) {
+		Expect(os.WriteFile(configPath, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		configPath = filepath.Join(dir, "config.yaml")
+	})
+
+	Describe("Load", func() {
+		It("should return defaults when no path is given", func() {
+			cfg, err := config.Load("")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg).To(Equal(config.Default()))
+		})
+
+		It("should read values from the file on top of the defaults", func() {
+			writeConfig(`
+apiVersion: config.pia-operator.eks.aws.com/v1alpha1
+kind: OperatorConfig
+clusterName: my-cluster
+awsRegion: us-west-2
+retryPolicy:
+  maxAttempts: 3
+  baseDelay: 10s
+tags:
+  team: platform
+`)
+
+			cfg, err := config.Load(configPath)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ClusterName).To(Equal("my-cluster"))
+			Expect(cfg.AWSRegion).To(Equal("us-west-2"))
+			Expect(cfg.RetryPolicy.MaxAttempts).To(Equal(3))
+			Expect(cfg.RetryPolicy.BaseDelay.Duration).To(Equal(10 * time.Second))
+			Expect(cfg.RetryPolicy.MaxDelay.Duration).To(Equal(config.DefaultRetryMaxDelay))
+			Expect(cfg.Tags).To(HaveKeyWithValue("team", "platform"))
+			Expect(cfg.Metrics.BindAddress).To(Equal(config.DefaultMetricsBindAddress))
+		})
+
+		It("should reject unknown fields", func() {
+			writeConfig("clusterName: my-cluster\nunknownField: true\n")
+
+			_, err := config.Load(configPath)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when the file does not exist", func() {
+			_, err := config.Load(filepath.Join(dir, "missing.yaml"))
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ApplyFlagOverrides", func() {
+		It("should only override values of flags that were set", func() {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("cluster-name", "", "")
+			fs.String("aws-region", "", "")
+			fs.Bool("leader-elect", false, "")
+			fs.String("watch-namespaces", "", "")
+			fs.Int("max-concurrent-reconciles", 1, "")
+			Expect(fs.Parse([]string{"--cluster-name=from-flag", "--leader-elect", "--watch-namespaces=team-a, team-b", "--max-concurrent-reconciles=4"})).To(Succeed())
+
+			cfg := config.Default()
+			cfg.ClusterName = "from-file"
+			cfg.AWSRegion = "us-east-1"
+
+			Expect(cfg.ApplyFlagOverrides(fs)).To(Succeed())
+			Expect(cfg.ClusterName).To(Equal("from-flag"))
+			Expect(cfg.AWSRegion).To(Equal("us-east-1"))
+			Expect(cfg.LeaderElection.LeaderElect).To(BeTrue())
+			Expect(cfg.Scope.Namespaces).To(Equal([]string{"team-a", "team-b"}))
+			Expect(cfg.Controller.MaxConcurrentReconciles).To(Equal(4))
+		})
+
+		It("should parse duration flags", func() {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.Duration("namespace-termination-timeout", config.DefaultNamespaceTerminationTimeout, "")
+			fs.Duration("shutdown-grace-period", config.DefaultShutdownGracePeriod, "")
+			fs.Duration("orphan-sweep-interval", config.DefaultOrphanSweepInterval, "")
+			Expect(fs.Parse([]string{"--namespace-termination-timeout=90s", "--shutdown-grace-period=1m", "--orphan-sweep-interval=0"})).To(Succeed())
+
+			cfg := config.Default()
+			Expect(cfg.ApplyFlagOverrides(fs)).To(Succeed())
+			Expect(cfg.NamespaceTermination.Timeout.Duration).To(Equal(90 * time.Second))
+			Expect(cfg.Controller.ShutdownGracePeriod.Duration).To(Equal(time.Minute))
+			Expect(cfg.Controller.OrphanSweepInterval.Duration).To(BeZero())
+		})
+
+		It("should report flags with invalid values", func() {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("webhook-port", "", "")
+			fs.String("sharding", "", "")
+			Expect(fs.Parse([]string{"--webhook-port=https", "--sharding=maybe"})).To(Succeed())
+
+			err := config.Default().ApplyFlagOverrides(fs)
+
+			Expect(err).To(MatchError(ContainSubstring("--webhook-port")))
+			Expect(err).To(MatchError(ContainSubstring("--sharding")))
+		})
+
+		It("should ignore flags that do not correspond to a field", func() {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("config", "", "")
+			Expect(fs.Parse([]string{"--config=/etc/pia-operator/config.yaml"})).To(Succeed())
+
+			cfg := config.Default()
+			Expect(cfg.ApplyFlagOverrides(fs)).To(Succeed())
+			Expect(cfg).To(Equal(config.Default()))
+		})
+	})
+
+	Describe("Validate", func() {
+		var cfg *config.OperatorConfig
+
+		BeforeEach(func() {
+			cfg = config.Default()
+			cfg.ClusterName = "my-cluster"
+			cfg.AWSRegion = "us-west-2"
+		})
+
+		It("should accept the defaults with a cluster name and region", func() {
+			Expect(cfg.Validate()).To(Succeed())
+		})
+
+		It("should require a cluster name", func() {
+			cfg.ClusterName = ""
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("clusterName is required")))
+		})
+
+		It("should require a region", func() {
+			cfg.AWSRegion = ""
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("awsRegion is required")))
+		})
+
+		It("should accept regions of every partition and reject malformed regions", func() {
+			for _, region := range []string{"eu-west-1", "cn-northwest-1", "us-gov-west-1", "ap-southeast-5"} {
+				cfg.AWSRegion = region
+				Expect(cfg.Validate()).To(Succeed(), region)
+			}
+			cfg.AWSRegion = "Europe (Ireland)"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring(`awsRegion "Europe (Ireland)" is not an AWS region`)))
+		})
+
+		It("should reject an unsupported apiVersion", func() {
+			cfg.APIVersion = "config.pia-operator.eks.aws.com/v2"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unsupported apiVersion")))
+		})
+
+		It("should reject a max delay lower than the base delay", func() {
+			cfg.RetryPolicy.MaxDelay.Duration = time.Second
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("retryPolicy.maxDelay")))
+		})
+
+		It("should reject a concurrency lower than one", func() {
+			cfg.Controller.MaxConcurrentReconciles = 0
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("maxConcurrentReconciles")))
+		})
+
+		It("should reject reserved and aws: prefixed tags", func() {
+			cfg.Tags = map[string]string{"managed-by": "me", "aws:owner": "me"}
+			err := cfg.Validate()
+			Expect(err).To(MatchError(ContainSubstring(`key "managed-by" is reserved`)))
+			Expect(err).To(MatchError(ContainSubstring(`key "aws:owner" uses the reserved aws: prefix`)))
+		})
+
+		It("should reject invalid namespaces", func() {
+			cfg.Scope.Namespaces = []string{"Not_Valid"}
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("scope.namespaces")))
+		})
+
+		It("should reject invalid label selectors", func() {
+			cfg.Scope.NamespaceSelector = "tenant in (a"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("scope.namespaceSelector")))
+		})
+
+		It("should reject sharding combined with leader election", func() {
+			cfg.Sharding.Enabled = true
+			cfg.LeaderElection.LeaderElect = true
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("sharding and leader election")))
+		})
+
+		It("should reject a negative shutdown grace period", func() {
+			cfg.Controller.ShutdownGracePeriod.Duration = -time.Second
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("controller.shutdownGracePeriod must not be negative")))
+		})
+
+		It("should reject health check durations that are not positive", func() {
+			cfg.Health.ReadinessCheckInterval.Duration = 0
+			cfg.Health.WorkqueueStallTimeout.Duration = -time.Minute
+
+			err := cfg.Validate()
+
+			Expect(err).To(MatchError(ContainSubstring("health.readinessCheckInterval must be positive")))
+			Expect(err).To(MatchError(ContainSubstring("health.workqueueStallTimeout must be positive")))
+		})
+
+		It("should reject an unknown pod webhook mode", func() {
+			cfg.Webhook.PodMode = "block"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("webhook.podMode")))
+		})
+
+		It("should reject an invalid namespace termination configuration", func() {
+			cfg.NamespaceTermination.Concurrency = 0
+			cfg.NamespaceTermination.Timeout.Duration = -time.Second
+			Expect(cfg.Validate()).To(MatchError(SatisfyAll(
+				ContainSubstring("namespaceTermination.concurrency"),
+				ContainSubstring("namespaceTermination.timeout"),
+			)))
+		})
+
+		It("should reject invalid role defaults", func() {
+			cfg.RoleDefaults.AssumeRole = "arn:aws:iam::{{ .AccountID }:role/target"
+			Expect(cfg.Validate()).To(MatchError(SatisfyAll(
+				ContainSubstring("roleDefaults.assumeRole: invalid template"),
+				ContainSubstring("roleDefaults.assumeRole requires roleDefaults.role"),
+			)))
+		})
+
+		It("should reject an invalid account registry name", func() {
+			cfg.AccountRegistry.ConfigMap = "Accounts_Map"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("accountRegistry.configMap")))
+		})
+
+		It("should reject unknown feature gates", func() {
+			cfg.FeatureGates = map[string]bool{"DoesNotExist": true}
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unknown feature gate")))
+		})
+
+		It("should accept known feature gates", func() {
+			Expect(cfg.FeatureEnabled(config.FeatureWorkloadRollout)).To(BeTrue())
+			cfg.FeatureGates = map[string]bool{config.FeatureWorkloadRollout: false}
+			Expect(cfg.Validate()).To(Succeed())
+			Expect(cfg.FeatureEnabled(config.FeatureWorkloadRollout)).To(BeFalse())
+		})
+	})
+
+	Describe("Store", func() {
+		It("should apply reloadable fields and notify listeners", func() {
+			cfg := config.Default()
+			cfg.ClusterName = "my-cluster"
+			store := config.NewStore(cfg)
+
+			var notified *config.OperatorConfig
+			store.OnChange(func(c *config.OperatorConfig) { notified = c })
+
+			next := cfg.DeepCopy()
+			next.Tags = map[string]string{"team": "platform"}
+			next.RetryPolicy.MaxAttempts = 2
+
+			Expect(store.Update(next)).To(BeEmpty())
+			Expect(notified).ToNot(BeNil())
+			Expect(notified.Tags).To(HaveKeyWithValue("team", "platform"))
+			Expect(store.Get().RetryPolicy.MaxAttempts).To(Equal(2))
+		})
+
+		It("should keep fields that require a restart and report them", func() {
+			cfg := config.Default()
+			cfg.ClusterName = "my-cluster"
+			store := config.NewStore(cfg)
+
+			next := cfg.DeepCopy()
+			next.ClusterName = "other-cluster"
+			next.Controller.MaxConcurrentReconciles = 4
+
+			Expect(store.Update(next)).To(ConsistOf("clusterName", "controller"))
+			Expect(store.Get().ClusterName).To(Equal("my-cluster"))
+			Expect(store.Get().Controller.MaxConcurrentReconciles).To(Equal(1))
+		})
+
+		It("should return copies that do not affect the active configuration", func() {
+			cfg := config.Default()
+			cfg.Tags = map[string]string{"team": "platform"}
+			store := config.NewStore(cfg)
+
+			store.Get().Tags["team"] = "changed"
+
+			Expect(store.Get().Tags).To(HaveKeyWithValue("team", "platform"))
+		})
+	})
+
+	Describe("Watcher", func() {
+		It("should reload the file and keep flag overrides", func() {
+			writeConfig("clusterName: my-cluster\n")
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("cluster-name", "", "")
+			Expect(fs.Parse([]string{"--cluster-name=flag-cluster"})).To(Succeed())
+
+			cfg, err := config.Load(configPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ApplyFlagOverrides(fs)).To(Succeed())
+			// Detected at startup
+			cfg.AWSRegion = "us-gov-west-1"
+			store := config.NewStore(cfg)
+			watcher := config.NewWatcher(configPath, fs, store, log.Log)
+
+			writeConfig("clusterName: my-cluster\ntags:\n  team: platform\n")
+			watcher.Reload()
+
+			Expect(store.Get().Tags).To(HaveKeyWithValue("team", "platform"))
+			Expect(store.Get().ClusterName).To(Equal("flag-cluster"))
+			Expect(store.Get().AWSRegion).To(Equal("us-gov-west-1"))
+		})
+
+		It("should reload feature gates", func() {
+			writeConfig("clusterName: my-cluster\n")
+			cfg, err := config.Load(configPath)
+			Expect(err).ToNot(HaveOccurred())
+			cfg.AWSRegion = "eu-west-1"
+			store := config.NewStore(cfg)
+			watcher := config.NewWatcher(configPath, flag.NewFlagSet("test", flag.ContinueOnError), store, log.Log)
+			Expect(store.Get().FeatureEnabled(config.FeatureWorkloadRollout)).To(BeTrue())
+
+			writeConfig("clusterName: my-cluster\nfeatureGates:\n  WorkloadRollout: false\n")
+			watcher.Reload()
+
+			Expect(store.Get().FeatureEnabled(config.FeatureWorkloadRollout)).To(BeFalse())
+		})
+
+		It("should keep the discovered cluster name and region", func() {
+			writeConfig("tags:\n  team: platform\n")
+			cfg, err := config.Load(configPath)
+			Expect(err).ToNot(HaveOccurred())
+			cfg.ClusterName, cfg.AWSRegion = "discovered", "eu-west-1"
+			store := config.NewStore(cfg)
+			watcher := config.NewWatcher(configPath, flag.NewFlagSet("test", flag.ContinueOnError), store, log.Log)
+
+			writeConfig("tags:\n  team: payments\n")
+			watcher.Reload()
+
+			Expect(store.Get().Tags).To(HaveKeyWithValue("team", "payments"))
+			Expect(store.Get().ClusterName).To(Equal("discovered"))
+			Expect(store.Get().AWSRegion).To(Equal("eu-west-1"))
+		})
+
+		It("should ignore invalid configurations", func() {
+			writeConfig("clusterName: my-cluster\n")
+			cfg, err := config.Load(configPath)
+			Expect(err).ToNot(HaveOccurred())
+			store := config.NewStore(cfg)
+			watcher := config.NewWatcher(configPath, flag.NewFlagSet("test", flag.ContinueOnError), store, log.Log)
+
+			writeConfig("clusterName: my-cluster\nretryPolicy:\n  maxAttempts: -1\n")
+			watcher.Reload()
+
+			Expect(store.Get().RetryPolicy.MaxAttempts).To(Equal(config.DefaultRetryMaxAttempts))
+		})
+	})
+})
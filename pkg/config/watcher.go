@@ -0,0 +1,204 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+)
+
+// Store holds the active configuration and notifies listeners when reloadable fields change
+type Store struct {
+	mu        sync.RWMutex
+	current   *OperatorConfig
+	listeners []func(*OperatorConfig)
+}
+
+// NewStore creates a Store holding the given configuration
+func NewStore(cfg *OperatorConfig) *Store {
+	return &Store{current: cfg.DeepCopy()}
+}
+
+// Get returns a copy of the active configuration
+func (s *Store) Get() *OperatorConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.DeepCopy()
+}
+
+// OnChange registers a function that is called with the new configuration after every reload
+func (s *Store) OnChange(fn func(*OperatorConfig)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Update applies the reloadable fields of next to the active configuration and notifies listeners.
+// It returns the names of the fields that differ but can only be changed by restarting the operator.
+func (s *Store) Update(next *OperatorConfig) []string {
+	s.mu.Lock()
+	updated := s.current.DeepCopy()
+	restartRequired := restartRequiredFields(s.current, next)
+	changed := !reflect.DeepEqual(s.current.RetryPolicy, next.RetryPolicy) ||
+		!reflect.DeepEqual(s.current.Tags, next.Tags) ||
+		!reflect.DeepEqual(s.current.FeatureGates, next.FeatureGates)
+
+	updated.RetryPolicy = next.RetryPolicy
+	updated.Tags = copyMap(next.Tags)
+	updated.FeatureGates = copyMap(next.FeatureGates)
+	s.current = updated
+	listeners := append([]func(*OperatorConfig){}, s.listeners...)
+	s.mu.Unlock()
+
+	if changed {
+		for _, fn := range listeners {
+			fn(updated.DeepCopy())
+		}
+	}
+	return restartRequired
+}
+
+// restartRequiredFields lists the fields that changed between two configurations and are not reloadable
+func restartRequiredFields(current, next *OperatorConfig) []string {
+	var fields []string
+	if current.ClusterName != next.ClusterName {
+		fields = append(fields, "clusterName")
+	}
+	if current.AWSRegion != next.AWSRegion {
+		fields = append(fields, "awsRegion")
+	}
+	if current.Health != next.Health {
+		fields = append(fields, "health")
+	}
+	if current.Metrics != next.Metrics {
+		fields = append(fields, "metrics")
+	}
+	if current.LeaderElection != next.LeaderElection {
+		fields = append(fields, "leaderElection")
+	}
+	if current.Controller != next.Controller {
+		fields = append(fields, "controller")
+	}
+	if !reflect.DeepEqual(current.Scope, next.Scope) {
+		fields = append(fields, "scope")
+	}
+	if current.Webhook != next.Webhook {
+		fields = append(fields, "webhook")
+	}
+	if current.Rollout != next.Rollout {
+		fields = append(fields, "rollout")
+	}
+	if current.Sharding != next.Sharding {
+		fields = append(fields, "sharding")
+	}
+	if current.RoleProvisioning != next.RoleProvisioning {
+		fields = append(fields, "roleProvisioning")
+	}
+	if current.TrustPolicy != next.TrustPolicy {
+		fields = append(fields, "trustPolicy")
+	}
+	if current.NamespaceTermination != next.NamespaceTermination {
+		fields = append(fields, "namespaceTermination")
+	}
+	if current.RoleDefaults != next.RoleDefaults {
+		fields = append(fields, "roleDefaults")
+	}
+	if current.AccountRegistry != next.AccountRegistry {
+		fields = append(fields, "accountRegistry")
+	}
+	return fields
+}
+
+// Watcher reloads the configuration file when it changes on disk.
+// It implements manager.Runnable so it can be added to the controller manager.
+type Watcher struct {
+	path  string
+	flags *flag.FlagSet
+	store *Store
+	log   logr.Logger
+}
+
+// NewWatcher creates a Watcher for the configuration file at path.
+// Flags set explicitly in fs are re-applied on every reload so they keep precedence over the file.
+func NewWatcher(path string, fs *flag.FlagSet, store *Store, log logr.Logger) *Watcher {
+	return &Watcher{
+		path:  path,
+		flags: fs,
+		store: store,
+		log:   log,
+	}
+}
+
+// Start watches the directory of the configuration file until the context is cancelled.
+// The directory is watched instead of the file because ConfigMap volumes replace files through symlink swaps.
+func (w *Watcher) Start(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config file watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to watch config file %s: %w", w.path, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			w.Reload()
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.log.Error(err, "Config file watcher error")
+		}
+	}
+}
+
+// NeedLeaderElection returns false so every replica keeps its configuration up to date
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload reads and validates the configuration file and applies it to the store.
+// Invalid configurations are logged and ignored, leaving the active configuration untouched.
+func (w *Watcher) Reload() {
+	next, err := Load(w.path)
+	if err != nil {
+		w.log.Error(err, "Failed to reload configuration, keeping the active configuration")
+		return
+	}
+	if err := next.ApplyFlagOverrides(w.flags); err != nil {
+		w.log.Error(err, "Failed to apply flag overrides to reloaded configuration")
+		return
+	}
+	// The cluster name and region may have been discovered at startup
+	current := w.store.Get()
+	if next.ClusterName == "" {
+		next.ClusterName = current.ClusterName
+	}
+	if next.AWSRegion == "" {
+		next.AWSRegion = current.AWSRegion
+	}
+	if err := next.Validate(); err != nil {
+		w.log.Error(err, "Reloaded configuration is invalid, keeping the active configuration")
+		return
+	}
+
+	if fields := w.store.Update(next); len(fields) > 0 {
+		w.log.Info("Configuration changes require a restart to take effect", "fields", fields)
+	}
+}
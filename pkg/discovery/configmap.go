@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigMapNamespace holds the ConfigMaps read by ConfigMapSource
+	ConfigMapNamespace = "kube-system"
+
+	// ClusterInfoConfigMap can be created by the tool provisioning the cluster, with the cluster
+	// name and region under the ClusterNameKey and RegionKey keys
+	ClusterInfoConfigMap = "cluster-info"
+	ClusterNameKey       = "cluster-name"
+	RegionKey            = "region"
+
+	// kubeProxyConfigMap is created by the EKS kube-proxy add-on, its kubeconfig points at the
+	// endpoint of the cluster, which contains the region
+	kubeProxyConfigMap = "kube-proxy"
+	kubeconfigKey      = "kubeconfig"
+)
+
+// eksEndpoint matches the API server endpoint of an EKS cluster and captures its region
+var eksEndpoint = regexp.MustCompile(`https://[0-9A-Za-z]+\.(?:[0-9a-z]+\.)?([a-z]{2}(?:-[a-z]+)+-\d+)\.eks\.amazonaws\.com(?:\.cn)?`)
+
+// ConfigMapSource reads the cluster name and region from ConfigMaps in kube-system
+type ConfigMapSource struct {
+	reader client.Reader
+}
+
+// NewConfigMapSource creates a ConfigMapSource. The reader should not be a cache, the ConfigMaps
+// are read once at startup.
+func NewConfigMapSource(reader client.Reader) *ConfigMapSource {
+	return &ConfigMapSource{reader: reader}
+}
+
+// Name identifies the source in logs
+func (s *ConfigMapSource) Name() string {
+	return "kube-system ConfigMaps"
+}
+
+// Discover reads the cluster-info ConfigMap, and the region from the kube-proxy ConfigMap when
+// cluster-info has none. Missing ConfigMaps are not an error.
+func (s *ConfigMapSource) Discover(ctx context.Context) (Cluster, error) {
+	var cluster Cluster
+	info, err := s.get(ctx, ClusterInfoConfigMap)
+	if err != nil {
+		return cluster, err
+	}
+	cluster.Name = info[ClusterNameKey]
+	cluster.Region = info[RegionKey]
+	if cluster.Region != "" {
+		return cluster, nil
+	}
+
+	kubeProxy, err := s.get(ctx, kubeProxyConfigMap)
+	if err != nil {
+		return cluster, err
+	}
+	if match := eksEndpoint.FindStringSubmatch(kubeProxy[kubeconfigKey]); match != nil {
+		cluster.Region = match[1]
+	}
+	return cluster, nil
+}
+
+// get returns the data of a ConfigMap in kube-system, or nil if it does not exist
+func (s *ConfigMapSource) get(ctx context.Context, name string) (map[string]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: ConfigMapNamespace, Name: name}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", ConfigMapNamespace, name, err)
+	}
+	return cm.Data, nil
+}
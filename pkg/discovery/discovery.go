@@ -0,0 +1,32 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+)
+
+// Discover fills in the empty fields of known from the sources, in order. Sources are not asked
+// once both fields are known, so values set explicitly always take precedence. Failing sources are
+// logged and skipped; the caller checks which fields are still empty.
+func Discover(ctx context.Context, known Cluster, log logr.Logger, sources ...Source) Cluster {
+	cluster := known
+	for _, source := range sources {
+		if cluster.complete() {
+			break
+		}
+		found, err := source.Discover(ctx)
+		if err != nil {
+			log.V(1).Info("Cluster discovery source failed", "source", source.Name(), "error", err.Error())
+		}
+		if cluster.Name == "" && found.Name != "" {
+			cluster.Name = found.Name
+			log.Info("Discovered cluster name", "clusterName", found.Name, "source", source.Name())
+		}
+		if cluster.Region == "" && found.Region != "" {
+			cluster.Region = found.Region
+			log.Info("Discovered AWS region", "region", found.Region, "source", source.Name())
+		}
+	}
+	return cluster
+}
@@ -0,0 +1,165 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/discovery"
+	discoverymocks "github.com/irenedo/pia-operator/pkg/discovery/mocks"
+)
+
+var _ = Describe("Discovery", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Describe("Discover", func() {
+		var first, second *discoverymocks.MockSource
+
+		BeforeEach(func() {
+			first = discoverymocks.NewMockSource(GinkgoT())
+			second = discoverymocks.NewMockSource(GinkgoT())
+			first.On("Name").Return("first").Maybe()
+			second.On("Name").Return("second").Maybe()
+		})
+
+		It("should keep known values and fill the others from the first source that has them", func() {
+			first.On("Discover", ctx).Return(discovery.Cluster{Name: "discovered", Region: ""}, nil)
+
+			cluster := discovery.Discover(ctx, discovery.Cluster{Region: "us-west-2"}, log.Log, first, second)
+
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "discovered", Region: "us-west-2"}))
+			second.AssertNotCalled(GinkgoT(), "Discover", mock.Anything)
+		})
+
+		It("should not ask any source when both values are known", func() {
+			cluster := discovery.Discover(ctx, discovery.Cluster{Name: "flag-cluster", Region: "us-west-2"}, log.Log, first)
+
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "flag-cluster", Region: "us-west-2"}))
+			first.AssertNotCalled(GinkgoT(), "Discover", mock.Anything)
+		})
+
+		It("should use partial values of failing sources and carry on", func() {
+			first.On("Discover", ctx).Return(discovery.Cluster{Region: "cn-north-1"}, errors.New("tag not found"))
+			second.On("Discover", ctx).Return(discovery.Cluster{Name: "my-cluster"}, nil)
+
+			cluster := discovery.Discover(ctx, discovery.Cluster{}, log.Log, first, second)
+
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "my-cluster", Region: "cn-north-1"}))
+		})
+	})
+
+	Describe("EnvSource", func() {
+		It("should read the first variable that is set", func() {
+			env := map[string]string{
+				"EKS_CLUSTER_NAME":   "eks-cluster",
+				"AWS_REGION":         "us-gov-west-1",
+				"AWS_DEFAULT_REGION": "eu-west-1",
+			}
+			source := discovery.NewEnvSourceWithLookup(func(name string) string { return env[name] })
+
+			cluster, err := source.Discover(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "eks-cluster", Region: "us-gov-west-1"}))
+		})
+	})
+
+	Describe("MetadataSource", func() {
+		var api *discoverymocks.MockMetadataAPI
+
+		BeforeEach(func() {
+			api = discoverymocks.NewMockMetadataAPI(GinkgoT())
+		})
+
+		It("should read the region and the cluster name tag of the node", func() {
+			api.On("GetRegion", mock.Anything, &imds.GetRegionInput{}).Return(&imds.GetRegionOutput{Region: "us-gov-east-1"}, nil)
+			api.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{Path: "tags/instance/aws:eks:cluster-name"}).
+				Return(&imds.GetMetadataOutput{Content: io.NopCloser(strings.NewReader("my-cluster\n"))}, nil)
+
+			cluster, err := discovery.NewMetadataSourceWithAPI(api).Discover(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "my-cluster", Region: "us-gov-east-1"}))
+		})
+
+		It("should return the region when instance metadata tags are disabled", func() {
+			api.On("GetRegion", mock.Anything, mock.Anything).Return(&imds.GetRegionOutput{Region: "eu-west-1"}, nil)
+			api.On("GetMetadata", mock.Anything, mock.Anything).Return(nil, errors.New("404 not found"))
+
+			cluster, err := discovery.NewMetadataSourceWithAPI(api).Discover(ctx)
+
+			Expect(err).To(MatchError(ContainSubstring("aws:eks:cluster-name")))
+			Expect(cluster).To(Equal(discovery.Cluster{Region: "eu-west-1"}))
+		})
+	})
+
+	Describe("ConfigMapSource", func() {
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		})
+
+		configMap := func(name string, data map[string]string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: discovery.ConfigMapNamespace},
+				Data:       data,
+			}
+		}
+
+		It("should read the cluster-info ConfigMap", func() {
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				configMap("cluster-info", map[string]string{"cluster-name": "my-cluster", "region": "eu-central-1"}),
+			).Build()
+
+			cluster, err := discovery.NewConfigMapSource(reader).Discover(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(Equal(discovery.Cluster{Name: "my-cluster", Region: "eu-central-1"}))
+		})
+
+		It("should read the region from the cluster endpoint in the kube-proxy kubeconfig", func() {
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				configMap("kube-proxy", map[string]string{"kubeconfig": `
+kind: Config
+apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+    server: https://0123456789ABCDEF0123456789ABCDEF.yl4.cn-northwest-1.eks.amazonaws.com.cn
+  name: default
+`}),
+			).Build()
+
+			cluster, err := discovery.NewConfigMapSource(reader).Discover(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(Equal(discovery.Cluster{Region: "cn-northwest-1"}))
+		})
+
+		It("should return nothing when the ConfigMaps do not exist", func() {
+			reader := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cluster, err := discovery.NewConfigMapSource(reader).Discover(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(Equal(discovery.Cluster{}))
+		})
+	})
+})
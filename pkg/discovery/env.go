@@ -0,0 +1,49 @@
+package discovery
+
+import (
+	"context"
+	"os"
+)
+
+var (
+	// clusterNameEnvVars and regionEnvVars are checked in order
+	clusterNameEnvVars = []string{"CLUSTER_NAME", "EKS_CLUSTER_NAME"}
+	regionEnvVars      = []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
+)
+
+// EnvSource reads the cluster name and region from environment variables
+type EnvSource struct {
+	getenv func(string) string
+}
+
+// NewEnvSource creates an EnvSource reading the environment of the process
+func NewEnvSource() *EnvSource {
+	return NewEnvSourceWithLookup(os.Getenv)
+}
+
+// NewEnvSourceWithLookup creates an EnvSource reading variables with getenv
+func NewEnvSourceWithLookup(getenv func(string) string) *EnvSource {
+	return &EnvSource{getenv: getenv}
+}
+
+// Name identifies the source in logs
+func (s *EnvSource) Name() string {
+	return "environment"
+}
+
+// Discover returns the first non-empty value of the cluster name and region variables
+func (s *EnvSource) Discover(context.Context) (Cluster, error) {
+	return Cluster{
+		Name:   s.first(clusterNameEnvVars),
+		Region: s.first(regionEnvVars),
+	}, nil
+}
+
+func (s *EnvSource) first(names []string) string {
+	for _, name := range names {
+		if value := s.getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+// Package discovery detects the EKS cluster the operator runs in when it is not configured explicitly.
+//
+// The cluster name and region are read from a list of sources, in order, until both are known:
+// the environment variables of the operator pod, which EKS Pod Identity and IRSA set the region
+// in, well-known ConfigMaps in kube-system, and the EC2 instance metadata of the node, whose
+// aws:eks:cluster-name tag is readable when instance metadata tags are enabled.
+package discovery
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+// Cluster identifies the EKS cluster of the operator
+type Cluster struct {
+	Name   string
+	Region string
+}
+
+// complete reports whether both the name and the region are known
+func (c Cluster) complete() bool {
+	return c.Name != "" && c.Region != ""
+}
+
+// Source is a signal the cluster name and region can be discovered from
+type Source interface {
+	// Name identifies the source in logs
+	Name() string
+	// Discover returns the values the source knows, leaving the others empty. Partial values may be
+	// returned along with an error.
+	Discover(ctx context.Context) (Cluster, error)
+}
+
+// MetadataAPI is the subset of the EC2 instance metadata API used by MetadataSource
+type MetadataAPI interface {
+	GetRegion(ctx context.Context, params *imds.GetRegionInput, optFns ...func(*imds.Options)) (*imds.GetRegionOutput, error)
+	GetMetadata(ctx context.Context, params *imds.GetMetadataInput, optFns ...func(*imds.Options)) (*imds.GetMetadataOutput, error)
+}
@@ -0,0 +1,75 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+const (
+	// metadataTimeout bounds the instance metadata lookups, which hang off EC2 or when the hop limit is too low
+	metadataTimeout = 5 * time.Second
+
+	// clusterNameTagPath is the instance metadata path of the tag EKS sets on the nodes of a cluster.
+	// It is only readable when instance metadata tags are enabled on the node.
+	clusterNameTagPath = "tags/instance/aws:eks:cluster-name"
+)
+
+// MetadataSource reads the region and the cluster name tag from the EC2 instance metadata of the node
+type MetadataSource struct {
+	api MetadataAPI
+}
+
+// NewMetadataSource creates a MetadataSource using the instance metadata service of the node
+func NewMetadataSource() *MetadataSource {
+	return NewMetadataSourceWithAPI(imds.New(imds.Options{}))
+}
+
+// NewMetadataSourceWithAPI creates a MetadataSource using the given instance metadata API
+func NewMetadataSourceWithAPI(api MetadataAPI) *MetadataSource {
+	return &MetadataSource{api: api}
+}
+
+// Name identifies the source in logs
+func (s *MetadataSource) Name() string {
+	return "instance metadata"
+}
+
+// Discover returns the region of the node and its aws:eks:cluster-name tag
+func (s *MetadataSource) Discover(ctx context.Context) (Cluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	var cluster Cluster
+	var errs []error
+	if out, err := s.api.GetRegion(ctx, &imds.GetRegionInput{}); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get the region: %w", err))
+	} else {
+		cluster.Region = out.Region
+	}
+
+	name, err := s.clusterNameTag(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get the %s tag: %w", clusterNameTagPath, err))
+	}
+	cluster.Name = name
+	return cluster, errors.Join(errs...)
+}
+
+func (s *MetadataSource) clusterNameTag(ctx context.Context) (string, error) {
+	out, err := s.api.GetMetadata(ctx, &imds.GetMetadataInput{Path: clusterNameTagPath})
+	if err != nil {
+		return "", err
+	}
+	defer out.Content.Close()
+	content, err := io.ReadAll(out.Content)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
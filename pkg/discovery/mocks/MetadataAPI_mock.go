@@ -0,0 +1,205 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package discovery
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockMetadataAPI creates a new instance of MockMetadataAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetadataAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetadataAPI {
+	mock := &MockMetadataAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetadataAPI is an autogenerated mock type for the MetadataAPI type
+type MockMetadataAPI struct {
+	mock.Mock
+}
+
+type MockMetadataAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetadataAPI) EXPECT() *MockMetadataAPI_Expecter {
+	return &MockMetadataAPI_Expecter{mock: &_m.Mock}
+}
+
+// GetMetadata provides a mock function for the type MockMetadataAPI
+func (_mock *MockMetadataAPI) GetMetadata(ctx context.Context, params *imds.GetMetadataInput, optFns ...func(*imds.Options)) (*imds.GetMetadataOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMetadata")
+	}
+
+	var r0 *imds.GetMetadataOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *imds.GetMetadataInput, ...func(*imds.Options)) (*imds.GetMetadataOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *imds.GetMetadataInput, ...func(*imds.Options)) *imds.GetMetadataOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*imds.GetMetadataOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *imds.GetMetadataInput, ...func(*imds.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetadataAPI_GetMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMetadata'
+type MockMetadataAPI_GetMetadata_Call struct {
+	*mock.Call
+}
+
+// GetMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *imds.GetMetadataInput
+//   - optFns ...func(*imds.Options)
+func (_e *MockMetadataAPI_Expecter) GetMetadata(ctx interface{}, params interface{}, optFns ...interface{}) *MockMetadataAPI_GetMetadata_Call {
+	return &MockMetadataAPI_GetMetadata_Call{Call: _e.mock.On("GetMetadata",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockMetadataAPI_GetMetadata_Call) Run(run func(ctx context.Context, params *imds.GetMetadataInput, optFns ...func(*imds.Options))) *MockMetadataAPI_GetMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *imds.GetMetadataInput
+		if args[1] != nil {
+			arg1 = args[1].(*imds.GetMetadataInput)
+		}
+		var arg2 []func(*imds.Options)
+		var variadicArgs []func(*imds.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*imds.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetadataAPI_GetMetadata_Call) Return(getMetadataOutput *imds.GetMetadataOutput, err error) *MockMetadataAPI_GetMetadata_Call {
+	_c.Call.Return(getMetadataOutput, err)
+	return _c
+}
+
+func (_c *MockMetadataAPI_GetMetadata_Call) RunAndReturn(run func(ctx context.Context, params *imds.GetMetadataInput, optFns ...func(*imds.Options)) (*imds.GetMetadataOutput, error)) *MockMetadataAPI_GetMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRegion provides a mock function for the type MockMetadataAPI
+func (_mock *MockMetadataAPI) GetRegion(ctx context.Context, params *imds.GetRegionInput, optFns ...func(*imds.Options)) (*imds.GetRegionOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRegion")
+	}
+
+	var r0 *imds.GetRegionOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *imds.GetRegionInput, ...func(*imds.Options)) (*imds.GetRegionOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *imds.GetRegionInput, ...func(*imds.Options)) *imds.GetRegionOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*imds.GetRegionOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *imds.GetRegionInput, ...func(*imds.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetadataAPI_GetRegion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRegion'
+type MockMetadataAPI_GetRegion_Call struct {
+	*mock.Call
+}
+
+// GetRegion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *imds.GetRegionInput
+//   - optFns ...func(*imds.Options)
+func (_e *MockMetadataAPI_Expecter) GetRegion(ctx interface{}, params interface{}, optFns ...interface{}) *MockMetadataAPI_GetRegion_Call {
+	return &MockMetadataAPI_GetRegion_Call{Call: _e.mock.On("GetRegion",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockMetadataAPI_GetRegion_Call) Run(run func(ctx context.Context, params *imds.GetRegionInput, optFns ...func(*imds.Options))) *MockMetadataAPI_GetRegion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *imds.GetRegionInput
+		if args[1] != nil {
+			arg1 = args[1].(*imds.GetRegionInput)
+		}
+		var arg2 []func(*imds.Options)
+		var variadicArgs []func(*imds.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*imds.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetadataAPI_GetRegion_Call) Return(getRegionOutput *imds.GetRegionOutput, err error) *MockMetadataAPI_GetRegion_Call {
+	_c.Call.Return(getRegionOutput, err)
+	return _c
+}
+
+func (_c *MockMetadataAPI_GetRegion_Call) RunAndReturn(run func(ctx context.Context, params *imds.GetRegionInput, optFns ...func(*imds.Options)) (*imds.GetRegionOutput, error)) *MockMetadataAPI_GetRegion_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package discovery
+
+import (
+	"context"
+
+	"github.com/irenedo/pia-operator/pkg/discovery"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockSource creates a new instance of MockSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSource {
+	mock := &MockSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSource is an autogenerated mock type for the Source type
+type MockSource struct {
+	mock.Mock
+}
+
+type MockSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSource) EXPECT() *MockSource_Expecter {
+	return &MockSource_Expecter{mock: &_m.Mock}
+}
+
+// Discover provides a mock function for the type MockSource
+func (_mock *MockSource) Discover(ctx context.Context) (discovery.Cluster, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Discover")
+	}
+
+	var r0 discovery.Cluster
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (discovery.Cluster, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) discovery.Cluster); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(discovery.Cluster)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSource_Discover_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Discover'
+type MockSource_Discover_Call struct {
+	*mock.Call
+}
+
+// Discover is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSource_Expecter) Discover(ctx interface{}) *MockSource_Discover_Call {
+	return &MockSource_Discover_Call{Call: _e.mock.On("Discover", ctx)}
+}
+
+func (_c *MockSource_Discover_Call) Run(run func(ctx context.Context)) *MockSource_Discover_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSource_Discover_Call) Return(cluster discovery.Cluster, err error) *MockSource_Discover_Call {
+	_c.Call.Return(cluster, err)
+	return _c
+}
+
+func (_c *MockSource_Discover_Call) RunAndReturn(run func(ctx context.Context) (discovery.Cluster, error)) *MockSource_Discover_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function for the type MockSource
+func (_mock *MockSource) Name() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockSource_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type MockSource_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *MockSource_Expecter) Name() *MockSource_Name_Call {
+	return &MockSource_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *MockSource_Name_Call) Run(run func()) *MockSource_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSource_Name_Call) Return(s string) *MockSource_Name_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockSource_Name_Call) RunAndReturn(run func() string) *MockSource_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -2,9 +2,12 @@ package errors
 
 import (
 	"context"
+	stderrors "errors"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/irenedo/pia-operator/pkg/awsclient"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -27,10 +30,28 @@ const (
 	ErrorRetryable                            // Retry immediately
 )
 
+// RetryPolicy controls how often and how quickly failed operations are retried
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxRetryAttempts,
+		BaseDelay:   baseRetryDelay,
+		MaxDelay:    maxRetryDelay,
+	}
+}
+
 // ErrorHandler provides error handling utilities for the controller
 type ErrorHandler struct {
 	client      client.Client
 	log         logr.Logger
+	mu          sync.Mutex
+	policy      RetryPolicy
 	retryCounts map[string]int // key: namespace/name
 }
 
@@ -39,10 +60,25 @@ func NewErrorHandler(client client.Client, log logr.Logger) *ErrorHandler {
 	return &ErrorHandler{
 		client:      client,
 		log:         log,
+		policy:      DefaultRetryPolicy(),
 		retryCounts: make(map[string]int),
 	}
 }
 
+// SetRetryPolicy replaces the retry policy; it is safe to call while reconciles are running
+func (eh *ErrorHandler) SetRetryPolicy(policy RetryPolicy) {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	eh.policy = policy
+}
+
+// GetRetryPolicy returns the active retry policy
+func (eh *ErrorHandler) GetRetryPolicy() RetryPolicy {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	return eh.policy
+}
+
 // ClassifyError analyzes an error and returns its classification (permanent, transient, or retryable).
 // This determines the retry strategy: permanent errors are not retried, transient use backoff, retryable retry immediately.
 func (eh *ErrorHandler) ClassifyError(err error) ErrorClassification {
@@ -50,6 +86,16 @@ func (eh *ErrorHandler) ClassifyError(err error) ErrorClassification {
 		return ErrorRetryable
 	}
 
+	// EKS API errors
+	switch {
+	case stderrors.Is(err, awsclient.ErrAccessDenied), stderrors.Is(err, awsclient.ErrThrottled):
+		// Permissions and credentials can be fixed while the operator runs, so denied requests back off too
+		return ErrorTransient
+	case stderrors.Is(err, awsclient.ErrConflict), stderrors.Is(err, awsclient.ErrAssociationNotFound):
+		// The next reconcile sees the current association, or creates the missing one
+		return ErrorRetryable
+	}
+
 	// Kubernetes API errors
 	if errors.IsNotFound(err) || errors.IsInvalid(err) || errors.IsBadRequest(err) {
 		return ErrorPermanent
@@ -69,28 +115,41 @@ func (eh *ErrorHandler) ClassifyError(err error) ErrorClassification {
 	return ErrorTransient
 }
 
-// CalculateBackoff calculates exponential backoff delay based on retry count, starting from baseRetryDelay.
-// The delay doubles with each retry up to maxRetryDelay to avoid overwhelming external services.
+// CalculateBackoff calculates exponential backoff delay based on retry count, starting from the policy's base delay.
+// The delay doubles with each retry up to the policy's max delay to avoid overwhelming external services.
 func (eh *ErrorHandler) CalculateBackoff(retryCount int) time.Duration {
-	delay := baseRetryDelay
-	for i := 0; i < retryCount && delay < maxRetryDelay; i++ {
+	policy := eh.GetRetryPolicy()
+	return exponentialBackoff(policy.BaseDelay, policy.MaxDelay, retryCount)
+}
+
+// exponentialBackoff doubles base for every retry, capped at max
+func exponentialBackoff(base, max time.Duration, retryCount int) time.Duration {
+	delay := base
+	for i := 0; i < retryCount && delay < max; i++ {
 		delay *= 2
 	}
-	if delay > maxRetryDelay {
-		delay = maxRetryDelay
+	if delay > max {
+		delay = max
 	}
 	return delay
 }
 
 // GetRetryCount gets the current retry count
 func (eh *ErrorHandler) GetRetryCount(sa *corev1.ServiceAccount) int {
-	key := sa.Namespace + "/" + sa.Name
+	return eh.retryCount(sa.Namespace + "/" + sa.Name)
+}
+
+func (eh *ErrorHandler) retryCount(key string) int {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
 	return eh.retryCounts[key]
 }
 
 // SetRetryCount sets the retry count
 func (eh *ErrorHandler) SetRetryCount(ctx context.Context, sa *corev1.ServiceAccount, count int) {
 	key := sa.Namespace + "/" + sa.Name
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
 	eh.retryCounts[key] = count
 }
 
@@ -113,9 +172,10 @@ func (eh *ErrorHandler) HandleError(ctx context.Context, sa *corev1.ServiceAccou
 
 	case ErrorTransient:
 		retryCount := eh.GetRetryCount(sa)
-		if retryCount >= maxRetryAttempts {
+		policy := eh.GetRetryPolicy()
+		if retryCount >= policy.MaxAttempts {
 			log.Error(err, "Max retry attempts reached", "retryCount", retryCount, "operation", operation)
-			return ctrl.Result{RequeueAfter: maxRetryDelay}, nil
+			return ctrl.Result{RequeueAfter: policy.MaxDelay}, nil
 		}
 
 		eh.SetRetryCount(ctx, sa, retryCount+1)
@@ -154,7 +214,13 @@ func (eh *ErrorHandler) HandleDeletionError(ctx context.Context, sa *corev1.Serv
 
 	case ErrorTransient:
 		retryCount := eh.GetRetryCount(sa)
-		if retryCount >= maxRetryAttempts {
+		policy := eh.GetRetryPolicy()
+		if retryCount >= policy.MaxAttempts {
+			if stderrors.Is(err, awsclient.ErrAccessDenied) {
+				// Releasing the finalizer would leak the association the operator cannot delete
+				log.Error(err, "Max retry attempts reached for deletion, keeping the finalizer until access is granted", "retryCount", retryCount)
+				return ctrl.Result{RequeueAfter: policy.MaxDelay}, nil
+			}
 			log.Error(err, "Max retry attempts reached for deletion, continuing anyway", "retryCount", retryCount)
 			return ctrl.Result{}, nil
 		}
@@ -178,7 +244,9 @@ func (eh *ErrorHandler) HandleDeletionError(ctx context.Context, sa *corev1.Serv
 // ResetRetryCount resets the retry count on successful operations
 func (eh *ErrorHandler) ResetRetryCount(ctx context.Context, sa *corev1.ServiceAccount) {
 	key := sa.Namespace + "/" + sa.Name
-	eh.retryCounts[key] = 0
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	delete(eh.retryCounts, key)
 }
 
 // MarkSuccess marks an operation as successful and resets retry count
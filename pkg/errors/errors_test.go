@@ -3,6 +3,7 @@ package errors_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/irenedo/pia-operator/pkg/awsclient"
 	pkgerrors "github.com/irenedo/pia-operator/pkg/errors"
 	mocksclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -108,6 +110,27 @@ var _ = Describe("ErrorHandler", func() {
 			})
 		})
 
+		Context("when error is returned by the EKS API", func() {
+			It("should back off from a denied request", func() {
+				err := &awsclient.Error{Operation: "create", Kind: awsclient.ErrAccessDenied, Err: errors.New("denied")}
+				Expect(errorHandler.ClassifyError(err)).To(Equal(pkgerrors.ErrorTransient))
+			})
+
+			It("should back off from a throttled request", func() {
+				err := fmt.Errorf("wrapped: %w", &awsclient.Error{Operation: "update", Kind: awsclient.ErrThrottled, Err: errors.New("slow down")})
+				Expect(errorHandler.ClassifyError(err)).To(Equal(pkgerrors.ErrorTransient))
+			})
+
+			It("should retry conflicts and missing associations immediately", func() {
+				Expect(errorHandler.ClassifyError(&awsclient.Error{Operation: "create", Kind: awsclient.ErrConflict, Err: errors.New("in use")})).To(Equal(pkgerrors.ErrorRetryable))
+				Expect(errorHandler.ClassifyError(fmt.Errorf("%w for ServiceAccount default/test-sa", awsclient.ErrAssociationNotFound))).To(Equal(pkgerrors.ErrorRetryable))
+			})
+
+			It("should not classify an error by its message", func() {
+				Expect(errorHandler.ClassifyError(errors.New("role not found"))).To(Equal(pkgerrors.ErrorTransient))
+			})
+		})
+
 		Context("when error is unknown", func() {
 			It("should return ErrorTransient for unknown error types", func() {
 				err := k8serrors.NewInternalError(errors.New("internal error"))
@@ -139,6 +162,39 @@ var _ = Describe("ErrorHandler", func() {
 		})
 	})
 
+	Describe("SetRetryPolicy", func() {
+		It("should use the default policy when none is set", func() {
+			Expect(errorHandler.GetRetryPolicy()).To(Equal(pkgerrors.DefaultRetryPolicy()))
+		})
+
+		It("should calculate backoff from the configured policy", func() {
+			errorHandler.SetRetryPolicy(pkgerrors.RetryPolicy{
+				MaxAttempts: 2,
+				BaseDelay:   time.Second,
+				MaxDelay:    3 * time.Second,
+			})
+
+			Expect(errorHandler.CalculateBackoff(0)).To(Equal(time.Second))
+			Expect(errorHandler.CalculateBackoff(1)).To(Equal(2 * time.Second))
+			Expect(errorHandler.CalculateBackoff(5)).To(Equal(3 * time.Second))
+		})
+
+		It("should stop retrying after the configured max attempts", func() {
+			errorHandler.SetRetryPolicy(pkgerrors.RetryPolicy{
+				MaxAttempts: 2,
+				BaseDelay:   time.Second,
+				MaxDelay:    3 * time.Second,
+			})
+			errorHandler.SetRetryCount(ctx, serviceAccount, 2)
+
+			timeoutErr := k8serrors.NewTimeoutError("timeout", 30)
+			result, err := errorHandler.HandleError(ctx, serviceAccount, timeoutErr, "test")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(3 * time.Second))
+		})
+	})
+
 	Describe("GetRetryCount and SetRetryCount", func() {
 		It("should return 0 for new service account", func() {
 			count := errorHandler.GetRetryCount(serviceAccount)
@@ -291,6 +347,16 @@ var _ = Describe("ErrorHandler", func() {
 				Expect(result.Requeue).To(BeFalse())
 				Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
 			})
+
+			It("should keep retrying a denied deletion after max attempts", func() {
+				deniedErr := &awsclient.Error{Operation: "delete", Kind: awsclient.ErrAccessDenied, Err: errors.New("denied")}
+				errorHandler.SetRetryCount(ctx, serviceAccount, 5)
+
+				result, err := errorHandler.HandleDeletionError(ctx, serviceAccount, deniedErr, "delete")
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(errorHandler.GetRetryPolicy().MaxDelay))
+			})
 		})
 	})
 
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// fastRetryDelay is the first delay for requeues the ErrorHandler does not track, such as conflicts
+	fastRetryDelay = 5 * time.Millisecond
+
+	// Overall limit shared by all ServiceAccounts, matching the controller-runtime default
+	overallQPS   = 10
+	overallBurst = 100
+)
+
+// RateLimiter is a workqueue rate limiter that reuses the ErrorHandler's backoff state.
+// ServiceAccounts with a pending transient failure are delayed by the ErrorHandler's backoff
+// for their current retry count, so a requeue never undercuts it. Other requeues, such as
+// optimistic locking conflicts, back off exponentially from a few milliseconds up to the base
+// delay of the retry policy.
+type RateLimiter struct {
+	handler  *ErrorHandler
+	mu       sync.Mutex
+	requeues map[interface{}]int
+}
+
+// NewRateLimiter creates a RateLimiter for the given ErrorHandler
+func NewRateLimiter(handler *ErrorHandler) *RateLimiter {
+	return &RateLimiter{
+		handler:  handler,
+		requeues: make(map[interface{}]int),
+	}
+}
+
+// ControllerRateLimiter combines the RateLimiter with an overall token bucket so that the
+// controller as a whole cannot exceed the default request rate
+func (eh *ErrorHandler) ControllerRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		NewRateLimiter(eh),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(overallQPS), overallBurst)},
+	)
+}
+
+// When returns how long the item has to wait before it is processed again
+func (rl *RateLimiter) When(item interface{}) time.Duration {
+	rl.mu.Lock()
+	requeues := rl.requeues[item]
+	rl.requeues[item] = requeues + 1
+	rl.mu.Unlock()
+
+	if req, ok := item.(reconcile.Request); ok {
+		if retryCount := rl.handler.retryCount(req.Namespace + "/" + req.Name); retryCount > 0 {
+			return rl.handler.CalculateBackoff(retryCount - 1)
+		}
+	}
+	return exponentialBackoff(fastRetryDelay, rl.handler.GetRetryPolicy().BaseDelay, requeues)
+}
+
+// Forget clears the requeue count of the item. The ErrorHandler's retry count is kept,
+// since it is only reset when the operation finally succeeds.
+func (rl *RateLimiter) Forget(item interface{}) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.requeues, item)
+}
+
+// NumRequeues returns how many times the item was requeued since it was last forgotten
+func (rl *RateLimiter) NumRequeues(item interface{}) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.requeues[item]
+}
@@ -0,0 +1,80 @@
+package errors_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pkgerrors "github.com/irenedo/pia-operator/pkg/errors"
+	mocksclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var (
+		ctx            context.Context
+		errorHandler   *pkgerrors.ErrorHandler
+		rateLimiter    *pkgerrors.RateLimiter
+		serviceAccount *corev1.ServiceAccount
+		req            reconcile.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		errorHandler = pkgerrors.NewErrorHandler(mocksclient.NewClientBuilder().Build(), log.Log.WithName("test-rate-limiter"))
+		rateLimiter = pkgerrors.NewRateLimiter(errorHandler)
+
+		serviceAccount = &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sa",
+				Namespace: "default",
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sa"}}
+	})
+
+	It("should back off quickly for requeues without a pending failure", func() {
+		Expect(rateLimiter.When(req)).To(Equal(5 * time.Millisecond))
+		Expect(rateLimiter.When(req)).To(Equal(10 * time.Millisecond))
+		Expect(rateLimiter.NumRequeues(req)).To(Equal(2))
+	})
+
+	It("should not exceed the base delay of the retry policy for untracked requeues", func() {
+		errorHandler.SetRetryPolicy(pkgerrors.RetryPolicy{MaxAttempts: 5, BaseDelay: 20 * time.Millisecond, MaxDelay: time.Second})
+
+		for i := 0; i < 10; i++ {
+			rateLimiter.When(req)
+		}
+
+		Expect(rateLimiter.When(req)).To(Equal(20 * time.Millisecond))
+	})
+
+	It("should use the error handler backoff for ServiceAccounts with pending failures", func() {
+		errorHandler.SetRetryCount(ctx, serviceAccount, 3)
+
+		Expect(rateLimiter.When(req)).To(Equal(errorHandler.CalculateBackoff(2)))
+	})
+
+	It("should forget requeues but keep the error handler retry count", func() {
+		errorHandler.SetRetryCount(ctx, serviceAccount, 1)
+		rateLimiter.When(req)
+
+		rateLimiter.Forget(req)
+
+		Expect(rateLimiter.NumRequeues(req)).To(Equal(0))
+		Expect(errorHandler.GetRetryCount(serviceAccount)).To(Equal(1))
+	})
+
+	It("should never undercut the overall controller rate limiter", func() {
+		limiter := errorHandler.ControllerRateLimiter()
+		errorHandler.SetRetryCount(ctx, serviceAccount, 1)
+
+		Expect(limiter.When(req)).To(Equal(30 * time.Second))
+	})
+})
@@ -0,0 +1,13 @@
+package fakeeks_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFakeEKS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FakeEKS Suite")
+}
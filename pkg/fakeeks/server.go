@@ -0,0 +1,576 @@
+// Package fakeeks provides an in-process fake of the EKS Pod Identity Association API for tests.
+//
+// The Server speaks the REST-JSON protocol of EKS over HTTP, so the AWS SDK is used unchanged by
+// pointing its endpoint at the server. It keeps associations per cluster in memory and follows the
+// semantics of EKS that the operator relies on: a single association per ServiceAccount, idempotent
+// creates with a client request token, which keep returning the association they created after it
+// was deleted, paginated and filtered lists, and the error types returned for missing clusters and
+// associations. Faults and throttling can be injected per operation.
+package fakeeks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"golang.org/x/time/rate"
+)
+
+// Operation names an EKS API operation served by the Server
+type Operation string
+
+const (
+	OperationCreate   Operation = "CreatePodIdentityAssociation"
+	OperationDescribe Operation = "DescribePodIdentityAssociation"
+	OperationUpdate   Operation = "UpdatePodIdentityAssociation"
+	OperationDelete   Operation = "DeletePodIdentityAssociation"
+	OperationList     Operation = "ListPodIdentityAssociations"
+)
+
+const (
+	// DefaultPageSize is the number of associations returned per page when maxResults is not set
+	DefaultPageSize = 100
+
+	// Region and account of the fake API
+	Region    = "us-east-1"
+	AccountID = "123456789012"
+)
+
+// Association is a Pod Identity Association stored by the Server
+type Association struct {
+	ID                 string
+	ClusterName        string
+	Namespace          string
+	ServiceAccount     string
+	RoleArn            string
+	TargetRoleArn      string
+	DisableSessionTags bool
+	Policy             string
+	Tags               map[string]string
+	CreatedAt          time.Time
+	ModifiedAt         time.Time
+}
+
+// Fault is an error returned by an operation instead of serving it
+type Fault struct {
+	// Code is the EKS error type, such as ServerException or ThrottlingException
+	Code    string
+	Message string
+	// Status is the HTTP status code of the error response
+	Status int
+	// Times is how many requests fail; zero fails every request until the fault is cleared
+	Times int
+}
+
+// Server is a fake EKS Pod Identity Association API
+type Server struct {
+	httpServer *httptest.Server
+
+	mu           sync.Mutex
+	clusters     map[string]bool
+	associations map[string]*Association
+	tokens       map[string]*Association
+	faults       map[Operation][]*Fault
+	limiter      *rate.Limiter
+	calls        map[Operation]int
+	nextID       int
+	pageSize     int
+}
+
+// NewServer starts a fake EKS API serving the given clusters. Close must be called to stop it.
+func NewServer(clusterNames ...string) *Server {
+	s := &Server{
+		clusters:     make(map[string]bool),
+		associations: make(map[string]*Association),
+		tokens:       make(map[string]*Association),
+		faults:       make(map[Operation][]*Fault),
+		calls:        make(map[Operation]int),
+		pageSize:     DefaultPageSize,
+	}
+	for _, name := range clusterNames {
+		s.clusters[name] = true
+	}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the endpoint of the server
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client returns an EKS client sending its requests to the server with static credentials.
+// Retries are disabled so that injected faults reach the caller; optFns may enable them again.
+func (s *Server) Client(optFns ...func(*eks.Options)) *eks.Client {
+	cfg := aws.Config{
+		Region:       Region,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDFAKEEKS", "secret", ""),
+		BaseEndpoint: aws.String(s.URL()),
+		Retryer:      func() aws.Retryer { return aws.NopRetryer{} },
+	}
+	return eks.NewFromConfig(cfg, optFns...)
+}
+
+// SetPageSize caps the associations returned by a single list call
+func (s *Server) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = size
+}
+
+// AddCluster makes the server serve another cluster
+func (s *Server) AddCluster(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clusters[name] = true
+}
+
+// InjectFault makes the next requests of the operation fail with the fault
+func (s *Server) InjectFault(op Operation, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fault.Status == 0 {
+		fault.Status = http.StatusInternalServerError
+	}
+	s.faults[op] = append(s.faults[op], &fault)
+}
+
+// Throttle makes the next requests of the operation fail with ThrottlingException
+func (s *Server) Throttle(op Operation, times int) {
+	s.InjectFault(op, Fault{
+		Code:    "ThrottlingException",
+		Message: "Rate exceeded",
+		Status:  http.StatusTooManyRequests,
+		Times:   times,
+	})
+}
+
+// SetRateLimit throttles all requests above the rate; a zero rate removes the limit
+func (s *Server) SetRateLimit(r rate.Limit, burst int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r == 0 {
+		s.limiter = nil
+		return
+	}
+	s.limiter = rate.NewLimiter(r, burst)
+}
+
+// ClearFaults removes all injected faults and the rate limit
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[Operation][]*Fault)
+	s.limiter = nil
+}
+
+// Calls returns how many requests of the operation were received, including failed ones
+func (s *Server) Calls(op Operation) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[op]
+}
+
+// Put stores an association directly, such as one created outside of the operator, and returns its ID
+func (s *Server) Put(a Association) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.ID == "" {
+		a.ID = s.newID()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+		a.ModifiedAt = a.CreatedAt
+	}
+	s.clusters[a.ClusterName] = true
+	s.associations[a.ID] = &a
+	return a.ID
+}
+
+// Get returns a copy of the association with the ID
+func (s *Server) Get(id string) (Association, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.associations[id]
+	if !ok {
+		return Association{}, false
+	}
+	return copyAssociation(a), true
+}
+
+// Remove deletes an association directly, such as one deleted outside of the operator
+func (s *Server) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.associations, id)
+}
+
+// Associations returns copies of all associations of the cluster sorted by ID
+func (s *Server) Associations(clusterName string) []Association {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var associations []Association
+	for _, a := range s.sorted(clusterName) {
+		associations = append(associations, copyAssociation(a))
+	}
+	return associations
+}
+
+// apiError is an error response of the EKS API
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func notFound(format string, args ...interface{}) *apiError {
+	return &apiError{status: http.StatusNotFound, code: "ResourceNotFoundException", message: fmt.Sprintf(format, args...)}
+}
+
+// policyWithSessionTags rejects a session policy on an association with session tags, as EKS does
+const policyWithSessionTags = "disableSessionTags must be true when a policy is specified"
+
+func invalidParameter(format string, args ...interface{}) *apiError {
+	return &apiError{status: http.StatusBadRequest, code: "InvalidParameterException", message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// Paths are /clusters/{clusterName}/pod-identity-associations[/{associationId}]
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[0] != "clusters" || parts[2] != "pod-identity-associations" {
+		writeError(w, &apiError{status: http.StatusNotFound, code: "UnknownOperationException", message: "unsupported path " + r.URL.Path})
+		return
+	}
+	clusterName, associationID := parts[1], ""
+	if len(parts) == 4 {
+		associationID = parts[3]
+	}
+
+	var op Operation
+	switch {
+	case associationID == "" && r.Method == http.MethodPost:
+		op = OperationCreate
+	case associationID == "" && r.Method == http.MethodGet:
+		op = OperationList
+	case r.Method == http.MethodGet:
+		op = OperationDescribe
+	case r.Method == http.MethodPost:
+		op = OperationUpdate
+	case r.Method == http.MethodDelete:
+		op = OperationDelete
+	default:
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, code: "UnknownOperationException", message: "unsupported method " + r.Method})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[op]++
+
+	if apiErr := s.injectedFault(op); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if !s.clusters[clusterName] {
+		writeError(w, notFound("No cluster found for name: %s.", clusterName))
+		return
+	}
+
+	var (
+		response interface{}
+		apiErr   *apiError
+	)
+	switch op {
+	case OperationCreate:
+		response, apiErr = s.create(r, clusterName)
+	case OperationList:
+		response, apiErr = s.list(r, clusterName)
+	case OperationDescribe:
+		response, apiErr = s.describe(clusterName, associationID)
+	case OperationUpdate:
+		response, apiErr = s.update(r, clusterName, associationID)
+	case OperationDelete:
+		response, apiErr = s.delete(clusterName, associationID)
+	}
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// injectedFault returns the error of the next fault or the rate limit of the operation, if any
+func (s *Server) injectedFault(op Operation) *apiError {
+	if s.limiter != nil && !s.limiter.Allow() {
+		return &apiError{status: http.StatusTooManyRequests, code: "ThrottlingException", message: "Rate exceeded"}
+	}
+	faults := s.faults[op]
+	if len(faults) == 0 {
+		return nil
+	}
+	fault := faults[0]
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			s.faults[op] = faults[1:]
+		}
+	}
+	return &apiError{status: fault.Status, code: fault.Code, message: fault.Message}
+}
+
+type createRequest struct {
+	Namespace          string            `json:"namespace"`
+	ServiceAccount     string            `json:"serviceAccount"`
+	RoleArn            string            `json:"roleArn"`
+	TargetRoleArn      string            `json:"targetRoleArn"`
+	DisableSessionTags bool              `json:"disableSessionTags"`
+	Policy             string            `json:"policy"`
+	Tags               map[string]string `json:"tags"`
+	ClientRequestToken string            `json:"clientRequestToken"`
+}
+
+func (s *Server) create(r *http.Request, clusterName string) (interface{}, *apiError) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, invalidParameter("invalid request body: %v", err)
+	}
+	if req.Namespace == "" || req.ServiceAccount == "" || req.RoleArn == "" {
+		return nil, invalidParameter("namespace, serviceAccount and roleArn are required")
+	}
+	if req.Policy != "" && !req.DisableSessionTags {
+		return nil, invalidParameter(policyWithSessionTags)
+	}
+
+	if req.ClientRequestToken != "" {
+		if created, ok := s.tokens[clusterName+"/"+req.ClientRequestToken]; ok {
+			if a, ok := s.associations[created.ID]; ok {
+				return map[string]interface{}{"association": associationDocument(a)}, nil
+			}
+			// Like EKS, the token still returns the association it created after that was deleted
+			return map[string]interface{}{"association": associationDocument(created)}, nil
+		}
+	}
+	for _, a := range s.associations {
+		if a.ClusterName == clusterName && a.Namespace == req.Namespace && a.ServiceAccount == req.ServiceAccount {
+			return nil, &apiError{
+				status:  http.StatusConflict,
+				code:    "ResourceInUseException",
+				message: fmt.Sprintf("Association already exists: %s", a.ID),
+			}
+		}
+	}
+
+	now := time.Now()
+	a := &Association{
+		ID:                 s.newID(),
+		ClusterName:        clusterName,
+		Namespace:          req.Namespace,
+		ServiceAccount:     req.ServiceAccount,
+		RoleArn:            req.RoleArn,
+		TargetRoleArn:      req.TargetRoleArn,
+		DisableSessionTags: req.DisableSessionTags,
+		Policy:             req.Policy,
+		Tags:               req.Tags,
+		CreatedAt:          now,
+		ModifiedAt:         now,
+	}
+	s.associations[a.ID] = a
+	if req.ClientRequestToken != "" {
+		created := *a
+		s.tokens[clusterName+"/"+req.ClientRequestToken] = &created
+	}
+	return map[string]interface{}{"association": associationDocument(a)}, nil
+}
+
+func (s *Server) list(r *http.Request, clusterName string) (interface{}, *apiError) {
+	query := r.URL.Query()
+	pageSize := s.pageSize
+	if maxResults := query.Get("maxResults"); maxResults != "" {
+		n, err := strconv.Atoi(maxResults)
+		if err != nil || n < 1 || n > 100 {
+			return nil, invalidParameter("maxResults must be between 1 and 100")
+		}
+		if n < pageSize {
+			pageSize = n
+		}
+	}
+	offset := 0
+	if nextToken := query.Get("nextToken"); nextToken != "" {
+		n, err := strconv.Atoi(nextToken)
+		if err != nil || n < 0 {
+			return nil, invalidParameter("invalid nextToken")
+		}
+		offset = n
+	}
+
+	var matching []*Association
+	for _, a := range s.sorted(clusterName) {
+		if namespace := query.Get("namespace"); namespace != "" && a.Namespace != namespace {
+			continue
+		}
+		if serviceAccount := query.Get("serviceAccount"); serviceAccount != "" && a.ServiceAccount != serviceAccount {
+			continue
+		}
+		matching = append(matching, a)
+	}
+
+	summaries := []interface{}{}
+	for i := offset; i < len(matching) && i < offset+pageSize; i++ {
+		a := matching[i]
+		summaries = append(summaries, map[string]interface{}{
+			"associationArn": associationArn(a),
+			"associationId":  a.ID,
+			"clusterName":    a.ClusterName,
+			"namespace":      a.Namespace,
+			"serviceAccount": a.ServiceAccount,
+		})
+	}
+	response := map[string]interface{}{"associations": summaries}
+	if offset+pageSize < len(matching) {
+		response["nextToken"] = strconv.Itoa(offset + pageSize)
+	}
+	return response, nil
+}
+
+func (s *Server) describe(clusterName, associationID string) (interface{}, *apiError) {
+	a, apiErr := s.lookup(clusterName, associationID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return map[string]interface{}{"association": associationDocument(a)}, nil
+}
+
+type updateRequest struct {
+	RoleArn            *string `json:"roleArn"`
+	TargetRoleArn      *string `json:"targetRoleArn"`
+	DisableSessionTags *bool   `json:"disableSessionTags"`
+	Policy             *string `json:"policy"`
+}
+
+func (s *Server) update(r *http.Request, clusterName, associationID string) (interface{}, *apiError) {
+	a, apiErr := s.lookup(clusterName, associationID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	var req updateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, invalidParameter("invalid request body: %v", err)
+	}
+
+	updated := *a
+	if req.RoleArn != nil {
+		updated.RoleArn = *req.RoleArn
+	}
+	// EKS keeps the target role and the policy unless another one is set; an empty value removes them
+	if req.TargetRoleArn != nil {
+		updated.TargetRoleArn = *req.TargetRoleArn
+	}
+	if req.DisableSessionTags != nil {
+		updated.DisableSessionTags = *req.DisableSessionTags
+	}
+	if req.Policy != nil {
+		updated.Policy = *req.Policy
+	}
+	if updated.Policy != "" && !updated.DisableSessionTags {
+		return nil, invalidParameter(policyWithSessionTags)
+	}
+	*a = updated
+	a.ModifiedAt = time.Now()
+	return map[string]interface{}{"association": associationDocument(a)}, nil
+}
+
+func (s *Server) delete(clusterName, associationID string) (interface{}, *apiError) {
+	a, apiErr := s.lookup(clusterName, associationID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	delete(s.associations, a.ID)
+	return map[string]interface{}{"association": associationDocument(a)}, nil
+}
+
+func (s *Server) lookup(clusterName, associationID string) (*Association, *apiError) {
+	a, ok := s.associations[associationID]
+	if !ok || a.ClusterName != clusterName {
+		return nil, notFound("No Pod Identity Association found for id: %s.", associationID)
+	}
+	return a, nil
+}
+
+// sorted returns the associations of the cluster in a stable order for pagination
+func (s *Server) sorted(clusterName string) []*Association {
+	var associations []*Association
+	for _, a := range s.associations {
+		if a.ClusterName == clusterName {
+			associations = append(associations, a)
+		}
+	}
+	sort.Slice(associations, func(i, j int) bool { return associations[i].ID < associations[j].ID })
+	return associations
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("a-%017d", s.nextID)
+}
+
+func associationArn(a *Association) string {
+	return fmt.Sprintf("arn:aws:eks:%s:%s:podidentityassociation/%s/%s", Region, AccountID, a.ClusterName, a.ID)
+}
+
+func associationDocument(a *Association) map[string]interface{} {
+	document := map[string]interface{}{
+		"associationArn":     associationArn(a),
+		"associationId":      a.ID,
+		"clusterName":        a.ClusterName,
+		"namespace":          a.Namespace,
+		"serviceAccount":     a.ServiceAccount,
+		"roleArn":            a.RoleArn,
+		"disableSessionTags": a.DisableSessionTags,
+		"tags":               a.Tags,
+		"createdAt":          float64(a.CreatedAt.UnixMilli()) / 1000,
+		"modifiedAt":         float64(a.ModifiedAt.UnixMilli()) / 1000,
+	}
+	if a.TargetRoleArn != "" {
+		document["targetRoleArn"] = a.TargetRoleArn
+	}
+	if a.Policy != "" {
+		document["policy"] = a.Policy
+	}
+	if a.Tags == nil {
+		document["tags"] = map[string]string{}
+	}
+	return document
+}
+
+func copyAssociation(a *Association) Association {
+	c := *a
+	if a.Tags != nil {
+		c.Tags = make(map[string]string, len(a.Tags))
+		for k, v := range a.Tags {
+			c.Tags[k] = v
+		}
+	}
+	return c
+}
+
+func writeError(w http.ResponseWriter, apiErr *apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-ErrorType", apiErr.code)
+	w.WriteHeader(apiErr.status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": apiErr.message})
+}
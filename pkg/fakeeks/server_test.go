@@ -0,0 +1,256 @@
+package fakeeks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/awsclient"
+	"github.com/irenedo/pia-operator/pkg/fakeeks"
+)
+
+var _ = Describe("Server", func() {
+	const (
+		clusterName = "test-cluster"
+		roleArn     = "arn:aws:iam::123456789012:role/test-role"
+		targetArn   = "arn:aws:iam::987654321098:role/target-role"
+	)
+
+	var (
+		ctx    context.Context
+		server *fakeeks.Server
+		client *awsclient.Client
+		sa     *corev1.ServiceAccount
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = fakeeks.NewServer(clusterName)
+		DeferCleanup(server.Close)
+		client = awsclient.NewClientWithAPI(server.Client(), clusterName, fakeeks.Region, log.Log.WithName("test"))
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+		}}
+	})
+
+	It("should create, describe, update and delete an association", func() {
+		associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, targetArn, awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		stored, ok := server.Get(associationID)
+		Expect(ok).To(BeTrue())
+		Expect(stored.Namespace).To(Equal("default"))
+		Expect(stored.ServiceAccount).To(Equal("app"))
+		Expect(stored.TargetRoleArn).To(Equal(targetArn))
+		Expect(stored.Tags).To(HaveKeyWithValue("managed-by", "pia-operator"))
+		Expect(stored.Tags).To(HaveKeyWithValue("assume-role", targetArn))
+
+		association, err := client.CheckAssociationReady(ctx, associationID, roleArn, targetArn, awsclient.SessionOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(association.Ready()).To(BeTrue())
+		Expect(association.Tags).To(HaveKeyWithValue("serviceaccount", "app"))
+		Expect(association.CreatedAt).ToNot(BeNil())
+
+		_, err = client.UpdatePodIdentityAssociation(ctx, sa, "arn:aws:iam::123456789012:role/other-role", targetArn, awsclient.SessionOptions{TaggingEnabled: false})
+		Expect(err).ToNot(HaveOccurred())
+		stored, _ = server.Get(associationID)
+		Expect(stored.RoleArn).To(Equal("arn:aws:iam::123456789012:role/other-role"))
+		Expect(stored.DisableSessionTags).To(BeTrue())
+
+		Expect(client.DeletePodIdentityAssociation(ctx, sa)).To(Succeed())
+		Expect(server.Associations(clusterName)).To(BeEmpty())
+
+		exists, err := client.AssociationExists(ctx, sa)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should store the session policy of associations without session tags", func() {
+		const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+
+		_, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true, Policy: policy})
+		var invalid *types.InvalidParameterException
+		Expect(errors.As(err, &invalid)).To(BeTrue())
+
+		associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{Policy: policy})
+		Expect(err).ToNot(HaveOccurred())
+		stored, _ := server.Get(associationID)
+		Expect(stored.Policy).To(Equal(policy))
+
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/association-id": associationID}
+		association, err := client.GetPodIdentityAssociation(ctx, sa)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(association.SessionPolicy).To(Equal(policy))
+
+		// Updating without a policy removes it
+		_, err = client.UpdatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		stored, _ = server.Get(associationID)
+		Expect(stored.Policy).To(BeEmpty())
+		Expect(stored.DisableSessionTags).To(BeFalse())
+	})
+
+	It("should remove the target role when the assume role is removed", func() {
+		associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, targetArn, awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/association-id": associationID}
+		_, err = client.UpdatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		stored, _ := server.Get(associationID)
+		Expect(stored.TargetRoleArn).To(BeEmpty())
+
+		association, err := client.CheckAssociationReady(ctx, associationID, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(association.Ready()).To(BeTrue())
+	})
+
+	It("should allow a single association per ServiceAccount", func() {
+		existingID := server.Put(fakeeks.Association{
+			ClusterName:    clusterName,
+			Namespace:      "default",
+			ServiceAccount: "app",
+			RoleArn:        "arn:aws:iam::123456789012:role/old-role",
+		})
+
+		_, err := server.Client().CreatePodIdentityAssociation(ctx, &eks.CreatePodIdentityAssociationInput{
+			ClusterName:    aws.String(clusterName),
+			Namespace:      aws.String("default"),
+			ServiceAccount: aws.String("app"),
+			RoleArn:        aws.String(roleArn),
+		})
+		var inUse *types.ResourceInUseException
+		Expect(errors.As(err, &inUse)).To(BeTrue())
+
+		// The operator adopts the existing association
+		associationID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(associationID).To(Equal(existingID))
+		stored, _ := server.Get(existingID)
+		Expect(stored.RoleArn).To(Equal(roleArn))
+	})
+
+	It("should return the same association for a repeated client request token", func() {
+		input := &eks.CreatePodIdentityAssociationInput{
+			ClusterName:        aws.String(clusterName),
+			Namespace:          aws.String("default"),
+			ServiceAccount:     aws.String("app"),
+			RoleArn:            aws.String(roleArn),
+			ClientRequestToken: aws.String("token"),
+		}
+		first, err := server.Client().CreatePodIdentityAssociation(ctx, input)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := server.Client().CreatePodIdentityAssociation(ctx, input)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(second.Association.AssociationId)).To(Equal(aws.ToString(first.Association.AssociationId)))
+	})
+
+	It("should create a new association when a removed role is added again with a new request nonce", func() {
+		sa.UID = "uid-1"
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/request-nonce": "first"}
+		firstID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeletePodIdentityAssociation(ctx, sa)).To(Succeed())
+
+		// The same token returns the deleted association
+		replayed := &corev1.ServiceAccount{ObjectMeta: *sa.ObjectMeta.DeepCopy()}
+		delete(replayed.Annotations, "pia-operator.eks.aws.com/association-id")
+		replayedID, err := client.CreatePodIdentityAssociation(ctx, replayed, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(replayedID).To(Equal(firstID))
+		Expect(server.Associations(clusterName)).To(BeEmpty())
+
+		// The operator replaces the nonce when it deletes the association
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/request-nonce": "second"}
+		secondID, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secondID).ToNot(Equal(firstID))
+		stored, ok := server.Get(secondID)
+		Expect(ok).To(BeTrue())
+		Expect(stored.Tags).To(HaveKeyWithValue("serviceaccount-uid", "uid-1"))
+	})
+
+	It("should paginate and filter listed associations", func() {
+		for i := 0; i < 5; i++ {
+			server.Put(fakeeks.Association{
+				ClusterName:    clusterName,
+				Namespace:      "default",
+				ServiceAccount: fmt.Sprintf("sa-%d", i),
+				RoleArn:        roleArn,
+			})
+		}
+		server.Put(fakeeks.Association{ClusterName: clusterName, Namespace: "other", ServiceAccount: "sa-0", RoleArn: roleArn})
+		server.Put(fakeeks.Association{ClusterName: "other-cluster", Namespace: "default", ServiceAccount: "sa-0", RoleArn: roleArn})
+		server.SetPageSize(2)
+
+		associations, err := client.ListPodIdentityAssociations(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(associations).To(HaveLen(6))
+		Expect(server.Calls(fakeeks.OperationList)).To(Equal(3))
+
+		output, err := server.Client().ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
+			ClusterName: aws.String(clusterName),
+			Namespace:   aws.String("other"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output.Associations).To(HaveLen(1))
+		Expect(aws.ToString(output.Associations[0].ServiceAccount)).To(Equal("sa-0"))
+	})
+
+	It("should report missing clusters and associations", func() {
+		_, err := server.Client().ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
+			ClusterName: aws.String("missing-cluster"),
+		})
+		var notFound *types.ResourceNotFoundException
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/association-id": "a-missing"}
+		_, err = client.GetPodIdentityAssociation(ctx, sa)
+		Expect(errors.Is(err, awsclient.ErrAssociationNotFound)).To(BeTrue())
+	})
+
+	It("should throttle requests", func() {
+		server.Throttle(fakeeks.OperationCreate, 1)
+
+		_, err := client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(errors.Is(err, awsclient.ErrThrottled)).To(BeTrue())
+
+		_, err = client.CreatePodIdentityAssociation(ctx, sa, roleArn, "", awsclient.SessionOptions{TaggingEnabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Calls(fakeeks.OperationCreate)).To(Equal(2))
+	})
+
+	It("should throttle requests above the rate limit", func() {
+		server.SetRateLimit(1, 1)
+
+		_, err := client.ListPodIdentityAssociations(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.ListPodIdentityAssociations(ctx)
+		Expect(errors.Is(err, awsclient.ErrThrottled)).To(BeTrue())
+	})
+
+	It("should inject faults until they are cleared", func() {
+		server.InjectFault(fakeeks.OperationDescribe, fakeeks.Fault{Code: "ServerException", Message: "internal failure"})
+		sa.Annotations = map[string]string{"pia-operator.eks.aws.com/association-id": "a-any"}
+
+		for i := 0; i < 2; i++ {
+			_, err := client.GetPodIdentityAssociation(ctx, sa)
+			var serverErr *types.ServerException
+			Expect(errors.As(err, &serverErr)).To(BeTrue())
+		}
+
+		server.ClearFaults()
+		_, err := client.GetPodIdentityAssociation(ctx, sa)
+		Expect(errors.Is(err, awsclient.ErrAssociationNotFound)).To(BeTrue())
+	})
+})
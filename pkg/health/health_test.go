@@ -0,0 +1,132 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/irenedo/pia-operator/pkg/health"
+)
+
+var _ = Describe("Health checks", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	clock := func() time.Time { return now }
+
+	Describe("CachedCheck", func() {
+		var (
+			calls  int
+			result error
+			check  *health.CachedCheck
+		)
+
+		BeforeEach(func() {
+			calls, result = 0, nil
+			check = health.NewCachedCheck(func(ctx context.Context) error {
+				calls++
+				_, hasDeadline := ctx.Deadline()
+				Expect(hasDeadline).To(BeTrue())
+				return result
+			}, 30*time.Second, 5*time.Second).WithClock(clock)
+		})
+
+		run := func() error {
+			return check.Check(httptest.NewRequest("GET", "/readyz", nil))
+		}
+
+		It("should reuse the result within the interval", func() {
+			Expect(run()).To(Succeed())
+			result = errors.New("credentials expired")
+			now = now.Add(10 * time.Second)
+
+			Expect(run()).To(Succeed())
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should check again after the interval", func() {
+			Expect(run()).To(Succeed())
+			result = errors.New("credentials expired")
+			now = now.Add(31 * time.Second)
+
+			Expect(run()).To(MatchError("credentials expired"))
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should cache failures too", func() {
+			result = errors.New("cluster not found")
+			Expect(run()).To(HaveOccurred())
+			result = nil
+			now = now.Add(time.Second)
+
+			Expect(run()).To(MatchError("cluster not found"))
+			Expect(calls).To(Equal(1))
+		})
+	})
+
+	Describe("WorkqueueCheck", func() {
+		var (
+			registry       *prometheus.Registry
+			depth          *prometheus.GaugeVec
+			workDuration   *prometheus.HistogramVec
+			longestRunning *prometheus.GaugeVec
+			check          *health.WorkqueueCheck
+		)
+
+		BeforeEach(func() {
+			registry = prometheus.NewRegistry()
+			depth = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_depth"}, []string{"name"})
+			workDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "workqueue_work_duration_seconds"}, []string{"name"})
+			longestRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_longest_running_processor_seconds"}, []string{"name"})
+			registry.MustRegister(depth, workDuration, longestRunning)
+			check = health.NewWorkqueueCheck(registry, 10*time.Minute).WithClock(clock)
+		})
+
+		run := func() error {
+			return check.Check(httptest.NewRequest("GET", "/healthz", nil))
+		}
+
+		It("should pass while the workqueue finishes items", func() {
+			depth.WithLabelValues("serviceaccount").Set(5)
+			Expect(run()).To(Succeed())
+
+			workDuration.WithLabelValues("serviceaccount").Observe(0.1)
+			now = now.Add(11 * time.Minute)
+			Expect(run()).To(Succeed())
+
+			now = now.Add(5 * time.Minute)
+			Expect(run()).To(Succeed())
+		})
+
+		It("should pass while the workqueue is empty", func() {
+			depth.WithLabelValues("serviceaccount").Set(0)
+			Expect(run()).To(Succeed())
+
+			now = now.Add(time.Hour)
+			Expect(run()).To(Succeed())
+		})
+
+		It("should fail when the workqueue holds items and finishes none", func() {
+			depth.WithLabelValues("serviceaccount").Set(3)
+			workDuration.WithLabelValues("serviceaccount").Observe(0.1)
+			Expect(run()).To(Succeed())
+
+			now = now.Add(11 * time.Minute)
+			Expect(run()).To(MatchError("workqueue serviceaccount has 3 items and finished none in 11m0s"))
+		})
+
+		It("should fail when a worker is stuck on an item", func() {
+			longestRunning.WithLabelValues("namespace").Set(15 * 60)
+
+			Expect(run()).To(MatchError("a worker of workqueue namespace has been processing an item for 15m0s"))
+		})
+	})
+})
@@ -0,0 +1,58 @@
+// Package health implements the readiness and liveness checks of the operator.
+//
+// The readiness check confirms that the operator can reach AWS: credentials resolve and the
+// Pod Identity Associations of the configured cluster can be listed. Its result is cached so that
+// frequent probes do not turn into EKS calls. The liveness check fails when a controller workqueue
+// stalls, so that the kubelet restarts an operator whose workers are stuck.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CachedCheck runs a check at most once per interval and reports its last result in between
+type CachedCheck struct {
+	check    func(context.Context) error
+	interval time.Duration
+	timeout  time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// NewCachedCheck creates a CachedCheck. Each run of check is bounded by timeout.
+func NewCachedCheck(check func(context.Context) error, interval, timeout time.Duration) *CachedCheck {
+	return &CachedCheck{
+		check:    check,
+		interval: interval,
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// WithClock sets the clock of the check, for tests
+func (c *CachedCheck) WithClock(now func() time.Time) *CachedCheck {
+	c.now = now
+	return c
+}
+
+// Check implements healthz.Checker. Concurrent probes wait for a single run of the check.
+func (c *CachedCheck) Check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && c.now().Sub(c.checked) < c.interval {
+		return c.err
+	}
+	// A probe that gives up must not turn into a cached failure
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), c.timeout)
+	defer cancel()
+	c.err = c.check(ctx)
+	c.checked = c.now()
+	return c.err
+}
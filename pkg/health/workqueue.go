@@ -0,0 +1,124 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Metrics of the controller workqueues, registered by controller-runtime
+const (
+	depthMetric          = "workqueue_depth"
+	workDurationMetric   = "workqueue_work_duration_seconds"
+	longestRunningMetric = "workqueue_longest_running_processor_seconds"
+	workqueueNameLabel   = "name"
+)
+
+// WorkqueueCheck detects stalled controller workqueues from their metrics. A workqueue is stalled
+// when it holds items but has not finished any for the stall timeout, or when one of its items has
+// been processed for longer than the stall timeout.
+type WorkqueueCheck struct {
+	gatherer prometheus.Gatherer
+	timeout  time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	progress map[string]queueProgress
+}
+
+// queueProgress is the number of items a workqueue had finished when it was last seen progressing
+type queueProgress struct {
+	finished uint64
+	since    time.Time
+}
+
+// queueState is the state of a workqueue in a metrics snapshot
+type queueState struct {
+	depth          float64
+	finished       uint64
+	longestRunning float64
+}
+
+// NewWorkqueueCheck creates a WorkqueueCheck reading the metrics gathered by gatherer
+func NewWorkqueueCheck(gatherer prometheus.Gatherer, timeout time.Duration) *WorkqueueCheck {
+	return &WorkqueueCheck{
+		gatherer: gatherer,
+		timeout:  timeout,
+		now:      time.Now,
+		progress: make(map[string]queueProgress),
+	}
+}
+
+// WithClock sets the clock of the check, for tests
+func (w *WorkqueueCheck) WithClock(now func() time.Time) *WorkqueueCheck {
+	w.now = now
+	return w
+}
+
+// Check implements healthz.Checker
+func (w *WorkqueueCheck) Check(_ *http.Request) error {
+	// Gather returns what it collected along with the errors of other collectors, which do not make
+	// the operator unhealthy
+	families, _ := w.gatherer.Gather()
+	queues := workqueueStates(families)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	for name, state := range queues {
+		if state.longestRunning > w.timeout.Seconds() {
+			return fmt.Errorf("a worker of workqueue %s has been processing an item for %s",
+				name, time.Duration(state.longestRunning*float64(time.Second)).Round(time.Second))
+		}
+
+		last, seen := w.progress[name]
+		if !seen || state.depth == 0 || state.finished != last.finished {
+			w.progress[name] = queueProgress{finished: state.finished, since: now}
+			continue
+		}
+		if stalled := now.Sub(last.since); stalled > w.timeout {
+			return fmt.Errorf("workqueue %s has %d items and finished none in %s",
+				name, int(state.depth), stalled.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// workqueueStates extracts the state of every workqueue from the gathered metrics
+func workqueueStates(families []*dto.MetricFamily) map[string]queueState {
+	queues := make(map[string]queueState)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := labelValue(metric, workqueueNameLabel)
+			if name == "" {
+				continue
+			}
+			state := queues[name]
+			switch family.GetName() {
+			case depthMetric:
+				state.depth = metric.GetGauge().GetValue()
+			case workDurationMetric:
+				state.finished = metric.GetHistogram().GetSampleCount()
+			case longestRunningMetric:
+				state.longestRunning = metric.GetGauge().GetValue()
+			default:
+				continue
+			}
+			queues[name] = state
+		}
+	}
+	return queues
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+package iamrole_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIAMRole(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAMRole Suite")
+}
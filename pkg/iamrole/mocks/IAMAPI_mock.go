@@ -0,0 +1,1118 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package iamrole
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIAMAPI creates a new instance of MockIAMAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAMAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAMAPI {
+	mock := &MockIAMAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIAMAPI is an autogenerated mock type for the IAMAPI type
+type MockIAMAPI struct {
+	mock.Mock
+}
+
+type MockIAMAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAMAPI) EXPECT() *MockIAMAPI_Expecter {
+	return &MockIAMAPI_Expecter{mock: &_m.Mock}
+}
+
+// AttachRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) AttachRolePolicy(ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachRolePolicy")
+	}
+
+	var r0 *iam.AttachRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.AttachRolePolicyInput, ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.AttachRolePolicyInput, ...func(*iam.Options)) *iam.AttachRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.AttachRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.AttachRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_AttachRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachRolePolicy'
+type MockIAMAPI_AttachRolePolicy_Call struct {
+	*mock.Call
+}
+
+// AttachRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.AttachRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) AttachRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_AttachRolePolicy_Call {
+	return &MockIAMAPI_AttachRolePolicy_Call{Call: _e.mock.On("AttachRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_AttachRolePolicy_Call) Run(run func(ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_AttachRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.AttachRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.AttachRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_AttachRolePolicy_Call) Return(attachRolePolicyOutput *iam.AttachRolePolicyOutput, err error) *MockIAMAPI_AttachRolePolicy_Call {
+	_c.Call.Return(attachRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_AttachRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error)) *MockIAMAPI_AttachRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRole provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) CreateRole(ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 *iam.CreateRoleOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.CreateRoleInput, ...func(*iam.Options)) (*iam.CreateRoleOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.CreateRoleInput, ...func(*iam.Options)) *iam.CreateRoleOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.CreateRoleOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.CreateRoleInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockIAMAPI_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.CreateRoleInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) CreateRole(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_CreateRole_Call {
+	return &MockIAMAPI_CreateRole_Call{Call: _e.mock.On("CreateRole",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_CreateRole_Call) Run(run func(ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options))) *MockIAMAPI_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.CreateRoleInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.CreateRoleInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_CreateRole_Call) Return(createRoleOutput *iam.CreateRoleOutput, err error) *MockIAMAPI_CreateRole_Call {
+	_c.Call.Return(createRoleOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_CreateRole_Call) RunAndReturn(run func(ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options)) (*iam.CreateRoleOutput, error)) *MockIAMAPI_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRole provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) DeleteRole(ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 *iam.DeleteRoleOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRoleInput, ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRoleInput, ...func(*iam.Options)) *iam.DeleteRoleOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.DeleteRoleOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.DeleteRoleInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
+type MockIAMAPI_DeleteRole_Call struct {
+	*mock.Call
+}
+
+// DeleteRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.DeleteRoleInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) DeleteRole(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_DeleteRole_Call {
+	return &MockIAMAPI_DeleteRole_Call{Call: _e.mock.On("DeleteRole",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_DeleteRole_Call) Run(run func(ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options))) *MockIAMAPI_DeleteRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.DeleteRoleInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.DeleteRoleInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRole_Call) Return(deleteRoleOutput *iam.DeleteRoleOutput, err error) *MockIAMAPI_DeleteRole_Call {
+	_c.Call.Return(deleteRoleOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)) *MockIAMAPI_DeleteRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRolePermissionsBoundary provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) DeleteRolePermissionsBoundary(ctx context.Context, params *iam.DeleteRolePermissionsBoundaryInput, optFns ...func(*iam.Options)) (*iam.DeleteRolePermissionsBoundaryOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRolePermissionsBoundary")
+	}
+
+	var r0 *iam.DeleteRolePermissionsBoundaryOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRolePermissionsBoundaryInput, ...func(*iam.Options)) (*iam.DeleteRolePermissionsBoundaryOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRolePermissionsBoundaryInput, ...func(*iam.Options)) *iam.DeleteRolePermissionsBoundaryOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.DeleteRolePermissionsBoundaryOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.DeleteRolePermissionsBoundaryInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_DeleteRolePermissionsBoundary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRolePermissionsBoundary'
+type MockIAMAPI_DeleteRolePermissionsBoundary_Call struct {
+	*mock.Call
+}
+
+// DeleteRolePermissionsBoundary is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.DeleteRolePermissionsBoundaryInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) DeleteRolePermissionsBoundary(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_DeleteRolePermissionsBoundary_Call {
+	return &MockIAMAPI_DeleteRolePermissionsBoundary_Call{Call: _e.mock.On("DeleteRolePermissionsBoundary",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_DeleteRolePermissionsBoundary_Call) Run(run func(ctx context.Context, params *iam.DeleteRolePermissionsBoundaryInput, optFns ...func(*iam.Options))) *MockIAMAPI_DeleteRolePermissionsBoundary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.DeleteRolePermissionsBoundaryInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.DeleteRolePermissionsBoundaryInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRolePermissionsBoundary_Call) Return(deleteRolePermissionsBoundaryOutput *iam.DeleteRolePermissionsBoundaryOutput, err error) *MockIAMAPI_DeleteRolePermissionsBoundary_Call {
+	_c.Call.Return(deleteRolePermissionsBoundaryOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRolePermissionsBoundary_Call) RunAndReturn(run func(ctx context.Context, params *iam.DeleteRolePermissionsBoundaryInput, optFns ...func(*iam.Options)) (*iam.DeleteRolePermissionsBoundaryOutput, error)) *MockIAMAPI_DeleteRolePermissionsBoundary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) DeleteRolePolicy(ctx context.Context, params *iam.DeleteRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRolePolicy")
+	}
+
+	var r0 *iam.DeleteRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRolePolicyInput, ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DeleteRolePolicyInput, ...func(*iam.Options)) *iam.DeleteRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.DeleteRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.DeleteRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_DeleteRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRolePolicy'
+type MockIAMAPI_DeleteRolePolicy_Call struct {
+	*mock.Call
+}
+
+// DeleteRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.DeleteRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) DeleteRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_DeleteRolePolicy_Call {
+	return &MockIAMAPI_DeleteRolePolicy_Call{Call: _e.mock.On("DeleteRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_DeleteRolePolicy_Call) Run(run func(ctx context.Context, params *iam.DeleteRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_DeleteRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.DeleteRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.DeleteRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRolePolicy_Call) Return(deleteRolePolicyOutput *iam.DeleteRolePolicyOutput, err error) *MockIAMAPI_DeleteRolePolicy_Call {
+	_c.Call.Return(deleteRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_DeleteRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.DeleteRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error)) *MockIAMAPI_DeleteRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DetachRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) DetachRolePolicy(ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachRolePolicy")
+	}
+
+	var r0 *iam.DetachRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DetachRolePolicyInput, ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.DetachRolePolicyInput, ...func(*iam.Options)) *iam.DetachRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.DetachRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.DetachRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_DetachRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DetachRolePolicy'
+type MockIAMAPI_DetachRolePolicy_Call struct {
+	*mock.Call
+}
+
+// DetachRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.DetachRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) DetachRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_DetachRolePolicy_Call {
+	return &MockIAMAPI_DetachRolePolicy_Call{Call: _e.mock.On("DetachRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_DetachRolePolicy_Call) Run(run func(ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_DetachRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.DetachRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.DetachRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_DetachRolePolicy_Call) Return(detachRolePolicyOutput *iam.DetachRolePolicyOutput, err error) *MockIAMAPI_DetachRolePolicy_Call {
+	_c.Call.Return(detachRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_DetachRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)) *MockIAMAPI_DetachRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRole provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRole")
+	}
+
+	var r0 *iam.GetRoleOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) (*iam.GetRoleOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) *iam.GetRoleOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.GetRoleOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_GetRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRole'
+type MockIAMAPI_GetRole_Call struct {
+	*mock.Call
+}
+
+// GetRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.GetRoleInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) GetRole(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_GetRole_Call {
+	return &MockIAMAPI_GetRole_Call{Call: _e.mock.On("GetRole",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_GetRole_Call) Run(run func(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options))) *MockIAMAPI_GetRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.GetRoleInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.GetRoleInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRole_Call) Return(getRoleOutput *iam.GetRoleOutput, err error) *MockIAMAPI_GetRole_Call {
+	_c.Call.Return(getRoleOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRole_Call) RunAndReturn(run func(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)) *MockIAMAPI_GetRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) GetRolePolicy(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRolePolicy")
+	}
+
+	var r0 *iam.GetRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRolePolicyInput, ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRolePolicyInput, ...func(*iam.Options)) *iam.GetRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.GetRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.GetRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_GetRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRolePolicy'
+type MockIAMAPI_GetRolePolicy_Call struct {
+	*mock.Call
+}
+
+// GetRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.GetRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) GetRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_GetRolePolicy_Call {
+	return &MockIAMAPI_GetRolePolicy_Call{Call: _e.mock.On("GetRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_GetRolePolicy_Call) Run(run func(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_GetRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.GetRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.GetRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRolePolicy_Call) Return(getRolePolicyOutput *iam.GetRolePolicyOutput, err error) *MockIAMAPI_GetRolePolicy_Call {
+	_c.Call.Return(getRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error)) *MockIAMAPI_GetRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAttachedRolePolicies provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAttachedRolePolicies")
+	}
+
+	var r0 *iam.ListAttachedRolePoliciesOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.ListAttachedRolePoliciesInput, ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.ListAttachedRolePoliciesInput, ...func(*iam.Options)) *iam.ListAttachedRolePoliciesOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.ListAttachedRolePoliciesOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.ListAttachedRolePoliciesInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_ListAttachedRolePolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAttachedRolePolicies'
+type MockIAMAPI_ListAttachedRolePolicies_Call struct {
+	*mock.Call
+}
+
+// ListAttachedRolePolicies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.ListAttachedRolePoliciesInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) ListAttachedRolePolicies(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_ListAttachedRolePolicies_Call {
+	return &MockIAMAPI_ListAttachedRolePolicies_Call{Call: _e.mock.On("ListAttachedRolePolicies",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_ListAttachedRolePolicies_Call) Run(run func(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options))) *MockIAMAPI_ListAttachedRolePolicies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.ListAttachedRolePoliciesInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.ListAttachedRolePoliciesInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_ListAttachedRolePolicies_Call) Return(listAttachedRolePoliciesOutput *iam.ListAttachedRolePoliciesOutput, err error) *MockIAMAPI_ListAttachedRolePolicies_Call {
+	_c.Call.Return(listAttachedRolePoliciesOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_ListAttachedRolePolicies_Call) RunAndReturn(run func(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)) *MockIAMAPI_ListAttachedRolePolicies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRolePolicies provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRolePolicies")
+	}
+
+	var r0 *iam.ListRolePoliciesOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.ListRolePoliciesInput, ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.ListRolePoliciesInput, ...func(*iam.Options)) *iam.ListRolePoliciesOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.ListRolePoliciesOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.ListRolePoliciesInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_ListRolePolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRolePolicies'
+type MockIAMAPI_ListRolePolicies_Call struct {
+	*mock.Call
+}
+
+// ListRolePolicies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.ListRolePoliciesInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) ListRolePolicies(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_ListRolePolicies_Call {
+	return &MockIAMAPI_ListRolePolicies_Call{Call: _e.mock.On("ListRolePolicies",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_ListRolePolicies_Call) Run(run func(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options))) *MockIAMAPI_ListRolePolicies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.ListRolePoliciesInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.ListRolePoliciesInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_ListRolePolicies_Call) Return(listRolePoliciesOutput *iam.ListRolePoliciesOutput, err error) *MockIAMAPI_ListRolePolicies_Call {
+	_c.Call.Return(listRolePoliciesOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_ListRolePolicies_Call) RunAndReturn(run func(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)) *MockIAMAPI_ListRolePolicies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutRolePermissionsBoundary provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) PutRolePermissionsBoundary(ctx context.Context, params *iam.PutRolePermissionsBoundaryInput, optFns ...func(*iam.Options)) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutRolePermissionsBoundary")
+	}
+
+	var r0 *iam.PutRolePermissionsBoundaryOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.PutRolePermissionsBoundaryInput, ...func(*iam.Options)) (*iam.PutRolePermissionsBoundaryOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.PutRolePermissionsBoundaryInput, ...func(*iam.Options)) *iam.PutRolePermissionsBoundaryOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.PutRolePermissionsBoundaryOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.PutRolePermissionsBoundaryInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_PutRolePermissionsBoundary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutRolePermissionsBoundary'
+type MockIAMAPI_PutRolePermissionsBoundary_Call struct {
+	*mock.Call
+}
+
+// PutRolePermissionsBoundary is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.PutRolePermissionsBoundaryInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) PutRolePermissionsBoundary(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_PutRolePermissionsBoundary_Call {
+	return &MockIAMAPI_PutRolePermissionsBoundary_Call{Call: _e.mock.On("PutRolePermissionsBoundary",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_PutRolePermissionsBoundary_Call) Run(run func(ctx context.Context, params *iam.PutRolePermissionsBoundaryInput, optFns ...func(*iam.Options))) *MockIAMAPI_PutRolePermissionsBoundary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.PutRolePermissionsBoundaryInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.PutRolePermissionsBoundaryInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_PutRolePermissionsBoundary_Call) Return(putRolePermissionsBoundaryOutput *iam.PutRolePermissionsBoundaryOutput, err error) *MockIAMAPI_PutRolePermissionsBoundary_Call {
+	_c.Call.Return(putRolePermissionsBoundaryOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_PutRolePermissionsBoundary_Call) RunAndReturn(run func(ctx context.Context, params *iam.PutRolePermissionsBoundaryInput, optFns ...func(*iam.Options)) (*iam.PutRolePermissionsBoundaryOutput, error)) *MockIAMAPI_PutRolePermissionsBoundary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) PutRolePolicy(ctx context.Context, params *iam.PutRolePolicyInput, optFns ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutRolePolicy")
+	}
+
+	var r0 *iam.PutRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.PutRolePolicyInput, ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.PutRolePolicyInput, ...func(*iam.Options)) *iam.PutRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.PutRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.PutRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_PutRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutRolePolicy'
+type MockIAMAPI_PutRolePolicy_Call struct {
+	*mock.Call
+}
+
+// PutRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.PutRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) PutRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_PutRolePolicy_Call {
+	return &MockIAMAPI_PutRolePolicy_Call{Call: _e.mock.On("PutRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_PutRolePolicy_Call) Run(run func(ctx context.Context, params *iam.PutRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_PutRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.PutRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.PutRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_PutRolePolicy_Call) Return(putRolePolicyOutput *iam.PutRolePolicyOutput, err error) *MockIAMAPI_PutRolePolicy_Call {
+	_c.Call.Return(putRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_PutRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.PutRolePolicyInput, optFns ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error)) *MockIAMAPI_PutRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAssumeRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) UpdateAssumeRolePolicy(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAssumeRolePolicy")
+	}
+
+	var r0 *iam.UpdateAssumeRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) *iam.UpdateAssumeRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.UpdateAssumeRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_UpdateAssumeRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAssumeRolePolicy'
+type MockIAMAPI_UpdateAssumeRolePolicy_Call struct {
+	*mock.Call
+}
+
+// UpdateAssumeRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.UpdateAssumeRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) UpdateAssumeRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	return &MockIAMAPI_UpdateAssumeRolePolicy_Call{Call: _e.mock.On("UpdateAssumeRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) Run(run func(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.UpdateAssumeRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.UpdateAssumeRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) Return(updateAssumeRolePolicyOutput *iam.UpdateAssumeRolePolicyOutput, err error) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Return(updateAssumeRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,162 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package iamrole
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"k8s.io/api/core/v1"
+)
+
+// NewMockProvisioner creates a new instance of MockProvisioner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProvisioner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProvisioner {
+	mock := &MockProvisioner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockProvisioner is an autogenerated mock type for the Provisioner type
+type MockProvisioner struct {
+	mock.Mock
+}
+
+type MockProvisioner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockProvisioner) EXPECT() *MockProvisioner_Expecter {
+	return &MockProvisioner_Expecter{mock: &_m.Mock}
+}
+
+// EnsureRole provides a mock function for the type MockProvisioner
+func (_mock *MockProvisioner) EnsureRole(ctx context.Context, sa *v1.ServiceAccount) (string, error) {
+	ret := _mock.Called(ctx, sa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsureRole")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) (string, error)); ok {
+		return returnFunc(ctx, sa)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) string); ok {
+		r0 = returnFunc(ctx, sa)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount) error); ok {
+		r1 = returnFunc(ctx, sa)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockProvisioner_EnsureRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnsureRole'
+type MockProvisioner_EnsureRole_Call struct {
+	*mock.Call
+}
+
+// EnsureRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+func (_e *MockProvisioner_Expecter) EnsureRole(ctx interface{}, sa interface{}) *MockProvisioner_EnsureRole_Call {
+	return &MockProvisioner_EnsureRole_Call{Call: _e.mock.On("EnsureRole", ctx, sa)}
+}
+
+func (_c *MockProvisioner_EnsureRole_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount)) *MockProvisioner_EnsureRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockProvisioner_EnsureRole_Call) Return(s string, err error) *MockProvisioner_EnsureRole_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockProvisioner_EnsureRole_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount) (string, error)) *MockProvisioner_EnsureRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseRole provides a mock function for the type MockProvisioner
+func (_mock *MockProvisioner) ReleaseRole(ctx context.Context, sa *v1.ServiceAccount) error {
+	ret := _mock.Called(ctx, sa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) error); ok {
+		r0 = returnFunc(ctx, sa)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockProvisioner_ReleaseRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseRole'
+type MockProvisioner_ReleaseRole_Call struct {
+	*mock.Call
+}
+
+// ReleaseRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+func (_e *MockProvisioner_Expecter) ReleaseRole(ctx interface{}, sa interface{}) *MockProvisioner_ReleaseRole_Call {
+	return &MockProvisioner_ReleaseRole_Call{Call: _e.mock.On("ReleaseRole", ctx, sa)}
+}
+
+func (_c *MockProvisioner_ReleaseRole_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount)) *MockProvisioner_ReleaseRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockProvisioner_ReleaseRole_Call) Return(err error) *MockProvisioner_ReleaseRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockProvisioner_ReleaseRole_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount) error) *MockProvisioner_ReleaseRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
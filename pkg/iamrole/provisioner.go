@@ -0,0 +1,422 @@
+package iamrole
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/irenedo/pia-operator/pkg/arn"
+)
+
+const (
+	// DefaultNameTemplate names roles after the cluster and the ServiceAccount
+	DefaultNameTemplate = "{{.ClusterName}}-{{.Namespace}}-{{.ServiceAccount}}"
+	// DefaultPath is the IAM path of provisioned roles
+	DefaultPath = "/"
+
+	// DeletionPolicyDelete deletes the role when the ServiceAccount stops using it
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain keeps the role when the ServiceAccount stops using it
+	DeletionPolicyRetain = "Retain"
+
+	// InlinePolicyName is the name of the inline policy holding the ServiceAccount's policy document
+	InlinePolicyName = "pia-operator"
+
+	// Annotations read from the ServiceAccount
+	policyAnnotation         = "pia-operator.eks.aws.com/managed-role-policy"
+	policyARNsAnnotation     = "pia-operator.eks.aws.com/managed-role-policy-arns"
+	deletionPolicyAnnotation = "pia-operator.eks.aws.com/role-deletion-policy"
+	managedRoleAnnotation    = "pia-operator.eks.aws.com/managed-role"
+
+	maxRoleNameLength = 64
+)
+
+var roleNamePattern = regexp.MustCompile(`^[\w+=,.@-]+$`)
+
+// Options configures the roles created by the Provisioner
+type Options struct {
+	// ClusterName is available to the name template and tagged on every role
+	ClusterName string
+	// NameTemplate is a text/template rendering the role name from ClusterName, Namespace and ServiceAccount
+	NameTemplate string
+	// Path is the IAM path of created roles
+	Path string
+	// PermissionsBoundary is the ARN of the policy set as permissions boundary; empty sets none
+	PermissionsBoundary string
+	// DeletionPolicy is Delete or Retain, and can be overridden per ServiceAccount
+	DeletionPolicy string
+	// Partition selects the Pod Identity service principal trusted by the roles. It defaults to
+	// the partition of the region given to NewProvisioner, or aws.
+	Partition string
+}
+
+// RoleProvisioner implements Provisioner on top of the IAM API
+type RoleProvisioner struct {
+	api          IAMAPI
+	opts         Options
+	nameTemplate *template.Template
+	trustPolicy  string
+	log          logr.Logger
+}
+
+// nameData is passed to the name template
+type nameData struct {
+	ClusterName    string
+	Namespace      string
+	ServiceAccount string
+}
+
+// NewProvisioner creates a Provisioner using the default AWS credentials chain
+func NewProvisioner(ctx context.Context, region string, opts Options, log logr.Logger) (Provisioner, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if opts.Partition == "" {
+		opts.Partition = arn.PartitionForRegion(region)
+	}
+	return NewProvisionerWithAPI(iam.NewFromConfig(cfg), opts, log)
+}
+
+// NewProvisionerWithAPI creates a Provisioner using the given IAM API
+func NewProvisionerWithAPI(api IAMAPI, opts Options, log logr.Logger) (*RoleProvisioner, error) {
+	if opts.NameTemplate == "" {
+		opts.NameTemplate = DefaultNameTemplate
+	}
+	if opts.Path == "" {
+		opts.Path = DefaultPath
+	}
+	if opts.DeletionPolicy == "" {
+		opts.DeletionPolicy = DeletionPolicyDelete
+	}
+	if opts.Partition == "" {
+		opts.Partition = arn.PartitionAWS
+	}
+	tmpl, err := template.New("roleName").Option("missingkey=error").Parse(opts.NameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role name template: %w", err)
+	}
+	return &RoleProvisioner{api: api, opts: opts, nameTemplate: tmpl, trustPolicy: PodIdentityTrustPolicy(opts.Partition), log: log}, nil
+}
+
+// PodIdentityTrustPolicy returns the trust policy letting the EKS Pod Identity service of the
+// partition assume the role and tag the session
+func PodIdentityTrustPolicy(partition string) string {
+	return fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":%q},"Action":["sts:AssumeRole","sts:TagSession"]}]}`,
+		arn.ServicePrincipal(partition, "pods.eks"))
+}
+
+// EnsureRole creates the role of the ServiceAccount, or brings an existing one in line with the
+// trust policy, permissions boundary and policies it should have. It returns the role ARN.
+func (p *RoleProvisioner) EnsureRole(ctx context.Context, sa *corev1.ServiceAccount) (string, error) {
+	name, err := p.roleName(sa)
+	if err != nil {
+		return "", err
+	}
+	log := p.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "role", name)
+
+	policy := sa.Annotations[policyAnnotation]
+	if policy != "" && !json.Valid([]byte(policy)) {
+		return "", fmt.Errorf("annotation %s of ServiceAccount %s/%s is not a valid JSON policy document", policyAnnotation, sa.Namespace, sa.Name)
+	}
+
+	role, err := p.getRole(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if role == nil {
+		role, err = p.createRole(ctx, sa, name)
+		if err != nil {
+			return "", err
+		}
+		log.Info("Created IAM role", "roleArn", aws.ToString(role.Arn))
+	} else {
+		if !p.owns(role, sa) {
+			return "", fmt.Errorf("IAM role %s already exists and is not managed by pia-operator for ServiceAccount %s/%s", name, sa.Namespace, sa.Name)
+		}
+		if err := p.syncRole(ctx, role); err != nil {
+			return "", err
+		}
+	}
+
+	if err := p.syncInlinePolicy(ctx, name, policy); err != nil {
+		return "", err
+	}
+	if err := p.syncManagedPolicies(ctx, name, splitList(sa.Annotations[policyARNsAnnotation])); err != nil {
+		return "", err
+	}
+	return aws.ToString(role.Arn), nil
+}
+
+// ReleaseRole deletes the role of the ServiceAccount unless the deletion policy retains it.
+// Roles that do not exist or are not tagged for the ServiceAccount are left untouched.
+func (p *RoleProvisioner) ReleaseRole(ctx context.Context, sa *corev1.ServiceAccount) error {
+	name, err := p.roleName(sa)
+	if err != nil {
+		return err
+	}
+	log := p.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "role", name)
+
+	if p.deletionPolicy(sa) == DeletionPolicyRetain {
+		log.Info("Retaining IAM role according to its deletion policy")
+		return nil
+	}
+
+	role, err := p.getRole(ctx, name)
+	if err != nil || role == nil {
+		return err
+	}
+	if !p.owns(role, sa) {
+		log.Info("IAM role is not managed by pia-operator for this ServiceAccount, not deleting it")
+		return nil
+	}
+
+	if err := p.syncManagedPolicies(ctx, name, nil); err != nil {
+		return err
+	}
+	inline, err := p.api.ListRolePolicies(ctx, &iam.ListRolePoliciesInput{RoleName: aws.String(name)})
+	if err != nil {
+		return fmt.Errorf("failed to list inline policies of IAM role %s: %w", name, err)
+	}
+	for _, policyName := range inline.PolicyNames {
+		if _, err := p.api.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: aws.String(name), PolicyName: aws.String(policyName)}); err != nil && !isNoSuchEntity(err) {
+			return fmt.Errorf("failed to delete inline policy %s of IAM role %s: %w", policyName, name, err)
+		}
+	}
+	if _, err := p.api.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(name)}); err != nil && !isNoSuchEntity(err) {
+		return fmt.Errorf("failed to delete IAM role %s: %w", name, err)
+	}
+
+	log.Info("Deleted IAM role")
+	return nil
+}
+
+// roleName returns the name of the ServiceAccount's role. The name recorded on the ServiceAccount
+// is used once the role exists, so that changing the template does not orphan existing roles.
+func (p *RoleProvisioner) roleName(sa *corev1.ServiceAccount) (string, error) {
+	if roleArn := sa.Annotations[managedRoleAnnotation]; roleArn != "" {
+		return roleArn[strings.LastIndex(roleArn, "/")+1:], nil
+	}
+
+	var buf bytes.Buffer
+	if err := p.nameTemplate.Execute(&buf, nameData{ClusterName: p.opts.ClusterName, Namespace: sa.Namespace, ServiceAccount: sa.Name}); err != nil {
+		return "", fmt.Errorf("failed to render role name: %w", err)
+	}
+	name := buf.String()
+	if !roleNamePattern.MatchString(name) {
+		return "", fmt.Errorf("rendered role name %q contains characters not allowed by IAM", name)
+	}
+	if len(name) > maxRoleNameLength {
+		// Keep names unique when truncating by appending a hash of the full name
+		sum := sha256.Sum256([]byte(name))
+		name = name[:maxRoleNameLength-9] + "-" + hex.EncodeToString(sum[:])[:8]
+	}
+	return name, nil
+}
+
+func (p *RoleProvisioner) getRole(ctx context.Context, name string) (*types.Role, error) {
+	out, err := p.api.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get IAM role %s: %w", name, err)
+	}
+	return out.Role, nil
+}
+
+func (p *RoleProvisioner) createRole(ctx context.Context, sa *corev1.ServiceAccount, name string) (*types.Role, error) {
+	input := &iam.CreateRoleInput{
+		RoleName:                 aws.String(name),
+		Path:                     aws.String(p.opts.Path),
+		AssumeRolePolicyDocument: aws.String(p.trustPolicy),
+		Description:              aws.String(fmt.Sprintf("Pod Identity role of ServiceAccount %s/%s", sa.Namespace, sa.Name)),
+		Tags: []types.Tag{
+			{Key: aws.String("managed-by"), Value: aws.String("pia-operator")},
+			{Key: aws.String("cluster"), Value: aws.String(p.opts.ClusterName)},
+			{Key: aws.String("namespace"), Value: aws.String(sa.Namespace)},
+			{Key: aws.String("serviceaccount"), Value: aws.String(sa.Name)},
+		},
+	}
+	if p.opts.PermissionsBoundary != "" {
+		input.PermissionsBoundary = aws.String(p.opts.PermissionsBoundary)
+	}
+
+	out, err := p.api.CreateRole(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM role %s: %w", name, err)
+	}
+	return out.Role, nil
+}
+
+// syncRole restores the trust policy and permissions boundary of an existing role
+func (p *RoleProvisioner) syncRole(ctx context.Context, role *types.Role) error {
+	name := aws.ToString(role.RoleName)
+
+	if !samePolicy(aws.ToString(role.AssumeRolePolicyDocument), p.trustPolicy) {
+		if _, err := p.api.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(name),
+			PolicyDocument: aws.String(p.trustPolicy),
+		}); err != nil {
+			return fmt.Errorf("failed to update trust policy of IAM role %s: %w", name, err)
+		}
+	}
+
+	var boundary string
+	if role.PermissionsBoundary != nil {
+		boundary = aws.ToString(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	switch {
+	case boundary == p.opts.PermissionsBoundary:
+	case p.opts.PermissionsBoundary == "":
+		if _, err := p.api.DeleteRolePermissionsBoundary(ctx, &iam.DeleteRolePermissionsBoundaryInput{RoleName: aws.String(name)}); err != nil {
+			return fmt.Errorf("failed to remove permissions boundary of IAM role %s: %w", name, err)
+		}
+	default:
+		if _, err := p.api.PutRolePermissionsBoundary(ctx, &iam.PutRolePermissionsBoundaryInput{
+			RoleName:            aws.String(name),
+			PermissionsBoundary: aws.String(p.opts.PermissionsBoundary),
+		}); err != nil {
+			return fmt.Errorf("failed to set permissions boundary of IAM role %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// syncInlinePolicy puts the policy document as the role's inline policy, or removes it when empty.
+// The current inline policy is read first so that IAM is only written when it differs.
+func (p *RoleProvisioner) syncInlinePolicy(ctx context.Context, name, policy string) error {
+	var current string
+	out, err := p.api.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+		RoleName:   aws.String(name),
+		PolicyName: aws.String(InlinePolicyName),
+	})
+	switch {
+	case err == nil:
+		current = aws.ToString(out.PolicyDocument)
+	case !isNoSuchEntity(err):
+		return fmt.Errorf("failed to get inline policy of IAM role %s: %w", name, err)
+	}
+
+	if policy == "" {
+		if current == "" {
+			return nil
+		}
+		if _, err := p.api.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(name),
+			PolicyName: aws.String(InlinePolicyName),
+		}); err != nil && !isNoSuchEntity(err) {
+			return fmt.Errorf("failed to delete inline policy of IAM role %s: %w", name, err)
+		}
+		return nil
+	}
+	if samePolicy(current, policy) {
+		return nil
+	}
+
+	if _, err := p.api.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+		RoleName:       aws.String(name),
+		PolicyName:     aws.String(InlinePolicyName),
+		PolicyDocument: aws.String(policy),
+	}); err != nil {
+		return fmt.Errorf("failed to put inline policy of IAM role %s: %w", name, err)
+	}
+	return nil
+}
+
+// syncManagedPolicies attaches the wanted managed policies and detaches all others
+func (p *RoleProvisioner) syncManagedPolicies(ctx context.Context, name string, wanted []string) error {
+	attached := make(map[string]bool)
+	paginator := iam.NewListAttachedRolePoliciesPaginator(p.api, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(name)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list attached policies of IAM role %s: %w", name, err)
+		}
+		for _, policy := range page.AttachedPolicies {
+			attached[aws.ToString(policy.PolicyArn)] = true
+		}
+	}
+
+	for _, policyArn := range wanted {
+		if attached[policyArn] {
+			delete(attached, policyArn)
+			continue
+		}
+		if _, err := p.api.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{RoleName: aws.String(name), PolicyArn: aws.String(policyArn)}); err != nil {
+			return fmt.Errorf("failed to attach policy %s to IAM role %s: %w", policyArn, name, err)
+		}
+	}
+	for policyArn := range attached {
+		if _, err := p.api.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: aws.String(name), PolicyArn: aws.String(policyArn)}); err != nil && !isNoSuchEntity(err) {
+			return fmt.Errorf("failed to detach policy %s from IAM role %s: %w", policyArn, name, err)
+		}
+	}
+	return nil
+}
+
+// owns reports whether the role was created by the operator for the ServiceAccount
+func (p *RoleProvisioner) owns(role *types.Role, sa *corev1.ServiceAccount) bool {
+	tags := make(map[string]string, len(role.Tags))
+	for _, tag := range role.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags["managed-by"] == "pia-operator" &&
+		tags["cluster"] == p.opts.ClusterName &&
+		tags["namespace"] == sa.Namespace &&
+		tags["serviceaccount"] == sa.Name
+}
+
+// deletionPolicy returns the deletion policy of the ServiceAccount's role
+func (p *RoleProvisioner) deletionPolicy(sa *corev1.ServiceAccount) string {
+	switch policy := sa.Annotations[deletionPolicyAnnotation]; policy {
+	case DeletionPolicyDelete, DeletionPolicyRetain:
+		return policy
+	default:
+		return p.opts.DeletionPolicy
+	}
+}
+
+// samePolicy compares two policy documents ignoring formatting. IAM returns documents URL encoded.
+func samePolicy(current, wanted string) bool {
+	if decoded, err := url.QueryUnescape(current); err == nil {
+		current = decoded
+	}
+	var a, b interface{}
+	if json.Unmarshal([]byte(current), &a) != nil || json.Unmarshal([]byte(wanted), &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isNoSuchEntity(err error) bool {
+	var noSuchEntity *types.NoSuchEntityException
+	return errors.As(err, &noSuchEntity)
+}
+
+// splitList splits a comma separated annotation value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,229 @@
+package iamrole_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/iamrole"
+	iammocks "github.com/irenedo/pia-operator/pkg/iamrole/mocks"
+)
+
+var _ = Describe("RoleProvisioner", func() {
+	const (
+		roleName   = "my-cluster-default-app"
+		roleArn    = "arn:aws:iam::123456789012:role/my-cluster-default-app"
+		boundary   = "arn:aws:iam::123456789012:policy/boundary"
+		readOnly   = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+		sqsPolicy  = "arn:aws:iam::aws:policy/AmazonSQSFullAccess"
+		inlineJSON = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	)
+
+	var (
+		ctx         context.Context
+		mockIAM     *iammocks.MockIAMAPI
+		provisioner *iamrole.RoleProvisioner
+		sa          *corev1.ServiceAccount
+	)
+
+	noSuchEntity := &types.NoSuchEntityException{Message: aws.String("not found")}
+	trustPolicy := iamrole.PodIdentityTrustPolicy("aws")
+
+	// urlEncoded encodes a policy document the way IAM returns it
+	urlEncoded := func(document string) *string {
+		return aws.String(strings.ReplaceAll(document, `"`, "%22"))
+	}
+
+	ownedRole := func(clusterName string) *types.Role {
+		return &types.Role{
+			RoleName:                 aws.String(roleName),
+			Arn:                      aws.String(roleArn),
+			AssumeRolePolicyDocument: urlEncoded(trustPolicy),
+			PermissionsBoundary:      &types.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String(boundary)},
+			Tags: []types.Tag{
+				{Key: aws.String("managed-by"), Value: aws.String("pia-operator")},
+				{Key: aws.String("cluster"), Value: aws.String(clusterName)},
+				{Key: aws.String("namespace"), Value: aws.String("default")},
+				{Key: aws.String("serviceaccount"), Value: aws.String("app")},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockIAM = iammocks.NewMockIAMAPI(GinkgoT())
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"pia-operator.eks.aws.com/managed-role-policy":      inlineJSON,
+				"pia-operator.eks.aws.com/managed-role-policy-arns": readOnly,
+			},
+		}}
+
+		var err error
+		provisioner, err = iamrole.NewProvisionerWithAPI(mockIAM, iamrole.Options{
+			ClusterName:         "my-cluster",
+			PermissionsBoundary: boundary,
+		}, log.Log)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should reject invalid name templates", func() {
+		_, err := iamrole.NewProvisionerWithAPI(mockIAM, iamrole.Options{NameTemplate: "{{.Namespace"}, log.Log)
+		Expect(err).To(MatchError(ContainSubstring("invalid role name template")))
+	})
+
+	Describe("EnsureRole", func() {
+		It("should create the role with the Pod Identity trust policy and its policies", func() {
+			mockIAM.On("GetRole", ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(nil, noSuchEntity)
+			mockIAM.On("CreateRole", ctx, mock.MatchedBy(func(in *iam.CreateRoleInput) bool {
+				return aws.ToString(in.RoleName) == roleName &&
+					aws.ToString(in.Path) == "/" &&
+					aws.ToString(in.AssumeRolePolicyDocument) == trustPolicy &&
+					aws.ToString(in.PermissionsBoundary) == boundary &&
+					len(in.Tags) == 4
+			})).Return(&iam.CreateRoleOutput{Role: &types.Role{RoleName: aws.String(roleName), Arn: aws.String(roleArn)}}, nil)
+			mockIAM.On("GetRolePolicy", ctx, mock.Anything).Return(nil, noSuchEntity)
+			mockIAM.On("PutRolePolicy", ctx, &iam.PutRolePolicyInput{
+				RoleName:       aws.String(roleName),
+				PolicyName:     aws.String(iamrole.InlinePolicyName),
+				PolicyDocument: aws.String(inlineJSON),
+			}).Return(&iam.PutRolePolicyOutput{}, nil)
+			mockIAM.On("ListAttachedRolePolicies", ctx, mock.Anything, mock.Anything).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+			mockIAM.On("AttachRolePolicy", ctx, &iam.AttachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(readOnly)}).
+				Return(&iam.AttachRolePolicyOutput{}, nil)
+
+			arn, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arn).To(Equal(roleArn))
+		})
+
+		It("should converge an existing role to the requested policies", func() {
+			delete(sa.Annotations, "pia-operator.eks.aws.com/managed-role-policy")
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(&iam.GetRoleOutput{Role: ownedRole("my-cluster")}, nil)
+			mockIAM.On("GetRolePolicy", ctx, mock.Anything).Return(&iam.GetRolePolicyOutput{PolicyDocument: urlEncoded(inlineJSON)}, nil)
+			mockIAM.On("DeleteRolePolicy", ctx, &iam.DeleteRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(iamrole.InlinePolicyName)}).
+				Return(&iam.DeleteRolePolicyOutput{}, nil)
+			mockIAM.On("ListAttachedRolePolicies", ctx, mock.Anything, mock.Anything).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String(readOnly)}, {PolicyArn: aws.String(sqsPolicy)}},
+			}, nil)
+			mockIAM.On("DetachRolePolicy", ctx, &iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(sqsPolicy)}).
+				Return(&iam.DetachRolePolicyOutput{}, nil)
+
+			arn, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arn).To(Equal(roleArn))
+			mockIAM.AssertNotCalled(GinkgoT(), "UpdateAssumeRolePolicy", mock.Anything, mock.Anything)
+			mockIAM.AssertNotCalled(GinkgoT(), "AttachRolePolicy", mock.Anything, mock.Anything)
+		})
+
+		It("should not write to IAM when the role is up to date", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(&iam.GetRoleOutput{Role: ownedRole("my-cluster")}, nil)
+			mockIAM.On("GetRolePolicy", ctx, &iam.GetRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(iamrole.InlinePolicyName)}).
+				Return(&iam.GetRolePolicyOutput{PolicyDocument: urlEncoded("{\n  \"Statement\": [{\"Resource\": \"*\", \"Action\": \"s3:GetObject\", \"Effect\": \"Allow\"}],\n  \"Version\": \"2012-10-17\"\n}")}, nil)
+			mockIAM.On("ListAttachedRolePolicies", ctx, mock.Anything, mock.Anything).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String(readOnly)}},
+			}, nil)
+
+			arn, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arn).To(Equal(roleArn))
+			mockIAM.AssertNotCalled(GinkgoT(), "UpdateAssumeRolePolicy", mock.Anything, mock.Anything)
+			mockIAM.AssertNotCalled(GinkgoT(), "PutRolePermissionsBoundary", mock.Anything, mock.Anything)
+			mockIAM.AssertNotCalled(GinkgoT(), "PutRolePolicy", mock.Anything, mock.Anything)
+			mockIAM.AssertNotCalled(GinkgoT(), "DeleteRolePolicy", mock.Anything, mock.Anything)
+			mockIAM.AssertNotCalled(GinkgoT(), "AttachRolePolicy", mock.Anything, mock.Anything)
+		})
+
+		It("should trust the Pod Identity service of the partition", func() {
+			var err error
+			provisioner, err = iamrole.NewProvisionerWithAPI(mockIAM, iamrole.Options{ClusterName: "my-cluster", Partition: "aws-cn"}, log.Log)
+			Expect(err).ToNot(HaveOccurred())
+			delete(sa.Annotations, "pia-operator.eks.aws.com/managed-role-policy")
+			delete(sa.Annotations, "pia-operator.eks.aws.com/managed-role-policy-arns")
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(nil, noSuchEntity)
+			mockIAM.On("CreateRole", ctx, mock.MatchedBy(func(in *iam.CreateRoleInput) bool {
+				return strings.Contains(aws.ToString(in.AssumeRolePolicyDocument), `"Service":"pods.eks.amazonaws.com.cn"`)
+			})).Return(&iam.CreateRoleOutput{Role: &types.Role{RoleName: aws.String(roleName), Arn: aws.String(roleArn)}}, nil)
+			mockIAM.On("GetRolePolicy", ctx, mock.Anything).Return(nil, noSuchEntity)
+			mockIAM.On("ListAttachedRolePolicies", ctx, mock.Anything, mock.Anything).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+
+			_, err = provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should refuse to take over a role it does not manage", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(&iam.GetRoleOutput{Role: ownedRole("other-cluster")}, nil)
+
+			_, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).To(MatchError(ContainSubstring("is not managed by pia-operator")))
+		})
+
+		It("should reject policy documents that are not JSON", func() {
+			sa.Annotations["pia-operator.eks.aws.com/managed-role-policy"] = "s3:GetObject"
+
+			_, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).To(MatchError(ContainSubstring("not a valid JSON policy document")))
+		})
+
+		It("should shorten role names longer than IAM allows", func() {
+			sa.Name = strings.Repeat("a", 70)
+			mockIAM.On("GetRole", ctx, mock.MatchedBy(func(in *iam.GetRoleInput) bool {
+				return len(aws.ToString(in.RoleName)) == 64
+			})).Return(nil, noSuchEntity)
+			mockIAM.On("CreateRole", ctx, mock.Anything).Return(nil, noSuchEntity)
+
+			_, err := provisioner.EnsureRole(ctx, sa)
+
+			Expect(err).To(MatchError(ContainSubstring("failed to create IAM role")))
+		})
+	})
+
+	Describe("ReleaseRole", func() {
+		BeforeEach(func() {
+			sa.Annotations["pia-operator.eks.aws.com/managed-role"] = roleArn
+		})
+
+		It("should delete the role and its policies", func() {
+			mockIAM.On("GetRole", ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole("my-cluster")}, nil)
+			mockIAM.On("ListAttachedRolePolicies", ctx, mock.Anything, mock.Anything).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String(readOnly)}},
+			}, nil)
+			mockIAM.On("DetachRolePolicy", ctx, mock.Anything).Return(&iam.DetachRolePolicyOutput{}, nil)
+			mockIAM.On("ListRolePolicies", ctx, mock.Anything).Return(&iam.ListRolePoliciesOutput{PolicyNames: []string{iamrole.InlinePolicyName}}, nil)
+			mockIAM.On("DeleteRolePolicy", ctx, mock.Anything).Return(&iam.DeleteRolePolicyOutput{}, nil)
+			mockIAM.On("DeleteRole", ctx, &iam.DeleteRoleInput{RoleName: aws.String(roleName)}).Return(&iam.DeleteRoleOutput{}, nil)
+
+			Expect(provisioner.ReleaseRole(ctx, sa)).To(Succeed())
+		})
+
+		It("should retain the role when the deletion policy says so", func() {
+			sa.Annotations["pia-operator.eks.aws.com/role-deletion-policy"] = iamrole.DeletionPolicyRetain
+
+			Expect(provisioner.ReleaseRole(ctx, sa)).To(Succeed())
+			mockIAM.AssertNotCalled(GinkgoT(), "DeleteRole", mock.Anything, mock.Anything)
+		})
+
+		It("should ignore roles that no longer exist", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(nil, noSuchEntity)
+
+			Expect(provisioner.ReleaseRole(ctx, sa)).To(Succeed())
+		})
+	})
+})
@@ -2,11 +2,21 @@ package k8sclient
 
 import (
 	context "context"
+	"encoding/json"
+	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// FieldManager is the field manager of the operator's writes to ServiceAccounts
+const FieldManager = "pia-operator"
+
 type DefaultServiceAccountClient struct {
 	Client client.Client
 }
@@ -24,6 +34,102 @@ func (c *DefaultServiceAccountClient) GetServiceAccount(ctx context.Context, nam
 	return sa, err
 }
 
+// PatchServiceAccount only sends the fields changed by mutate, so concurrent writers of other
+// fields are not overwritten. Merge patches replace lists as a whole, so when the finalizers
+// change the patch is guarded by the resourceVersion of sa and fails with a conflict if the
+// ServiceAccount was changed since it was read.
+func (c *DefaultServiceAccountClient) PatchServiceAccount(ctx context.Context, sa *corev1.ServiceAccount, mutate func(*corev1.ServiceAccount)) error {
+	original := sa.DeepCopy()
+	mutate(sa)
+
+	var opts []client.MergeFromOption
+	if !reflect.DeepEqual(original.Finalizers, sa.Finalizers) {
+		opts = append(opts, client.MergeFromWithOptimisticLock{})
+	}
+	return c.Client.Patch(ctx, sa, client.MergeFromWithOptions(original, opts...), client.FieldOwner(FieldManager))
+}
+
+// ApplyServiceAccountAnnotations applies annotations as the complete set of annotations owned by
+// the FieldManager: annotations it applied before and that are missing from annotations are removed,
+// and annotations of other field managers are left alone. sa is updated with the applied ServiceAccount.
+// The ServiceAccount must exist, as server-side apply would create it otherwise.
+//
+// Annotations the FieldManager wrote with updates, before the operator used server-side apply, are not
+// owned by its apply and would never be removed by it, so those missing from annotations are deleted
+// with a merge patch first.
+func (c *DefaultServiceAccountClient) ApplyServiceAccountAnnotations(ctx context.Context, sa *corev1.ServiceAccount, annotations map[string]string) error {
+	if err := c.removeUpdatedAnnotations(ctx, sa, annotations); err != nil {
+		return err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ServiceAccount")
+	obj.SetNamespace(sa.Namespace)
+	obj.SetName(sa.Name)
+	obj.SetAnnotations(annotations)
+
+	if err := c.Client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	applied := &corev1.ServiceAccount{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, applied); err != nil {
+		return err
+	}
+	*sa = *applied
+	return nil
+}
+
+// removeUpdatedAnnotations deletes the annotations of sa written by the FieldManager with updates that
+// are not in keep
+func (c *DefaultServiceAccountClient) removeUpdatedAnnotations(ctx context.Context, sa *corev1.ServiceAccount, keep map[string]string) error {
+	removed := make(map[string]interface{})
+	for _, key := range updatedAnnotations(sa.ManagedFields) {
+		if _, ok := keep[key]; !ok {
+			removed[key] = nil
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": removed},
+	})
+	if err != nil {
+		return err
+	}
+	target := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: sa.Namespace, Name: sa.Name}}
+	return c.Client.Patch(ctx, target, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(FieldManager))
+}
+
+// updatedAnnotations returns the annotation keys owned by the update entries of the FieldManager
+func updatedAnnotations(managedFields []metav1.ManagedFieldsEntry) []string {
+	var keys []string
+	for _, entry := range managedFields {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationUpdate ||
+			entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]json.RawMessage `json:"f:annotations"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		for field := range fields.Metadata.Annotations {
+			if key, ok := strings.CutPrefix(field, "f:"); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 // NewClient returns a Cli implementation
 func NewClient(c client.Client) Cli {
 	return &DefaultServiceAccountClient{Client: c}
@@ -0,0 +1,175 @@
+package k8sclient_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/irenedo/pia-operator/pkg/k8sclient"
+)
+
+var _ = Describe("DefaultServiceAccountClient", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		cli        k8sclient.Cli
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClient = fake.NewClientBuilder().WithObjects(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "default",
+				Annotations: map[string]string{"owner": "helm"},
+				Finalizers:  []string{"other.io/finalizer"},
+			},
+		}).Build()
+		cli = k8sclient.NewClient(fakeClient)
+	})
+
+	// writeConcurrently changes the ServiceAccount behind the back of a reader
+	writeConcurrently := func(mutate func(*corev1.ServiceAccount)) {
+		sa := &corev1.ServiceAccount{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app"}, sa)).To(Succeed())
+		mutate(sa)
+		Expect(fakeClient.Update(ctx, sa)).To(Succeed())
+	}
+
+	Describe("PatchServiceAccount", func() {
+		It("should keep annotations written concurrently by others", func() {
+			sa, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			writeConcurrently(func(sa *corev1.ServiceAccount) {
+				sa.Annotations["argocd.argoproj.io/tracking-id"] = "app"
+			})
+
+			Expect(cli.PatchServiceAccount(ctx, sa, func(sa *corev1.ServiceAccount) {
+				sa.Annotations["pia-operator.eks.aws.com/ready"] = "true"
+				delete(sa.Annotations, "owner")
+			})).To(Succeed())
+
+			current, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(current.Annotations).To(Equal(map[string]string{
+				"argocd.argoproj.io/tracking-id": "app",
+				"pia-operator.eks.aws.com/ready": "true",
+			}))
+		})
+
+		It("should refuse to replace finalizers changed concurrently", func() {
+			sa, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			writeConcurrently(func(sa *corev1.ServiceAccount) {
+				sa.Finalizers = append(sa.Finalizers, "another.io/finalizer")
+			})
+
+			err = cli.PatchServiceAccount(ctx, sa, func(sa *corev1.ServiceAccount) {
+				sa.Finalizers = append(sa.Finalizers, "pia-operator.eks.aws.com/finalizer")
+			})
+
+			Expect(k8serrors.IsConflict(err)).To(BeTrue())
+			current, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(current.Finalizers).To(ConsistOf("other.io/finalizer", "another.io/finalizer"))
+		})
+
+		It("should update the ServiceAccount with the patched object", func() {
+			sa, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			resourceVersion := sa.ResourceVersion
+
+			Expect(cli.PatchServiceAccount(ctx, sa, func(sa *corev1.ServiceAccount) {
+				sa.Finalizers = append(sa.Finalizers, "pia-operator.eks.aws.com/finalizer")
+			})).To(Succeed())
+
+			Expect(sa.ResourceVersion).ToNot(Equal(resourceVersion))
+			Expect(sa.Finalizers).To(ConsistOf("other.io/finalizer", "pia-operator.eks.aws.com/finalizer"))
+		})
+	})
+
+	Describe("ApplyServiceAccountAnnotations", func() {
+		var applied []map[string]string
+
+		BeforeEach(func() {
+			applied = nil
+			// The fake client does not support server-side apply, so applies only record the
+			// annotations and read back the ServiceAccount
+			fakeClient = fake.NewClientBuilder().WithObjects(&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"owner": "helm",
+						"pia-operator.eks.aws.com/association-id": "a-123",
+						"pia-operator.eks.aws.com/ready":          "true",
+					},
+					Finalizers: []string{"pia-operator.eks.aws.com/finalizer"},
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{
+							Manager:    "helm",
+							Operation:  metav1.ManagedFieldsOperationUpdate,
+							FieldsType: "FieldsV1",
+							FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{".":{},"f:owner":{}}}}`)},
+						},
+						{
+							Manager:    k8sclient.FieldManager,
+							Operation:  metav1.ManagedFieldsOperationUpdate,
+							FieldsType: "FieldsV1",
+							FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{` +
+								`"f:pia-operator.eks.aws.com/association-id":{},"f:pia-operator.eks.aws.com/ready":{}},` +
+								`"f:finalizers":{".":{},"v:\"pia-operator.eks.aws.com/finalizer\"":{}}}}`)},
+						},
+					},
+				},
+			}).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if patch.Type() != types.ApplyPatchType {
+						return c.Patch(ctx, obj, patch, opts...)
+					}
+					applied = append(applied, obj.GetAnnotations())
+					return c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+				},
+			}).Build()
+			cli = k8sclient.NewClient(fakeClient)
+		})
+
+		It("should remove annotations written with updates before server-side apply", func() {
+			sa, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cli.ApplyServiceAccountAnnotations(ctx, sa, map[string]string{
+				"pia-operator.eks.aws.com/ready": "true",
+			})).To(Succeed())
+
+			Expect(applied).To(ConsistOf(map[string]string{"pia-operator.eks.aws.com/ready": "true"}))
+			Expect(sa.Annotations).To(Equal(map[string]string{
+				"owner":                          "helm",
+				"pia-operator.eks.aws.com/ready": "true",
+			}))
+			Expect(sa.Finalizers).To(ConsistOf("pia-operator.eks.aws.com/finalizer"))
+		})
+
+		It("should not patch when the updated annotations are still applied", func() {
+			sa, err := cli.GetServiceAccount(ctx, "default", "app")
+			Expect(err).ToNot(HaveOccurred())
+			resourceVersion := sa.ResourceVersion
+
+			Expect(cli.ApplyServiceAccountAnnotations(ctx, sa, map[string]string{
+				"pia-operator.eks.aws.com/association-id": "a-123",
+				"pia-operator.eks.aws.com/ready":          "true",
+			})).To(Succeed())
+
+			Expect(sa.ResourceVersion).To(Equal(resourceVersion))
+			Expect(sa.Annotations).To(HaveKeyWithValue("pia-operator.eks.aws.com/association-id", "a-123"))
+		})
+	})
+})
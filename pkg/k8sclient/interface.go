@@ -18,4 +18,8 @@ import (
 type Cli interface {
 	UpdateServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) error
 	GetServiceAccount(ctx context.Context, name, namespace string) (*corev1.ServiceAccount, error)
+	// PatchServiceAccount applies mutate to sa and sends the changes as a JSON merge patch
+	PatchServiceAccount(ctx context.Context, sa *corev1.ServiceAccount, mutate func(*corev1.ServiceAccount)) error
+	// ApplyServiceAccountAnnotations sets the annotations owned by the operator with server-side apply
+	ApplyServiceAccountAnnotations(ctx context.Context, sa *corev1.ServiceAccount, annotations map[string]string) error
 }
@@ -0,0 +1,13 @@
+package k8sclient_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestK8sClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "K8sClient Suite")
+}
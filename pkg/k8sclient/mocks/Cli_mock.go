@@ -38,6 +38,69 @@ func (_m *MockCli) EXPECT() *MockCli_Expecter {
 	return &MockCli_Expecter{mock: &_m.Mock}
 }
 
+// ApplyServiceAccountAnnotations provides a mock function for the type MockCli
+func (_mock *MockCli) ApplyServiceAccountAnnotations(ctx context.Context, sa *v1.ServiceAccount, annotations map[string]string) error {
+	ret := _mock.Called(ctx, sa, annotations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyServiceAccountAnnotations")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, map[string]string) error); ok {
+		r0 = returnFunc(ctx, sa, annotations)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCli_ApplyServiceAccountAnnotations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyServiceAccountAnnotations'
+type MockCli_ApplyServiceAccountAnnotations_Call struct {
+	*mock.Call
+}
+
+// ApplyServiceAccountAnnotations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+//   - annotations map[string]string
+func (_e *MockCli_Expecter) ApplyServiceAccountAnnotations(ctx interface{}, sa interface{}, annotations interface{}) *MockCli_ApplyServiceAccountAnnotations_Call {
+	return &MockCli_ApplyServiceAccountAnnotations_Call{Call: _e.mock.On("ApplyServiceAccountAnnotations", ctx, sa, annotations)}
+}
+
+func (_c *MockCli_ApplyServiceAccountAnnotations_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, annotations map[string]string)) *MockCli_ApplyServiceAccountAnnotations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCli_ApplyServiceAccountAnnotations_Call) Return(err error) *MockCli_ApplyServiceAccountAnnotations_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCli_ApplyServiceAccountAnnotations_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, annotations map[string]string) error) *MockCli_ApplyServiceAccountAnnotations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetServiceAccount provides a mock function for the type MockCli
 func (_mock *MockCli) GetServiceAccount(ctx context.Context, name string, namespace string) (*v1.ServiceAccount, error) {
 	ret := _mock.Called(ctx, name, namespace)
@@ -112,6 +175,69 @@ func (_c *MockCli_GetServiceAccount_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
+// PatchServiceAccount provides a mock function for the type MockCli
+func (_mock *MockCli) PatchServiceAccount(ctx context.Context, sa *v1.ServiceAccount, mutate func(*v1.ServiceAccount)) error {
+	ret := _mock.Called(ctx, sa, mutate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchServiceAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, func(*v1.ServiceAccount)) error); ok {
+		r0 = returnFunc(ctx, sa, mutate)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCli_PatchServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchServiceAccount'
+type MockCli_PatchServiceAccount_Call struct {
+	*mock.Call
+}
+
+// PatchServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+//   - mutate func(*v1.ServiceAccount)
+func (_e *MockCli_Expecter) PatchServiceAccount(ctx interface{}, sa interface{}, mutate interface{}) *MockCli_PatchServiceAccount_Call {
+	return &MockCli_PatchServiceAccount_Call{Call: _e.mock.On("PatchServiceAccount", ctx, sa, mutate)}
+}
+
+func (_c *MockCli_PatchServiceAccount_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, mutate func(*v1.ServiceAccount))) *MockCli_PatchServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		var arg2 func(*v1.ServiceAccount)
+		if args[2] != nil {
+			arg2 = args[2].(func(*v1.ServiceAccount))
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCli_PatchServiceAccount_Call) Return(err error) *MockCli_PatchServiceAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCli_PatchServiceAccount_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, mutate func(*v1.ServiceAccount)) error) *MockCli_PatchServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateServiceAccount provides a mock function for the type MockCli
 func (_mock *MockCli) UpdateServiceAccount(ctx context.Context, sa *v1.ServiceAccount) error {
 	ret := _mock.Called(ctx, sa)
@@ -21,12 +21,48 @@ var (
 			Help: "Number of Pod Identity Associations managed by the operator",
 		},
 	)
+
+	// Number of live shard members when sharding is enabled
+	ShardMembers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pia_operator_shard_members",
+			Help: "Number of operator replicas currently holding a shard membership lease",
+		},
+	)
+
+	// Number of namespaces owned by this replica when sharding is enabled
+	ShardOwnedNamespaces = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pia_operator_shard_owned_namespaces",
+			Help: "Number of namespaces assigned to this operator replica",
+		},
+	)
+
+	// Number of terminating namespaces whose associations could not all be deleted yet
+	NamespaceTerminationsBlocked = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pia_operator_namespace_terminations_blocked",
+			Help: "Number of terminating namespaces blocked by Pod Identity Associations that could not be deleted yet",
+		},
+	)
+
+	// Total terminating namespaces whose finalizers were released after the termination timeout
+	NamespaceTerminationTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pia_operator_namespace_termination_timeouts_total",
+			Help: "Total number of terminating namespaces whose finalizers were released before their Pod Identity Associations were deleted",
+		},
+	)
 )
 
 // RegisterMetrics registers all custom metrics with the given Prometheus registry
 func RegisterMetrics(registry prometheus.Registerer) {
 	registry.MustRegister(PodIdentityAssociationErrors)
 	registry.MustRegister(PodIdentityAssociationsManaged)
+	registry.MustRegister(ShardMembers)
+	registry.MustRegister(ShardOwnedNamespaces)
+	registry.MustRegister(NamespaceTerminationsBlocked)
+	registry.MustRegister(NamespaceTerminationTimeouts)
 }
 
 // IncAssociationError increments the error counter for a given operation
@@ -38,3 +74,23 @@ func IncAssociationError(operation string) {
 func SetAssociationsManaged(count int) {
 	PodIdentityAssociationsManaged.Set(float64(count))
 }
+
+// SetShardMembers sets the gauge for the number of live shard members
+func SetShardMembers(count int) {
+	ShardMembers.Set(float64(count))
+}
+
+// SetShardOwnedNamespaces sets the gauge for the number of namespaces owned by this replica
+func SetShardOwnedNamespaces(count int) {
+	ShardOwnedNamespaces.Set(float64(count))
+}
+
+// SetNamespaceTerminationsBlocked sets the gauge for the number of blocked namespace terminations
+func SetNamespaceTerminationsBlocked(count int) {
+	NamespaceTerminationsBlocked.Set(float64(count))
+}
+
+// IncNamespaceTerminationTimeouts increments the counter of timed out namespace terminations
+func IncNamespaceTerminationTimeouts() {
+	NamespaceTerminationTimeouts.Inc()
+}
@@ -0,0 +1,87 @@
+package podgate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceAccountNameField is the pod field selector used to find the pods of a ServiceAccount
+const ServiceAccountNameField = "spec.serviceAccountName"
+
+// ReadinessGate implements Gate by setting the readiness gate condition of the waiting pods.
+// Pods are listed directly from the API server so the operator does not cache every pod.
+type ReadinessGate struct {
+	reader client.Reader
+	writer client.StatusWriter
+	log    logr.Logger
+}
+
+// NewReadinessGate creates a ReadinessGate
+func NewReadinessGate(reader client.Reader, writer client.StatusWriter, log logr.Logger) *ReadinessGate {
+	return &ReadinessGate{
+		reader: reader,
+		writer: writer,
+		log:    log,
+	}
+}
+
+// OpenGates marks the readiness gate condition as true on every pod of the ServiceAccount that has the gate
+func (g *ReadinessGate) OpenGates(ctx context.Context, sa *corev1.ServiceAccount) error {
+	pods := &corev1.PodList{}
+	if err := g.reader.List(ctx, pods,
+		client.InNamespace(sa.Namespace),
+		client.MatchingFields{ServiceAccountNameField: sa.Name}); err != nil {
+		return fmt.Errorf("failed to list pods of ServiceAccount: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !Waiting(pod) {
+			continue
+		}
+
+		patch := client.StrategicMergeFrom(pod.DeepCopy())
+		setGateCondition(pod)
+		if err := g.writer.Patch(ctx, pod, patch); err != nil {
+			return fmt.Errorf("failed to open readiness gate of pod %s: %w", pod.Name, err)
+		}
+		g.log.Info("Opened readiness gate", "pod", pod.Name, "namespace", pod.Namespace, "serviceaccount", sa.Name)
+	}
+	return nil
+}
+
+// Waiting reports whether the pod has the readiness gate and the gate was not opened yet
+func Waiting(pod *corev1.Pod) bool {
+	return hasReadinessGate(pod) && !gateOpen(pod)
+}
+
+func gateOpen(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == ReadinessGateConditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func setGateCondition(pod *corev1.Pod) {
+	condition := corev1.PodCondition{
+		Type:               ReadinessGateConditionType,
+		Status:             corev1.ConditionTrue,
+		Reason:             "AssociationReady",
+		Message:            "Pod Identity Association of the ServiceAccount is ready",
+		LastTransitionTime: metav1.Now(),
+	}
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == ReadinessGateConditionType {
+			pod.Status.Conditions[i] = condition
+			return
+		}
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, condition)
+}
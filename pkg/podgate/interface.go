@@ -0,0 +1,19 @@
+// Package podgate holds back pods whose ServiceAccount's Pod Identity Association is not ready yet.
+//
+// Pods started before their association exists do not get Pod Identity credentials and fail
+// on their first AWS call. The pod admission Webhook checks whether the association of the pod's
+// ServiceAccount is pending, as recorded by the operator. When it is, the webhook either denies
+// the pod, so that its controller retries the creation later, or adds a readiness gate to the pod,
+// which is opened by a Gate once the operator reports the association ready.
+package podgate
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Gate opens the readiness gates of the pods that wait for a ServiceAccount's association
+type Gate interface {
+	OpenGates(ctx context.Context, sa *corev1.ServiceAccount) error
+}
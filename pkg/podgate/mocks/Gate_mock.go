@@ -0,0 +1,96 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package podgate
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"k8s.io/api/core/v1"
+)
+
+// NewMockGate creates a new instance of MockGate. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGate(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGate {
+	mock := &MockGate{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockGate is an autogenerated mock type for the Gate type
+type MockGate struct {
+	mock.Mock
+}
+
+type MockGate_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGate) EXPECT() *MockGate_Expecter {
+	return &MockGate_Expecter{mock: &_m.Mock}
+}
+
+// OpenGates provides a mock function for the type MockGate
+func (_mock *MockGate) OpenGates(ctx context.Context, sa *v1.ServiceAccount) error {
+	ret := _mock.Called(ctx, sa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenGates")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) error); ok {
+		r0 = returnFunc(ctx, sa)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockGate_OpenGates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OpenGates'
+type MockGate_OpenGates_Call struct {
+	*mock.Call
+}
+
+// OpenGates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+func (_e *MockGate_Expecter) OpenGates(ctx interface{}, sa interface{}) *MockGate_OpenGates_Call {
+	return &MockGate_OpenGates_Call{Call: _e.mock.On("OpenGates", ctx, sa)}
+}
+
+func (_c *MockGate_OpenGates_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount)) *MockGate_OpenGates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGate_OpenGates_Call) Return(err error) *MockGate_OpenGates_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockGate_OpenGates_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount) error) *MockGate_OpenGates_Call {
+	_c.Call.Return(run)
+	return _c
+}
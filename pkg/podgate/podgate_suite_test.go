@@ -0,0 +1,13 @@
+package podgate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPodGate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PodGate Suite")
+}
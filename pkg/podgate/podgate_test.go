@@ -0,0 +1,150 @@
+package podgate_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/irenedo/pia-operator/pkg/podgate"
+)
+
+var _ = Describe("PodGate", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+	)
+
+	serviceAccount := func(name string, annotations map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	Describe("Webhook", func() {
+		var fakeClient client.Client
+
+		pending := func(sa *corev1.ServiceAccount) bool {
+			return sa.Annotations["pia-operator.eks.aws.com/ready"] != "true"
+		}
+
+		request := func(pod *corev1.Pod) admission.Request {
+			raw, err := json.Marshal(pod)
+			Expect(err).ToNot(HaveOccurred())
+			return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: raw},
+			}}
+		}
+
+		pod := func(serviceAccountName string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Containers:         []corev1.Container{{Name: "app", Image: "app"}},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				serviceAccount("pending", map[string]string{"pia-operator.eks.aws.com/role": "arn:aws:iam::123456789012:role/test-role"}),
+				serviceAccount("ready", map[string]string{
+					"pia-operator.eks.aws.com/role":           "arn:aws:iam::123456789012:role/test-role",
+					"pia-operator.eks.aws.com/association-id": "a-12345",
+					"pia-operator.eks.aws.com/ready":          "true",
+				}),
+			).Build()
+		})
+
+		It("should deny pods of pending ServiceAccounts with a retryable error in deny mode", func() {
+			webhook := podgate.NewWebhook(fakeClient, scheme, podgate.ModeDeny, pending, log.Log)
+
+			resp := webhook.Handle(ctx, request(pod("pending")))
+
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(Equal(int32(http.StatusServiceUnavailable)))
+			Expect(resp.Result.Message).To(ContainSubstring("retry later"))
+		})
+
+		It("should add a readiness gate to pods of pending ServiceAccounts in readiness-gate mode", func() {
+			webhook := podgate.NewWebhook(fakeClient, scheme, podgate.ModeReadinessGate, pending, log.Log)
+
+			resp := webhook.Handle(ctx, request(pod("pending")))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(
+				HaveField("Path", "/spec/readinessGates"),
+				HaveField("Path", "/metadata/labels"),
+			))
+		})
+
+		It("should admit pods of ready ServiceAccounts unchanged", func() {
+			webhook := podgate.NewWebhook(fakeClient, scheme, podgate.ModeReadinessGate, pending, log.Log)
+
+			resp := webhook.Handle(ctx, request(pod("ready")))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(BeEmpty())
+		})
+
+		It("should admit pods whose ServiceAccount does not exist", func() {
+			webhook := podgate.NewWebhook(fakeClient, scheme, podgate.ModeDeny, pending, log.Log)
+
+			resp := webhook.Handle(ctx, request(pod("")))
+
+			Expect(resp.Allowed).To(BeTrue())
+		})
+	})
+
+	Describe("ReadinessGate", func() {
+		It("should open the readiness gate of waiting pods of the ServiceAccount", func() {
+			gated := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "gated", Namespace: "default"},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: "app-sa",
+					ReadinessGates:     []corev1.PodReadinessGate{{ConditionType: podgate.ReadinessGateConditionType}},
+				},
+			}
+			ungated := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "ungated", Namespace: "default"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "app-sa"},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(gated, ungated).
+				WithStatusSubresource(&corev1.Pod{}).
+				WithIndex(&corev1.Pod{}, podgate.ServiceAccountNameField, func(obj client.Object) []string {
+					return []string{obj.(*corev1.Pod).Spec.ServiceAccountName}
+				}).Build()
+			gate := podgate.NewReadinessGate(fakeClient, fakeClient.Status(), log.Log)
+
+			Expect(gate.OpenGates(ctx, serviceAccount("app-sa", nil))).To(Succeed())
+
+			updated := &corev1.Pod{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(gated), updated)).To(Succeed())
+			Expect(updated.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", podgate.ReadinessGateConditionType),
+				HaveField("Status", corev1.ConditionTrue),
+			)))
+
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ungated), updated)).To(Succeed())
+			Expect(updated.Status.Conditions).To(BeEmpty())
+		})
+	})
+})
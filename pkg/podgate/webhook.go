@@ -0,0 +1,124 @@
+package podgate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Mode selects what the webhook does with pods whose association is not ready
+type Mode string
+
+const (
+	// ModeDeny rejects the pod with a retryable error
+	ModeDeny Mode = "deny"
+	// ModeReadinessGate admits the pod with a readiness gate that is opened once the association is ready
+	ModeReadinessGate Mode = "readiness-gate"
+
+	// WebhookPath is the path the pod webhook is served on
+	WebhookPath = "/mutate-v1-pod"
+
+	// ReadinessGateConditionType is the pod condition the readiness gate waits for
+	ReadinessGateConditionType corev1.PodConditionType = "pia-operator.eks.aws.com/association-ready"
+
+	// GatedLabel marks the pods admitted with the readiness gate, so that the operator only has to
+	// watch those pods
+	GatedLabel = "pia-operator.eks.aws.com/readiness-gate"
+)
+
+// Webhook is a pod admission webhook that holds back pods until their association is ready.
+// Errors while looking up the ServiceAccount admit the pod unchanged, so that an unavailable
+// operator never blocks pod creation.
+type Webhook struct {
+	reader  client.Reader
+	mode    Mode
+	pending func(*corev1.ServiceAccount) bool
+	decoder *admission.Decoder
+	log     logr.Logger
+}
+
+// NewWebhook creates a pod admission webhook for the given mode. pending reports whether the
+// association of a ServiceAccount is not ready yet, from the annotations the operator records.
+func NewWebhook(reader client.Reader, scheme *runtime.Scheme, mode Mode, pending func(*corev1.ServiceAccount) bool, log logr.Logger) *Webhook {
+	return &Webhook{
+		reader:  reader,
+		mode:    mode,
+		pending: pending,
+		decoder: admission.NewDecoder(scheme),
+		log:     log,
+	}
+}
+
+// Handle admits, denies or gates a pod depending on its ServiceAccount's association readiness
+func (w *Webhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := w.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	namespace := pod.Namespace
+	if namespace == "" {
+		namespace = req.Namespace
+	}
+	name := pod.Spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+	log := w.log.WithValues("namespace", namespace, "serviceaccount", name)
+
+	sa := &corev1.ServiceAccount{}
+	if err := w.reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sa); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "Failed to get ServiceAccount, admitting pod")
+		}
+		return admission.Allowed("")
+	}
+
+	if !w.pending(sa) {
+		return admission.Allowed("")
+	}
+
+	if w.mode == ModeDeny {
+		log.Info("Denying pod until the Pod Identity Association is ready")
+		resp := admission.Denied(fmt.Sprintf(
+			"Pod Identity Association for ServiceAccount %s/%s is not ready yet, retry later", namespace, name))
+		resp.Result.Code = http.StatusServiceUnavailable
+		return resp
+	}
+
+	if hasReadinessGate(pod) {
+		return admission.Allowed("")
+	}
+	original, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: ReadinessGateConditionType})
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[GatedLabel] = "true"
+	gated, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	log.Info("Adding readiness gate until the Pod Identity Association is ready")
+	return admission.PatchResponseFromRaw(original, gated)
+}
+
+func hasReadinessGate(pod *corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == ReadinessGateConditionType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+// Package rollout restarts the workloads that use a ServiceAccount.
+//
+// The EKS Pod Identity agent only injects credentials into pods that are created after
+// the Pod Identity Association exists, so pods that were already running keep their previous
+// identity until they are replaced. A Restarter triggers a rolling restart of the Deployments,
+// StatefulSets and DaemonSets whose pod template uses the ServiceAccount, the same way
+// `kubectl rollout restart` does, by setting an annotation on the pod template.
+package rollout
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Restarter restarts the workloads that run pods with a given ServiceAccount
+type Restarter interface {
+	RestartWorkloads(ctx context.Context, sa *corev1.ServiceAccount, revision string) (Result, error)
+}
+
+// Result reports the progress of a rollout restart
+type Result struct {
+	// Restarted lists the workloads restarted by the call as Kind/name
+	Restarted []string
+	// RequeueAfter is set when the rate limit left workloads to restart. They are restarted by a
+	// later call with the same revision once the delay passed.
+	RequeueAfter time.Duration
+}
+
+// Done reports whether every workload was restarted
+func (r Result) Done() bool {
+	return r.RequeueAfter == 0
+}
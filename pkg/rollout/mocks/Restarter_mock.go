@@ -0,0 +1,112 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package rollout
+
+import (
+	"context"
+
+	"github.com/irenedo/pia-operator/pkg/rollout"
+	mock "github.com/stretchr/testify/mock"
+	"k8s.io/api/core/v1"
+)
+
+// NewMockRestarter creates a new instance of MockRestarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRestarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRestarter {
+	mock := &MockRestarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRestarter is an autogenerated mock type for the Restarter type
+type MockRestarter struct {
+	mock.Mock
+}
+
+type MockRestarter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRestarter) EXPECT() *MockRestarter_Expecter {
+	return &MockRestarter_Expecter{mock: &_m.Mock}
+}
+
+// RestartWorkloads provides a mock function for the type MockRestarter
+func (_mock *MockRestarter) RestartWorkloads(ctx context.Context, sa *v1.ServiceAccount, revision string) (rollout.Result, error) {
+	ret := _mock.Called(ctx, sa, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestartWorkloads")
+	}
+
+	var r0 rollout.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string) (rollout.Result, error)); ok {
+		return returnFunc(ctx, sa, revision)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string) rollout.Result); ok {
+		r0 = returnFunc(ctx, sa, revision)
+	} else {
+		r0 = ret.Get(0).(rollout.Result)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ServiceAccount, string) error); ok {
+		r1 = returnFunc(ctx, sa, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRestarter_RestartWorkloads_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestartWorkloads'
+type MockRestarter_RestartWorkloads_Call struct {
+	*mock.Call
+}
+
+// RestartWorkloads is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+//   - revision string
+func (_e *MockRestarter_Expecter) RestartWorkloads(ctx interface{}, sa interface{}, revision interface{}) *MockRestarter_RestartWorkloads_Call {
+	return &MockRestarter_RestartWorkloads_Call{Call: _e.mock.On("RestartWorkloads", ctx, sa, revision)}
+}
+
+func (_c *MockRestarter_RestartWorkloads_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, revision string)) *MockRestarter_RestartWorkloads_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRestarter_RestartWorkloads_Call) Return(result rollout.Result, err error) *MockRestarter_RestartWorkloads_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockRestarter_RestartWorkloads_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, revision string) (rollout.Result, error)) *MockRestarter_RestartWorkloads_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,160 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RestartedAtAnnotation is set on the pod template to trigger a rolling restart
+	RestartedAtAnnotation = "pia-operator.eks.aws.com/restartedAt"
+	// RestartedForAnnotation records on the pod template the revision the workload was last
+	// restarted for, so that retries skip the workloads that were already restarted
+	RestartedForAnnotation = "pia-operator.eks.aws.com/restartedFor"
+
+	// DefaultMaxRestartsPerMinute limits how many workloads are restarted per minute
+	DefaultMaxRestartsPerMinute = 10
+
+	// ReasonRolloutRestarted is the reason of the events recorded for restarted workloads
+	ReasonRolloutRestarted = "RolloutRestarted"
+)
+
+// WorkloadRestarter implements Restarter. Workloads are listed directly from the API server,
+// so the operator does not have to cache every workload in the cluster, and restarts are spread
+// out by a rate limiter shared by all ServiceAccounts.
+type WorkloadRestarter struct {
+	reader   client.Reader
+	writer   client.Writer
+	recorder record.EventRecorder
+	limiter  *rate.Limiter
+	log      logr.Logger
+}
+
+// NewRestarter creates a WorkloadRestarter that restarts at most maxPerMinute workloads per minute
+func NewRestarter(reader client.Reader, writer client.Writer, recorder record.EventRecorder, maxPerMinute int, log logr.Logger) *WorkloadRestarter {
+	if maxPerMinute <= 0 {
+		maxPerMinute = DefaultMaxRestartsPerMinute
+	}
+	return &WorkloadRestarter{
+		reader:   reader,
+		writer:   writer,
+		recorder: recorder,
+		limiter:  rate.NewLimiter(rate.Every(time.Minute/time.Duration(maxPerMinute)), 1),
+		log:      log,
+	}
+}
+
+// RestartWorkloads restarts every Deployment, StatefulSet and DaemonSet in the ServiceAccount's
+// namespace whose pod template uses the ServiceAccount and was not restarted for revision yet.
+// Restarts are not waited for: once the rate limit is reached the remaining workloads are left
+// for a later call, after the delay in the result. An event is recorded on each restarted
+// workload and on the ServiceAccount.
+func (r *WorkloadRestarter) RestartWorkloads(ctx context.Context, sa *corev1.ServiceAccount, revision string) (Result, error) {
+	log := r.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace)
+
+	workloads, err := r.workloadsUsing(ctx, sa)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	defer func() {
+		if len(result.Restarted) > 0 {
+			r.recorder.Eventf(sa, corev1.EventTypeNormal, ReasonRolloutRestarted,
+				"Restarted %d workloads to pick up the Pod Identity Association: %v", len(result.Restarted), result.Restarted)
+		}
+	}()
+
+	restartedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, workload := range workloads {
+		if workload.template.Annotations[RestartedForAnnotation] == revision {
+			continue
+		}
+
+		reservation := r.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			result.RequeueAfter = delay
+			return result, nil
+		}
+
+		patch := client.MergeFrom(workload.obj.DeepCopyObject().(client.Object))
+		template := workload.template
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string)
+		}
+		template.Annotations[RestartedAtAnnotation] = restartedAt
+		template.Annotations[RestartedForAnnotation] = revision
+		if err := r.writer.Patch(ctx, workload.obj, patch); err != nil {
+			return result, fmt.Errorf("failed to restart %s: %w", workload.name, err)
+		}
+
+		log.Info("Restarted workload after Pod Identity Association change", "workload", workload.name)
+		r.recorder.Eventf(workload.obj, corev1.EventTypeNormal, ReasonRolloutRestarted,
+			"Restarted to pick up the Pod Identity Association of ServiceAccount %s", sa.Name)
+		result.Restarted = append(result.Restarted, workload.name)
+	}
+	return result, nil
+}
+
+// workload is a restartable object together with its pod template
+type workload struct {
+	name     string
+	obj      client.Object
+	template *corev1.PodTemplateSpec
+}
+
+// workloadsUsing lists the workloads in the ServiceAccount's namespace that run pods with it
+func (r *WorkloadRestarter) workloadsUsing(ctx context.Context, sa *corev1.ServiceAccount) ([]workload, error) {
+	inNamespace := client.InNamespace(sa.Namespace)
+	var workloads []workload
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.reader.List(ctx, deployments, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list Deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		workloads = appendIfUsing(workloads, sa, "Deployment", d, &d.Spec.Template)
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.reader.List(ctx, statefulSets, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list StatefulSets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		workloads = appendIfUsing(workloads, sa, "StatefulSet", s, &s.Spec.Template)
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.reader.List(ctx, daemonSets, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list DaemonSets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		workloads = appendIfUsing(workloads, sa, "DaemonSet", d, &d.Spec.Template)
+	}
+
+	return workloads, nil
+}
+
+// appendIfUsing adds the object to the workloads if its pod template runs with the ServiceAccount
+func appendIfUsing(workloads []workload, sa *corev1.ServiceAccount, kind string, obj client.Object, template *corev1.PodTemplateSpec) []workload {
+	name := template.Spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+	if name != sa.Name {
+		return workloads
+	}
+	return append(workloads, workload{name: kind + "/" + obj.GetName(), obj: obj, template: template})
+}
@@ -0,0 +1,146 @@
+package rollout_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/rollout"
+)
+
+var _ = Describe("WorkloadRestarter", func() {
+	const revision = "arn:aws:iam::123456789012:role/test-role"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		recorder   *record.FakeRecorder
+		restarter  *rollout.WorkloadRestarter
+		sa         *corev1.ServiceAccount
+	)
+
+	podTemplate := func(serviceAccountName string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{ServiceAccountName: serviceAccountName}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Template: podTemplate("app-sa")},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Template: podTemplate("other-sa")},
+			},
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec:       appsv1.StatefulSetSpec{Template: podTemplate("app-sa")},
+			},
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "other"},
+				Spec:       appsv1.DaemonSetSpec{Template: podTemplate("app-sa")},
+			},
+		).Build()
+
+		recorder = record.NewFakeRecorder(10)
+		// High enough for every restart to be allowed immediately
+		restarter = rollout.NewRestarter(fakeClient, fakeClient, recorder, math.MaxInt32, log.Log)
+
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "app-sa", Namespace: "default"}}
+	})
+
+	It("should restart the workloads using the ServiceAccount in its namespace", func() {
+		result, err := restarter.RestartWorkloads(ctx, sa, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Restarted).To(ConsistOf("Deployment/web", "StatefulSet/db"))
+		Expect(result.Done()).To(BeTrue())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web"}, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations).To(HaveKey(rollout.RestartedAtAnnotation))
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(rollout.RestartedForAnnotation, revision))
+
+		other := &appsv1.Deployment{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "other"}, other)).To(Succeed())
+		Expect(other.Spec.Template.Annotations).ToNot(HaveKey(rollout.RestartedAtAnnotation))
+
+		daemonSet := &appsv1.DaemonSet{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "other", Name: "agent"}, daemonSet)).To(Succeed())
+		Expect(daemonSet.Spec.Template.Annotations).ToNot(HaveKey(rollout.RestartedAtAnnotation))
+	})
+
+	It("should record an event on each workload and on the ServiceAccount", func() {
+		_, err := restarter.RestartWorkloads(ctx, sa, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(HaveLen(3))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring(rollout.ReasonRolloutRestarted)))
+	})
+
+	It("should treat an empty service account name as the default ServiceAccount", func() {
+		Expect(fakeClient.Create(ctx, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Template: podTemplate("")},
+		})).To(Succeed())
+
+		result, err := restarter.RestartWorkloads(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}}, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Restarted).To(ConsistOf("Deployment/legacy"))
+	})
+
+	It("should not restart anything when no workload uses the ServiceAccount", func() {
+		result, err := restarter.RestartWorkloads(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "default"}}, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Restarted).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should leave the remaining workloads for later once the rate limit is reached", func() {
+		restarter = rollout.NewRestarter(fakeClient, fakeClient, recorder, 1, log.Log)
+
+		first, err := restarter.RestartWorkloads(ctx, sa, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.Restarted).To(HaveLen(1))
+		Expect(first.Done()).To(BeFalse())
+		Expect(first.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+	})
+
+	It("should skip the workloads already restarted for the revision", func() {
+		deployment := &appsv1.Deployment{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web"}, deployment)).To(Succeed())
+		deployment.Spec.Template.Annotations = map[string]string{rollout.RestartedForAnnotation: revision}
+		Expect(fakeClient.Update(ctx, deployment)).To(Succeed())
+
+		result, err := restarter.RestartWorkloads(ctx, sa, revision)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Restarted).To(ConsistOf("StatefulSet/db"))
+
+		result, err = restarter.RestartWorkloads(ctx, sa, "arn:aws:iam::123456789012:role/other-role")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Restarted).To(ConsistOf("Deployment/web", "StatefulSet/db"))
+	})
+})
@@ -0,0 +1,18 @@
+// Package sharding splits reconciliation of ServiceAccounts between several operator replicas.
+//
+// Every replica keeps a coordination.k8s.io Lease renewed while it is running. The set of
+// replicas holding an unexpired Lease forms the shard membership, and each namespace is
+// assigned to exactly one member using rendezvous hashing, so that only the namespaces of a
+// leaving or joining replica move when the membership changes. Replicas reconcile only the
+// ServiceAccounts in the namespaces they own and are notified to rebalance on every change.
+package sharding
+
+import (
+	"context"
+)
+
+// ShardOwner decides which namespaces the local replica is responsible for
+type ShardOwner interface {
+	Owns(namespace string) bool
+	OnRebalance(fn func(ctx context.Context))
+}
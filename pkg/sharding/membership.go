@@ -0,0 +1,275 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metric "github.com/irenedo/pia-operator/pkg/metrics"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ShardGroupLabel marks the Leases that belong to the same shard group
+	ShardGroupLabel = "pia-operator.eks.aws.com/shard-group"
+	// ShardMembersAnnotation publishes on a Lease the members of the ring its holder reconciles with
+	ShardMembersAnnotation = "pia-operator.eks.aws.com/shard-members"
+
+	leaseNamePrefix = "pia-operator-shard-"
+
+	DefaultGroup         = "pia-operator"
+	DefaultLeaseDuration = 30 * time.Second
+	DefaultRenewInterval = 10 * time.Second
+)
+
+// Options configures shard membership
+type Options struct {
+	// Identity uniquely names this replica, usually the pod name
+	Identity string
+	// Namespace holds the membership Leases, usually the operator namespace
+	Namespace string
+	// Group separates independent operator deployments sharing a namespace
+	Group string
+	// LeaseDuration is how long a member is considered alive without renewing its Lease
+	LeaseDuration time.Duration
+	// RenewInterval is how often the Lease is renewed and the membership re-read
+	RenewInterval time.Duration
+}
+
+// Membership maintains this replica's Lease and the current shard ring.
+// It implements manager.Runnable and runs on every replica, regardless of leader election.
+type Membership struct {
+	client client.Client
+	opts   Options
+	log    logr.Logger
+	mu     sync.RWMutex
+	ring   *Ring
+	// peers are the rings the other live members published, keyed by member
+	peers     map[string]*Ring
+	listeners []func(ctx context.Context)
+}
+
+// NewMembership creates a Membership; the client should read directly from the API server
+func NewMembership(c client.Client, opts Options, log logr.Logger) *Membership {
+	if opts.Group == "" {
+		opts.Group = DefaultGroup
+	}
+	if opts.LeaseDuration == 0 {
+		opts.LeaseDuration = DefaultLeaseDuration
+	}
+	if opts.RenewInterval == 0 {
+		opts.RenewInterval = DefaultRenewInterval
+	}
+	return &Membership{
+		client: c,
+		opts:   opts,
+		log:    log.WithValues("shard", opts.Identity),
+		ring:   NewRing(nil),
+	}
+}
+
+// Start renews the Lease and refreshes the membership until the context is cancelled,
+// then deletes the Lease so the remaining replicas take over without waiting for it to expire.
+func (m *Membership) Start(ctx context.Context) error {
+	ticker := time.NewTicker(m.opts.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Sync(ctx); err != nil {
+			m.log.Error(err, "Failed to sync shard membership")
+		}
+		select {
+		case <-ctx.Done():
+			m.leave()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false because every replica must hold a Lease to take part in sharding
+func (m *Membership) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync renews this replica's Lease, reads the live members and rebuilds the ring.
+// Listeners registered with OnRebalance are called when the members change, and when another
+// member adopted a new ring, since namespaces held back by the handoff can be taken over then.
+func (m *Membership) Sync(ctx context.Context) error {
+	if err := m.renew(ctx); err != nil {
+		return err
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := m.client.List(ctx, leases,
+		client.InNamespace(m.opts.Namespace),
+		client.MatchingLabels{ShardGroupLabel: m.opts.Group}); err != nil {
+		return fmt.Errorf("failed to list shard leases: %w", err)
+	}
+
+	now := time.Now()
+	var members []string
+	peers := make(map[string]*Ring)
+	for i := range leases.Items {
+		holder, alive := m.liveHolder(&leases.Items[i], now)
+		if !alive {
+			continue
+		}
+		members = append(members, holder)
+		if published, ok := leases.Items[i].Annotations[ShardMembersAnnotation]; ok && holder != m.opts.Identity {
+			peers[holder] = NewRing(strings.Split(published, ","))
+		}
+	}
+
+	ring := NewRing(members)
+	m.mu.Lock()
+	changed := !m.ring.Equal(ring)
+	handedOff := !equalRings(m.peers, peers)
+	m.ring = ring
+	m.peers = peers
+	listeners := append([]func(context.Context){}, m.listeners...)
+	m.mu.Unlock()
+
+	if changed {
+		m.log.Info("Shard membership changed, rebalancing", "members", ring.Members())
+		metric.SetShardMembers(len(ring.Members()))
+		// Publish the new ring right away, so that other members take over what this one gave up
+		if err := m.renew(ctx); err != nil {
+			return err
+		}
+	}
+	if changed || handedOff {
+		for _, fn := range listeners {
+			fn(ctx)
+		}
+	}
+	return nil
+}
+
+// Owns reports whether this replica is responsible for the namespace.
+// Nothing is owned until the first successful Sync. A namespace that moves here from a member that
+// is still alive is only taken over once that member published a ring in which it no longer owns
+// the namespace, so that the two never reconcile the namespace at the same time. Members whose
+// Lease expired or was released hand their namespaces over right away.
+func (m *Membership) Owns(namespace string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ring.Owner(namespace) != m.opts.Identity {
+		return false
+	}
+	for member, ring := range m.peers {
+		if ring.Owner(namespace) == member {
+			return false
+		}
+	}
+	return true
+}
+
+// Members returns the current live members
+func (m *Membership) Members() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ring.Members()
+}
+
+// OnRebalance registers a function that is called after the membership changes
+func (m *Membership) OnRebalance(fn func(ctx context.Context)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// renew creates or renews the Lease of this replica
+func (m *Membership) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(time.Now())
+	durationSeconds := int32(m.opts.LeaseDuration.Seconds())
+	m.mu.RLock()
+	members := strings.Join(m.ring.Members(), ",")
+	m.mu.RUnlock()
+
+	lease := &coordinationv1.Lease{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: m.opts.Namespace, Name: m.leaseName()}, lease)
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        m.leaseName(),
+				Namespace:   m.opts.Namespace,
+				Labels:      map[string]string{ShardGroupLabel: m.opts.Group},
+				Annotations: map[string]string{ShardMembersAnnotation: members},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.opts.Identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := m.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create shard lease: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get shard lease: %w", err)
+	}
+
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string)
+	}
+	lease.Annotations[ShardMembersAnnotation] = members
+	lease.Spec.HolderIdentity = &m.opts.Identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+	if err := m.client.Update(ctx, lease); err != nil {
+		return fmt.Errorf("failed to renew shard lease: %w", err)
+	}
+	return nil
+}
+
+// liveHolder returns the holder of the Lease and whether it was renewed within its duration
+func (m *Membership) liveHolder(lease *coordinationv1.Lease, now time.Time) (string, bool) {
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil {
+		return "", false
+	}
+	duration := m.opts.LeaseDuration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return *lease.Spec.HolderIdentity, lease.Spec.RenewTime.Add(duration).After(now)
+}
+
+// equalRings reports whether both maps hold the same members with equal rings
+func equalRings(a, b map[string]*Ring) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for member, ring := range a {
+		if !ring.Equal(b[member]) {
+			return false
+		}
+	}
+	return true
+}
+
+// leave deletes this replica's Lease on shutdown
+func (m *Membership) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.opts.RenewInterval)
+	defer cancel()
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: m.leaseName(), Namespace: m.opts.Namespace},
+	}
+	if err := m.client.Delete(ctx, lease); err != nil && !errors.IsNotFound(err) {
+		m.log.Error(err, "Failed to release shard lease")
+	}
+}
+
+func (m *Membership) leaseName() string {
+	return leaseNamePrefix + m.opts.Identity
+}
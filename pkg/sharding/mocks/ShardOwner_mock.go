@@ -0,0 +1,129 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package sharding
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockShardOwner creates a new instance of MockShardOwner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockShardOwner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockShardOwner {
+	mock := &MockShardOwner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockShardOwner is an autogenerated mock type for the ShardOwner type
+type MockShardOwner struct {
+	mock.Mock
+}
+
+type MockShardOwner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockShardOwner) EXPECT() *MockShardOwner_Expecter {
+	return &MockShardOwner_Expecter{mock: &_m.Mock}
+}
+
+// OnRebalance provides a mock function for the type MockShardOwner
+func (_mock *MockShardOwner) OnRebalance(fn func(ctx context.Context)) {
+	_mock.Called(fn)
+	return
+}
+
+// MockShardOwner_OnRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnRebalance'
+type MockShardOwner_OnRebalance_Call struct {
+	*mock.Call
+}
+
+// OnRebalance is a helper method to define mock.On call
+//   - fn func(ctx context.Context)
+func (_e *MockShardOwner_Expecter) OnRebalance(fn interface{}) *MockShardOwner_OnRebalance_Call {
+	return &MockShardOwner_OnRebalance_Call{Call: _e.mock.On("OnRebalance", fn)}
+}
+
+func (_c *MockShardOwner_OnRebalance_Call) Run(run func(fn func(ctx context.Context))) *MockShardOwner_OnRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(ctx context.Context)
+		if args[0] != nil {
+			arg0 = args[0].(func(ctx context.Context))
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockShardOwner_OnRebalance_Call) Return() *MockShardOwner_OnRebalance_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockShardOwner_OnRebalance_Call) RunAndReturn(run func(fn func(ctx context.Context))) *MockShardOwner_OnRebalance_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Owns provides a mock function for the type MockShardOwner
+func (_mock *MockShardOwner) Owns(namespace string) bool {
+	ret := _mock.Called(namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Owns")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = returnFunc(namespace)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockShardOwner_Owns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Owns'
+type MockShardOwner_Owns_Call struct {
+	*mock.Call
+}
+
+// Owns is a helper method to define mock.On call
+//   - namespace string
+func (_e *MockShardOwner_Expecter) Owns(namespace interface{}) *MockShardOwner_Owns_Call {
+	return &MockShardOwner_Owns_Call{Call: _e.mock.On("Owns", namespace)}
+}
+
+func (_c *MockShardOwner_Owns_Call) Run(run func(namespace string)) *MockShardOwner_Owns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockShardOwner_Owns_Call) Return(b bool) *MockShardOwner_Owns_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockShardOwner_Owns_Call) RunAndReturn(run func(namespace string) bool) *MockShardOwner_Owns_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,66 @@
+package sharding
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Ring assigns namespaces to shard members using rendezvous (highest random weight) hashing
+type Ring struct {
+	members []string
+}
+
+// NewRing creates a Ring for the given members; duplicates are ignored
+func NewRing(members []string) *Ring {
+	seen := make(map[string]bool, len(members))
+	unique := make([]string, 0, len(members))
+	for _, m := range members {
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		unique = append(unique, m)
+	}
+	sort.Strings(unique)
+	return &Ring{members: unique}
+}
+
+// Members returns the sorted list of members in the ring
+func (r *Ring) Members() []string {
+	return append([]string(nil), r.members...)
+}
+
+// Owner returns the member responsible for the namespace, or an empty string if the ring is empty
+func (r *Ring) Owner(namespace string) string {
+	var owner string
+	var best uint64
+	for _, member := range r.members {
+		weight := hash(member, namespace)
+		if owner == "" || weight > best {
+			owner = member
+			best = weight
+		}
+	}
+	return owner
+}
+
+// Equal reports whether both rings have the same members
+func (r *Ring) Equal(other *Ring) bool {
+	if other == nil || len(r.members) != len(other.members) {
+		return false
+	}
+	for i := range r.members {
+		if r.members[i] != other.members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(member, namespace string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(namespace))
+	return h.Sum64()
+}
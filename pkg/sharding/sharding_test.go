@@ -0,0 +1,183 @@
+package sharding_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/sharding"
+)
+
+var _ = Describe("Ring", func() {
+	It("should not assign namespaces when empty", func() {
+		Expect(sharding.NewRing(nil).Owner("default")).To(BeEmpty())
+	})
+
+	It("should ignore duplicated and empty members", func() {
+		ring := sharding.NewRing([]string{"b", "a", "", "b"})
+		Expect(ring.Members()).To(Equal([]string{"a", "b"}))
+	})
+
+	It("should assign every namespace to a member regardless of member order", func() {
+		first := sharding.NewRing([]string{"a", "b", "c"})
+		second := sharding.NewRing([]string{"c", "a", "b"})
+
+		for i := 0; i < 100; i++ {
+			ns := fmt.Sprintf("ns-%d", i)
+			Expect(first.Owner(ns)).To(BeElementOf("a", "b", "c"))
+			Expect(first.Owner(ns)).To(Equal(second.Owner(ns)))
+		}
+	})
+
+	It("should only move the namespaces of a leaving member", func() {
+		before := sharding.NewRing([]string{"a", "b", "c"})
+		after := sharding.NewRing([]string{"a", "b"})
+
+		for i := 0; i < 100; i++ {
+			ns := fmt.Sprintf("ns-%d", i)
+			if before.Owner(ns) != "c" {
+				Expect(after.Owner(ns)).To(Equal(before.Owner(ns)))
+			}
+		}
+	})
+
+	It("should compare members", func() {
+		Expect(sharding.NewRing([]string{"a", "b"}).Equal(sharding.NewRing([]string{"b", "a"}))).To(BeTrue())
+		Expect(sharding.NewRing([]string{"a"}).Equal(sharding.NewRing([]string{"a", "b"}))).To(BeFalse())
+	})
+})
+
+var _ = Describe("Membership", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		opts       sharding.Options
+	)
+
+	lease := func(holder string, renewed time.Time) *coordinationv1.Lease {
+		duration := int32(30)
+		renewTime := metav1.NewMicroTime(renewed)
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pia-operator-shard-" + holder,
+				Namespace: "pia-system",
+				Labels:    map[string]string{sharding.ShardGroupLabel: sharding.DefaultGroup},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &duration,
+				RenewTime:            &renewTime,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(coordinationv1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			lease("replica-b", time.Now()),
+			lease("replica-c", time.Now().Add(-time.Hour)),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		).Build()
+
+		opts = sharding.Options{Identity: "replica-a", Namespace: "pia-system"}
+	})
+
+	It("should not own any namespace before the first sync", func() {
+		membership := sharding.NewMembership(fakeClient, opts, log.Log)
+
+		Expect(membership.Owns("default")).To(BeFalse())
+	})
+
+	It("should create its lease and ignore expired members", func() {
+		membership := sharding.NewMembership(fakeClient, opts, log.Log)
+
+		Expect(membership.Sync(ctx)).To(Succeed())
+
+		own := &coordinationv1.Lease{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "pia-system", Name: "pia-operator-shard-replica-a"}, own)).To(Succeed())
+		Expect(*own.Spec.HolderIdentity).To(Equal("replica-a"))
+		Expect(membership.Members()).To(Equal([]string{"replica-a", "replica-b"}))
+
+		ring := sharding.NewRing([]string{"replica-a", "replica-b"})
+		Expect(membership.Owns("default")).To(Equal(ring.Owner("default") == "replica-a"))
+	})
+
+	It("should notify listeners only when the members change", func() {
+		membership := sharding.NewMembership(fakeClient, opts, log.Log)
+		rebalances := 0
+		membership.OnRebalance(func(context.Context) { rebalances++ })
+
+		Expect(membership.Sync(ctx)).To(Succeed())
+		Expect(membership.Sync(ctx)).To(Succeed())
+		Expect(rebalances).To(Equal(1))
+
+		Expect(fakeClient.Delete(ctx, lease("replica-b", time.Now()))).To(Succeed())
+		Expect(membership.Sync(ctx)).To(Succeed())
+		Expect(rebalances).To(Equal(2))
+		Expect(membership.Owns("default")).To(BeTrue())
+	})
+
+	It("should take over a namespace only once its previous owner published the new ring", func() {
+		// replica-b has not seen replica-a join yet and still owns every namespace
+		b := &coordinationv1.Lease{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "pia-system", Name: "pia-operator-shard-replica-b"}, b)).To(Succeed())
+		b.Annotations = map[string]string{sharding.ShardMembersAnnotation: "replica-b"}
+		Expect(fakeClient.Update(ctx, b)).To(Succeed())
+
+		ring := sharding.NewRing([]string{"replica-a", "replica-b"})
+		namespace := "ns-0"
+		for i := 1; ring.Owner(namespace) != "replica-a"; i++ {
+			namespace = fmt.Sprintf("ns-%d", i)
+		}
+
+		membership := sharding.NewMembership(fakeClient, opts, log.Log)
+		rebalances := 0
+		membership.OnRebalance(func(context.Context) { rebalances++ })
+		Expect(membership.Sync(ctx)).To(Succeed())
+		Expect(membership.Owns(namespace)).To(BeFalse())
+
+		own := &coordinationv1.Lease{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "pia-system", Name: "pia-operator-shard-replica-a"}, own)).To(Succeed())
+		Expect(own.Annotations).To(HaveKeyWithValue(sharding.ShardMembersAnnotation, "replica-a,replica-b"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "pia-system", Name: "pia-operator-shard-replica-b"}, b)).To(Succeed())
+		b.Annotations[sharding.ShardMembersAnnotation] = "replica-a,replica-b"
+		Expect(fakeClient.Update(ctx, b)).To(Succeed())
+		Expect(membership.Sync(ctx)).To(Succeed())
+
+		Expect(membership.Owns(namespace)).To(BeTrue())
+		Expect(rebalances).To(Equal(2))
+	})
+
+	It("should release its lease when stopped", func() {
+		opts.RenewInterval = time.Second
+		membership := sharding.NewMembership(fakeClient, opts, log.Log)
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() { done <- membership.Start(runCtx) }()
+
+		Eventually(func() []string { return membership.Members() }).Should(ContainElement("replica-a"))
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+
+		own := &coordinationv1.Lease{}
+		err := fakeClient.Get(ctx, client.ObjectKey{Namespace: "pia-system", Name: "pia-operator-shard-replica-a"}, own)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,51 @@
+// Package trustpolicy maintains the trust policy of the target roles used by assume-role chains.
+//
+// With an assume-role annotation, EKS Pod Identity first assumes the base role and then the
+// target role, which is usually in another account and has to trust the base role. A Manager
+// adds a statement to the target role's trust policy that allows the base role to assume it and
+// tag the session, conditioned on the session tags identifying the cluster and ServiceAccount,
+// and removes the ServiceAccount from it again when it stops using the target role. The
+// ServiceAccounts trusted through the same base role share a single statement, listing their
+// namespaces and names, so that trust policies stay within the IAM size quota. Session tags
+// cannot be matched in pairs, so the statement allows every listed name in every listed namespace.
+//
+// Trust policies of other accounts are changed with credentials obtained by assuming an access
+// role in the target account.
+package trustpolicy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// ErrAccessDenied is returned when the operator is not allowed to read or change the trust policy
+	ErrAccessDenied = errors.New("access denied")
+	// ErrReadOnly is returned when the trust policy of the target role cannot be changed, such as for service-linked roles
+	ErrReadOnly = errors.New("trust policy is read-only")
+	// ErrRejected is returned when IAM rejects the updated trust policy, such as when it exceeds the size quota
+	ErrRejected = errors.New("trust policy rejected")
+)
+
+// Subject is a ServiceAccount that still uses a target role through a base role
+type Subject struct {
+	Namespace      string
+	Name           string
+	RoleArn        string
+	TaggingEnabled bool
+}
+
+// Manager grants and revokes the trust of target roles in base roles
+type Manager interface {
+	Grant(ctx context.Context, sa *corev1.ServiceAccount, roleArn, targetRoleArn string, taggingEnabled bool) error
+	Revoke(ctx context.Context, sa *corev1.ServiceAccount, targetRoleArn string, keep []Subject) error
+}
+
+// IAMAPI is the subset of the IAM API used by the Manager
+type IAMAPI interface {
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	UpdateAssumeRolePolicy(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)
+}
@@ -0,0 +1,412 @@
+package trustpolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/go-logr/logr"
+	"github.com/irenedo/pia-operator/pkg/arn"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// statementIDPrefix marks the trust policy statements owned by the operator
+	statementIDPrefix = "PiaOperator"
+
+	// Session tags set by EKS Pod Identity that identify the ServiceAccount
+	clusterNameTag    = "eks-cluster-name"
+	namespaceTag      = "kubernetes-namespace"
+	serviceAccountTag = "kubernetes-service-account"
+)
+
+// Options configures the Manager
+type Options struct {
+	// ClusterName is required in the session tags of the trusted base role
+	ClusterName string
+	// AccessRoleName is the role assumed in the account of each target role to change its trust policy.
+	// When empty, the operator's own credentials are used, which only works for roles in its own account.
+	AccessRoleName string
+}
+
+// APIFactory returns an IAM API acting in the given account
+type APIFactory func(ctx context.Context, accountID string) (IAMAPI, error)
+
+// TrustManager implements Manager on top of the IAM API of each target account
+type TrustManager struct {
+	newAPI APIFactory
+	opts   Options
+	log    logr.Logger
+
+	mu   sync.Mutex
+	apis map[string]IAMAPI
+}
+
+// NewManager creates a Manager that assumes the access role of each target account using
+// the default AWS credentials chain
+func NewManager(ctx context.Context, region string, opts Options, log logr.Logger) (Manager, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	stsClient := sts.NewFromConfig(cfg)
+	return NewManagerWithAPI(func(ctx context.Context, accountID string) (IAMAPI, error) {
+		if opts.AccessRoleName == "" {
+			return iam.NewFromConfig(cfg), nil
+		}
+		accountCfg := cfg.Copy()
+		accessRoleArn := arn.RoleARN(arn.PartitionForRegion(region), accountID, opts.AccessRoleName)
+		accountCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, accessRoleArn, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "pia-operator-trust-policy"
+		}))
+		return iam.NewFromConfig(accountCfg), nil
+	}, opts, log), nil
+}
+
+// NewManagerWithAPI creates a Manager using the IAM APIs returned by newAPI
+func NewManagerWithAPI(newAPI APIFactory, opts Options, log logr.Logger) *TrustManager {
+	return &TrustManager{
+		newAPI: newAPI,
+		opts:   opts,
+		log:    log,
+		apis:   make(map[string]IAMAPI),
+	}
+}
+
+// Grant allows the base role to assume the target role on behalf of the ServiceAccount. All
+// ServiceAccounts trusted through the same base role share one statement, whose session tag
+// conditions list their namespaces and names. ServiceAccounts without session tags share a
+// statement without conditions.
+func (m *TrustManager) Grant(ctx context.Context, sa *corev1.ServiceAccount, roleArn, targetRoleArn string, taggingEnabled bool) error {
+	sid := m.statementID(roleArn, taggingEnabled)
+	return m.updateTrustPolicy(ctx, sa, targetRoleArn, func(statements []interface{}) []interface{} {
+		statements = removeStatement(statements, m.legacyStatementID(sa.Namespace, sa.Name))
+		index := findStatement(statements, sid)
+		if !taggingEnabled {
+			return putStatement(statements, index, m.statement(sid, roleArn, nil, nil))
+		}
+
+		var namespaces, names []string
+		if index >= 0 {
+			namespaces, names = subjectTags(statements[index])
+		}
+		return putStatement(statements, index, m.statement(sid, roleArn,
+			addValue(namespaces, sa.Namespace), addValue(names, sa.Name)))
+	})
+}
+
+// Revoke removes the ServiceAccount from the statements added by Grant. The namespace and name
+// of the ServiceAccount stay in a statement while a ServiceAccount in keep still needs them, and
+// statements left without subjects are removed. Target roles that no longer exist are ignored.
+func (m *TrustManager) Revoke(ctx context.Context, sa *corev1.ServiceAccount, targetRoleArn string, keep []Subject) error {
+	return m.updateTrustPolicy(ctx, sa, targetRoleArn, func(statements []interface{}) []interface{} {
+		statements = removeStatement(statements, m.legacyStatementID(sa.Namespace, sa.Name))
+
+		var updated []interface{}
+		for _, statement := range statements {
+			roleArn, taggingEnabled, ok := m.grantedStatement(statement)
+			if !ok {
+				updated = append(updated, statement)
+				continue
+			}
+
+			var kept []Subject
+			for _, subject := range keep {
+				if subject.RoleArn == roleArn && subject.TaggingEnabled == taggingEnabled {
+					kept = append(kept, subject)
+				}
+			}
+			if !taggingEnabled {
+				if len(kept) > 0 {
+					updated = append(updated, statement)
+				}
+				continue
+			}
+
+			namespaces, names := subjectTags(statement)
+			if !keepsValue(kept, func(s Subject) string { return s.Namespace }, sa.Namespace) {
+				namespaces = removeValue(namespaces, sa.Namespace)
+			}
+			if !keepsValue(kept, func(s Subject) string { return s.Name }, sa.Name) {
+				names = removeValue(names, sa.Name)
+			}
+			if len(namespaces) == 0 || len(names) == 0 {
+				continue
+			}
+			updated = append(updated, m.statement(m.statementID(roleArn, true), roleArn, namespaces, names))
+		}
+		return updated
+	})
+}
+
+// updateTrustPolicy applies change to the statements of the target role's trust policy and
+// writes the policy back if it differs
+func (m *TrustManager) updateTrustPolicy(ctx context.Context, sa *corev1.ServiceAccount, targetRoleArn string, change func([]interface{}) []interface{}) error {
+	parsed, err := arn.ParseRole(targetRoleArn)
+	if err != nil {
+		return fmt.Errorf("invalid target role ARN %q: %w", targetRoleArn, err)
+	}
+	roleName := parsed.Name
+	log := m.log.WithValues("serviceaccount", sa.Name, "namespace", sa.Namespace, "targetRoleArn", targetRoleArn)
+
+	api, err := m.api(ctx, parsed.AccountID)
+	if err != nil {
+		return err
+	}
+
+	out, err := api.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		if errorCode(err) == "NoSuchEntity" {
+			log.Info("Target role does not exist, not changing its trust policy")
+			return nil
+		}
+		return m.wrapError(err, "read", targetRoleArn)
+	}
+
+	document, err := url.QueryUnescape(aws.ToString(out.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return fmt.Errorf("failed to decode trust policy of %s: %w", targetRoleArn, err)
+	}
+	policy := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return fmt.Errorf("failed to parse trust policy of %s: %w", targetRoleArn, err)
+	}
+
+	statements := policyStatements(policy)
+	updated := change(append([]interface{}(nil), statements...))
+	if reflect.DeepEqual(statements, updated) {
+		return nil
+	}
+	if len(updated) == 0 {
+		// IAM rejects trust policies without statements
+		log.Info("Not removing the only statement of the trust policy of the target role")
+		return nil
+	}
+	policy["Statement"] = updated
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to encode trust policy of %s: %w", targetRoleArn, err)
+	}
+	if _, err := api.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyDocument: aws.String(string(data)),
+	}); err != nil {
+		return m.wrapError(err, "update", targetRoleArn)
+	}
+
+	log.Info("Updated trust policy of target role")
+	return nil
+}
+
+// api returns the IAM API of the account, creating it on first use
+func (m *TrustManager) api(ctx context.Context, accountID string) (IAMAPI, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if api, ok := m.apis[accountID]; ok {
+		return api, nil
+	}
+	api, err := m.newAPI(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM client for account %s: %w", accountID, err)
+	}
+	m.apis[accountID] = api
+	return api, nil
+}
+
+// wrapError maps IAM and STS errors that retrying cannot fix to ErrAccessDenied, ErrReadOnly and ErrRejected
+func (m *TrustManager) wrapError(err error, operation, targetRoleArn string) error {
+	switch errorCode(err) {
+	case "AccessDenied", "AccessDeniedException":
+		return fmt.Errorf("%w: cannot %s the trust policy of %s, check the permissions of the access role %q: %v",
+			ErrAccessDenied, operation, targetRoleArn, m.opts.AccessRoleName, err)
+	case "UnmodifiableEntity":
+		return fmt.Errorf("%w: the trust policy of %s cannot be changed: %v", ErrReadOnly, targetRoleArn, err)
+	case "LimitExceeded", "MalformedPolicyDocument":
+		return fmt.Errorf("%w: IAM did not accept the trust policy of %s: %v", ErrRejected, targetRoleArn, err)
+	default:
+		return fmt.Errorf("failed to %s the trust policy of %s: %w", operation, targetRoleArn, err)
+	}
+}
+
+// statementID identifies the statement shared by the ServiceAccounts trusted through the base role.
+// Sids may only contain letters and digits.
+func (m *TrustManager) statementID(roleArn string, taggingEnabled bool) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%t", m.opts.ClusterName, roleArn, taggingEnabled)))
+	return statementIDPrefix + hex.EncodeToString(sum[:])[:16]
+}
+
+// legacyStatementID identifies the statement earlier versions added for every ServiceAccount.
+// Grant and Revoke remove it, so that trust policies move to the shared statements.
+func (m *TrustManager) legacyStatementID(namespace, name string) string {
+	sum := sha256.Sum256([]byte(m.opts.ClusterName + "/" + namespace + "/" + name))
+	return statementIDPrefix + hex.EncodeToString(sum[:])[:16]
+}
+
+// statement builds the statement trusting the base role. Without subjects, the statement has no
+// session tag conditions.
+func (m *TrustManager) statement(sid, roleArn string, namespaces, names []string) map[string]interface{} {
+	statement := map[string]interface{}{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]interface{}{"AWS": roleArn},
+		"Action":    []interface{}{"sts:AssumeRole", "sts:TagSession"},
+	}
+	if len(namespaces) > 0 {
+		statement["Condition"] = map[string]interface{}{
+			"StringEquals": map[string]interface{}{
+				"aws:RequestTag/" + clusterNameTag:    m.opts.ClusterName,
+				"aws:RequestTag/" + namespaceTag:      toInterfaces(namespaces),
+				"aws:RequestTag/" + serviceAccountTag: toInterfaces(names),
+			},
+		}
+	}
+	return statement
+}
+
+// grantedStatement returns the base role of a statement added by Grant, and whether it has session
+// tag conditions. Statements of other tools and of earlier versions are not reported.
+func (m *TrustManager) grantedStatement(statement interface{}) (string, bool, bool) {
+	s, ok := statement.(map[string]interface{})
+	if !ok {
+		return "", false, false
+	}
+	principal, _ := s["Principal"].(map[string]interface{})
+	roleArn, _ := principal["AWS"].(string)
+	for _, taggingEnabled := range []bool{true, false} {
+		if roleArn != "" && s["Sid"] == m.statementID(roleArn, taggingEnabled) {
+			return roleArn, taggingEnabled, true
+		}
+	}
+	return "", false, false
+}
+
+// subjectTags returns the namespaces and names a statement's session tag conditions allow
+func subjectTags(statement interface{}) ([]string, []string) {
+	s, _ := statement.(map[string]interface{})
+	condition, _ := s["Condition"].(map[string]interface{})
+	equals, _ := condition["StringEquals"].(map[string]interface{})
+	return stringValues(equals["aws:RequestTag/"+namespaceTag]), stringValues(equals["aws:RequestTag/"+serviceAccountTag])
+}
+
+// stringValues reads a condition value, which may hold a single string
+func stringValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// addValue adds the value to a condition list, kept sorted so that the policy only changes with its subjects
+func addValue(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	values = append(values, value)
+	sort.Strings(values)
+	return values
+}
+
+func removeValue(values []string, value string) []string {
+	var kept []string
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// keepsValue reports whether a subject still needs the value of a condition list
+func keepsValue(subjects []Subject, field func(Subject) string, value string) bool {
+	for _, subject := range subjects {
+		if field(subject) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toInterfaces(values []string) []interface{} {
+	items := make([]interface{}, len(values))
+	for i, v := range values {
+		items[i] = v
+	}
+	return items
+}
+
+// findStatement returns the index of the statement with the Sid, or -1
+func findStatement(statements []interface{}, sid string) int {
+	for i, statement := range statements {
+		if s, ok := statement.(map[string]interface{}); ok && s["Sid"] == sid {
+			return i
+		}
+	}
+	return -1
+}
+
+// putStatement replaces the statement at index in place, or appends it when index is -1, so that
+// the order of the statements does not change between reconciles
+func putStatement(statements []interface{}, index int, statement map[string]interface{}) []interface{} {
+	if index < 0 {
+		return append(statements, statement)
+	}
+	statements[index] = statement
+	return statements
+}
+
+// policyStatements returns the statements of a policy, which may hold a single statement object
+func policyStatements(policy map[string]interface{}) []interface{} {
+	switch statement := policy["Statement"].(type) {
+	case []interface{}:
+		return statement
+	case nil:
+		return nil
+	default:
+		return []interface{}{statement}
+	}
+}
+
+func removeStatement(statements []interface{}, sid string) []interface{} {
+	kept := statements[:0]
+	for _, statement := range statements {
+		if s, ok := statement.(map[string]interface{}); ok && s["Sid"] == sid {
+			continue
+		}
+		kept = append(kept, statement)
+	}
+	return kept
+}
+
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
@@ -0,0 +1,234 @@
+package trustpolicy_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
+	trustmocks "github.com/irenedo/pia-operator/pkg/trustpolicy/mocks"
+)
+
+// legacySid returns the statement ID suffix earlier versions used for every ServiceAccount
+func legacySid(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+var _ = Describe("TrustManager", func() {
+	const (
+		baseRole   = "arn:aws:iam::111111111111:role/base-role"
+		targetRole = "arn:aws:iam::222222222222:role/path/target-role"
+		existing   = `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}}`
+	)
+
+	var (
+		ctx      context.Context
+		mockIAM  *trustmocks.MockIAMAPI
+		accounts []string
+		manager  *trustpolicy.TrustManager
+		sa       *corev1.ServiceAccount
+	)
+
+	role := func(document string) *iam.GetRoleOutput {
+		return &iam.GetRoleOutput{Role: &types.Role{
+			RoleName:                 aws.String("target-role"),
+			AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+		}}
+	}
+
+	statements := func(document string) []map[string]interface{} {
+		var policy struct {
+			Statement []map[string]interface{}
+		}
+		Expect(json.Unmarshal([]byte(document), &policy)).To(Succeed())
+		return policy.Statement
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockIAM = trustmocks.NewMockIAMAPI(GinkgoT())
+		accounts = nil
+		manager = trustpolicy.NewManagerWithAPI(func(_ context.Context, accountID string) (trustpolicy.IAMAPI, error) {
+			accounts = append(accounts, accountID)
+			return mockIAM, nil
+		}, trustpolicy.Options{ClusterName: "my-cluster", AccessRoleName: "pia-operator-access"}, log.Log)
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	})
+
+	Describe("Grant", func() {
+		It("should add a statement trusting the base role with session tag conditions", func() {
+			var updated string
+			mockIAM.On("GetRole", ctx, &iam.GetRoleInput{RoleName: aws.String("target-role")}).Return(role(existing), nil)
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				updated = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+
+			Expect(accounts).To(Equal([]string{"222222222222"}))
+			granted := statements(updated)
+			Expect(granted).To(HaveLen(2))
+			Expect(granted[1]).To(HaveKeyWithValue("Principal", map[string]interface{}{"AWS": baseRole}))
+			Expect(granted[1]).To(HaveKeyWithValue("Action", []interface{}{"sts:AssumeRole", "sts:TagSession"}))
+			Expect(granted[1]["Condition"]).To(HaveKeyWithValue("StringEquals", map[string]interface{}{
+				"aws:RequestTag/eks-cluster-name":           "my-cluster",
+				"aws:RequestTag/kubernetes-namespace":       []interface{}{"default"},
+				"aws:RequestTag/kubernetes-service-account": []interface{}{"app"},
+			}))
+		})
+
+		It("should share the statement of the base role and keep its position", func() {
+			var updated string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil).Once()
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				updated = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil).Times(2)
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+
+			// Another tool appends a statement after the one of the operator
+			var policy map[string]interface{}
+			Expect(json.Unmarshal([]byte(updated), &policy)).To(Succeed())
+			policy["Statement"] = append(policy["Statement"].([]interface{}), map[string]interface{}{
+				"Effect": "Allow", "Principal": map[string]interface{}{"Service": "lambda.amazonaws.com"}, "Action": "sts:AssumeRole",
+			})
+			document, err := json.Marshal(policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			other := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "jobs"}}
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(string(document)), nil).Once()
+			Expect(manager.Grant(ctx, other, baseRole, targetRole, true)).To(Succeed())
+
+			granted := statements(updated)
+			Expect(granted).To(HaveLen(3))
+			Expect(granted[1]["Condition"]).To(HaveKeyWithValue("StringEquals", map[string]interface{}{
+				"aws:RequestTag/eks-cluster-name":           "my-cluster",
+				"aws:RequestTag/kubernetes-namespace":       []interface{}{"default", "jobs"},
+				"aws:RequestTag/kubernetes-service-account": []interface{}{"app", "worker"},
+			}))
+			Expect(granted[2]).To(HaveKeyWithValue("Principal", map[string]interface{}{"Service": "lambda.amazonaws.com"}))
+
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(updated), nil).Once()
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+			mockIAM.AssertNumberOfCalls(GinkgoT(), "UpdateAssumeRolePolicy", 2)
+		})
+
+		It("should replace the statement of earlier versions", func() {
+			legacy := `{"Version":"2012-10-17","Statement":[{"Sid":"PiaOperator` + legacySid("my-cluster/default/app") +
+				`","Effect":"Allow","Principal":{"AWS":"` + baseRole + `"},"Action":["sts:AssumeRole","sts:TagSession"]}]}`
+			var updated string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(legacy), nil)
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				updated = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+
+			granted := statements(updated)
+			Expect(granted).To(HaveLen(1))
+			Expect(granted[0]["Sid"]).NotTo(ContainSubstring(legacySid("my-cluster/default/app")))
+		})
+
+		It("should not write the policy when the statement is already present", func() {
+			var updated string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil).Once()
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				updated = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil).Once()
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, false)).To(Succeed())
+
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(updated), nil).Once()
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, false)).To(Succeed())
+
+			Expect(accounts).To(HaveLen(1))
+			mockIAM.AssertNumberOfCalls(GinkgoT(), "UpdateAssumeRolePolicy", 1)
+		})
+
+		It("should report denied access", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"})
+
+			err := manager.Grant(ctx, sa, baseRole, targetRole, true)
+
+			Expect(err).To(MatchError(trustpolicy.ErrAccessDenied))
+			Expect(err).To(MatchError(ContainSubstring("pia-operator-access")))
+		})
+
+		It("should report read-only trust policies", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil)
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "UnmodifiableEntity"})
+
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(MatchError(trustpolicy.ErrReadOnly))
+		})
+
+		It("should report trust policies IAM rejects", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil)
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "LimitExceeded"})
+
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(MatchError(trustpolicy.ErrRejected))
+		})
+	})
+
+	Describe("Revoke", func() {
+		It("should remove only the statement of the ServiceAccount", func() {
+			var granted string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil).Once()
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				granted = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil).Once()
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+
+			var revoked string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(granted), nil).Once()
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				revoked = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil).Once()
+			Expect(manager.Revoke(ctx, sa, targetRole, nil)).To(Succeed())
+
+			Expect(statements(revoked)).To(ConsistOf(HaveKeyWithValue("Principal", map[string]interface{}{"Service": "ec2.amazonaws.com"})))
+		})
+
+		It("should keep the namespace and name other ServiceAccounts still use", func() {
+			var granted string
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(existing), nil).Once()
+			mockIAM.On("UpdateAssumeRolePolicy", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				granted = aws.ToString(args.Get(1).(*iam.UpdateAssumeRolePolicyInput).PolicyDocument)
+			}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil).Times(3)
+			Expect(manager.Grant(ctx, sa, baseRole, targetRole, true)).To(Succeed())
+			other := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}}
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(granted), nil).Once()
+			Expect(manager.Grant(ctx, other, baseRole, targetRole, true)).To(Succeed())
+
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(role(granted), nil).Once()
+			Expect(manager.Revoke(ctx, sa, targetRole, []trustpolicy.Subject{
+				{Namespace: "default", Name: "worker", RoleArn: baseRole, TaggingEnabled: true},
+			})).To(Succeed())
+
+			revoked := statements(granted)
+			Expect(revoked).To(HaveLen(2))
+			Expect(revoked[1]["Condition"]).To(HaveKeyWithValue("StringEquals", map[string]interface{}{
+				"aws:RequestTag/eks-cluster-name":           "my-cluster",
+				"aws:RequestTag/kubernetes-namespace":       []interface{}{"default"},
+				"aws:RequestTag/kubernetes-service-account": []interface{}{"worker"},
+			}))
+		})
+
+		It("should ignore target roles that no longer exist", func() {
+			mockIAM.On("GetRole", ctx, mock.Anything).Return(nil, &types.NoSuchEntityException{Message: aws.String("gone")})
+
+			Expect(manager.Revoke(ctx, sa, targetRole, nil)).To(Succeed())
+		})
+	})
+})
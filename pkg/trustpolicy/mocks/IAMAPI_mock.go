@@ -0,0 +1,205 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package trustpolicy
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIAMAPI creates a new instance of MockIAMAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAMAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAMAPI {
+	mock := &MockIAMAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIAMAPI is an autogenerated mock type for the IAMAPI type
+type MockIAMAPI struct {
+	mock.Mock
+}
+
+type MockIAMAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAMAPI) EXPECT() *MockIAMAPI_Expecter {
+	return &MockIAMAPI_Expecter{mock: &_m.Mock}
+}
+
+// GetRole provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRole")
+	}
+
+	var r0 *iam.GetRoleOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) (*iam.GetRoleOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) *iam.GetRoleOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.GetRoleOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_GetRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRole'
+type MockIAMAPI_GetRole_Call struct {
+	*mock.Call
+}
+
+// GetRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.GetRoleInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) GetRole(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_GetRole_Call {
+	return &MockIAMAPI_GetRole_Call{Call: _e.mock.On("GetRole",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_GetRole_Call) Run(run func(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options))) *MockIAMAPI_GetRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.GetRoleInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.GetRoleInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRole_Call) Return(getRoleOutput *iam.GetRoleOutput, err error) *MockIAMAPI_GetRole_Call {
+	_c.Call.Return(getRoleOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_GetRole_Call) RunAndReturn(run func(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)) *MockIAMAPI_GetRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAssumeRolePolicy provides a mock function for the type MockIAMAPI
+func (_mock *MockIAMAPI) UpdateAssumeRolePolicy(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAssumeRolePolicy")
+	}
+
+	var r0 *iam.UpdateAssumeRolePolicyOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) *iam.UpdateAssumeRolePolicyOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.UpdateAssumeRolePolicyOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *iam.UpdateAssumeRolePolicyInput, ...func(*iam.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAMAPI_UpdateAssumeRolePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAssumeRolePolicy'
+type MockIAMAPI_UpdateAssumeRolePolicy_Call struct {
+	*mock.Call
+}
+
+// UpdateAssumeRolePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *iam.UpdateAssumeRolePolicyInput
+//   - optFns ...func(*iam.Options)
+func (_e *MockIAMAPI_Expecter) UpdateAssumeRolePolicy(ctx interface{}, params interface{}, optFns ...interface{}) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	return &MockIAMAPI_UpdateAssumeRolePolicy_Call{Call: _e.mock.On("UpdateAssumeRolePolicy",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) Run(run func(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options))) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *iam.UpdateAssumeRolePolicyInput
+		if args[1] != nil {
+			arg1 = args[1].(*iam.UpdateAssumeRolePolicyInput)
+		}
+		var arg2 []func(*iam.Options)
+		var variadicArgs []func(*iam.Options)
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*iam.Options))
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) Return(updateAssumeRolePolicyOutput *iam.UpdateAssumeRolePolicyOutput, err error) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Return(updateAssumeRolePolicyOutput, err)
+	return _c
+}
+
+func (_c *MockIAMAPI_UpdateAssumeRolePolicy_Call) RunAndReturn(run func(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)) *MockIAMAPI_UpdateAssumeRolePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,184 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package trustpolicy
+
+import (
+	"context"
+
+	"github.com/irenedo/pia-operator/pkg/trustpolicy"
+	mock "github.com/stretchr/testify/mock"
+	"k8s.io/api/core/v1"
+)
+
+// NewMockManager creates a new instance of MockManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockManager {
+	mock := &MockManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockManager is an autogenerated mock type for the Manager type
+type MockManager struct {
+	mock.Mock
+}
+
+type MockManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockManager) EXPECT() *MockManager_Expecter {
+	return &MockManager_Expecter{mock: &_m.Mock}
+}
+
+// Grant provides a mock function for the type MockManager
+func (_mock *MockManager) Grant(ctx context.Context, sa *v1.ServiceAccount, roleArn string, targetRoleArn string, taggingEnabled bool) error {
+	ret := _mock.Called(ctx, sa, roleArn, targetRoleArn, taggingEnabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Grant")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, string, bool) error); ok {
+		r0 = returnFunc(ctx, sa, roleArn, targetRoleArn, taggingEnabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockManager_Grant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Grant'
+type MockManager_Grant_Call struct {
+	*mock.Call
+}
+
+// Grant is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+//   - roleArn string
+//   - targetRoleArn string
+//   - taggingEnabled bool
+func (_e *MockManager_Expecter) Grant(ctx interface{}, sa interface{}, roleArn interface{}, targetRoleArn interface{}, taggingEnabled interface{}) *MockManager_Grant_Call {
+	return &MockManager_Grant_Call{Call: _e.mock.On("Grant", ctx, sa, roleArn, targetRoleArn, taggingEnabled)}
+}
+
+func (_c *MockManager_Grant_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, targetRoleArn string, taggingEnabled bool)) *MockManager_Grant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 bool
+		if args[4] != nil {
+			arg4 = args[4].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManager_Grant_Call) Return(err error) *MockManager_Grant_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockManager_Grant_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, roleArn string, targetRoleArn string, taggingEnabled bool) error) *MockManager_Grant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function for the type MockManager
+func (_mock *MockManager) Revoke(ctx context.Context, sa *v1.ServiceAccount, targetRoleArn string, keep []trustpolicy.Subject) error {
+	ret := _mock.Called(ctx, sa, targetRoleArn, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount, string, []trustpolicy.Subject) error); ok {
+		r0 = returnFunc(ctx, sa, targetRoleArn, keep)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockManager_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockManager_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sa *v1.ServiceAccount
+//   - targetRoleArn string
+//   - keep []trustpolicy.Subject
+func (_e *MockManager_Expecter) Revoke(ctx interface{}, sa interface{}, targetRoleArn interface{}, keep interface{}) *MockManager_Revoke_Call {
+	return &MockManager_Revoke_Call{Call: _e.mock.On("Revoke", ctx, sa, targetRoleArn, keep)}
+}
+
+func (_c *MockManager_Revoke_Call) Run(run func(ctx context.Context, sa *v1.ServiceAccount, targetRoleArn string, keep []trustpolicy.Subject)) *MockManager_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*v1.ServiceAccount)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []trustpolicy.Subject
+		if args[3] != nil {
+			arg3 = args[3].([]trustpolicy.Subject)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManager_Revoke_Call) Return(err error) *MockManager_Revoke_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockManager_Revoke_Call) RunAndReturn(run func(ctx context.Context, sa *v1.ServiceAccount, targetRoleArn string, keep []trustpolicy.Subject) error) *MockManager_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
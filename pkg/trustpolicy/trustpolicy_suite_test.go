@@ -0,0 +1,13 @@
+package trustpolicy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTrustPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TrustPolicy Suite")
+}
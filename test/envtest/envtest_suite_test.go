@@ -0,0 +1,107 @@
+package envtest_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/irenedo/pia-operator/internal/controller"
+	"github.com/irenedo/pia-operator/pkg/awsclient"
+	pkgerrors "github.com/irenedo/pia-operator/pkg/errors"
+	"github.com/irenedo/pia-operator/pkg/fakeeks"
+	"github.com/irenedo/pia-operator/pkg/k8sclient"
+)
+
+const (
+	clusterName = "envtest-cluster"
+
+	// scopeLabel must be set on namespaces for the operator to handle their ServiceAccounts
+	scopeLabel = "pia-operator.eks.aws.com/envtest"
+)
+
+var (
+	ctx       context.Context
+	cancel    context.CancelFunc
+	testEnv   *envtest.Environment
+	k8sClient client.Client
+	eksServer *fakeeks.Server
+)
+
+func TestEnvtest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Envtest Suite")
+}
+
+var _ = BeforeSuite(func() {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		Skip("KUBEBUILDER_ASSETS is not set, run the envtest suite with `task test-envtest`")
+	}
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+	ctx, cancel = context.WithCancel(context.Background())
+
+	testEnv = &envtest.Environment{}
+	cfg, err := testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).ToNot(HaveOccurred())
+
+	scope, err := controller.NewScope(nil, scopeLabel+"=true", "")
+	Expect(err).ToNot(HaveOccurred())
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		Cache:   scope.CacheOptions(),
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	eksServer = fakeeks.NewServer(clusterName)
+	log := ctrl.Log.WithName("envtest")
+
+	errorHandler := pkgerrors.NewErrorHandler(mgr.GetClient(), log.WithName("errors"))
+	errorHandler.SetRetryPolicy(pkgerrors.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    time.Second,
+	})
+
+	reconciler := &controller.ServiceAccountReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Log:                     log.WithName("controller"),
+		AWSRegion:               fakeeks.Region,
+		ClusterName:             clusterName,
+		AWSClient:               awsclient.NewClientWithAPI(eksServer.Client(), clusterName, fakeeks.Region, log.WithName("awsclient")),
+		K8sClient:               k8sclient.NewClient(mgr.GetClient()),
+		ErrorHandler:            errorHandler,
+		MaxConcurrentReconciles: 2,
+		Scope:                   scope,
+	}
+	Expect(reconciler.SetupWithManager(mgr)).To(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if testEnv == nil {
+		return
+	}
+	cancel()
+	eksServer.Close()
+	Expect(testEnv.Stop()).To(Succeed())
+})
@@ -0,0 +1,237 @@
+package envtest_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/irenedo/pia-operator/internal/controller"
+	"github.com/irenedo/pia-operator/pkg/fakeeks"
+)
+
+const (
+	timeout  = 10 * time.Second
+	interval = 50 * time.Millisecond
+
+	roleArn      = "arn:aws:iam::123456789012:role/app"
+	otherRoleArn = "arn:aws:iam::123456789012:role/other"
+)
+
+var namespaceCount int
+
+var _ = Describe("ServiceAccount controller", func() {
+	var namespace string
+
+	// newNamespace creates a namespace, inside the operator's scope unless inScope is false
+	newNamespace := func(inScope bool) string {
+		namespaceCount++
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("envtest-%d", namespaceCount),
+			Labels: map[string]string{scopeLabel: fmt.Sprint(inScope)},
+		}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		return ns.Name
+	}
+
+	newServiceAccount := func(name string, annotations map[string]string) *corev1.ServiceAccount {
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		}}
+		Expect(k8sClient.Create(ctx, sa)).To(Succeed())
+		return sa
+	}
+
+	get := func(sa *corev1.ServiceAccount) func(Gomega) *corev1.ServiceAccount {
+		return func(g Gomega) *corev1.ServiceAccount {
+			current := &corev1.ServiceAccount{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(sa), current)).To(Succeed())
+			return current
+		}
+	}
+
+	// update applies mutate to the latest version of the ServiceAccount, retrying on conflicts
+	update := func(sa *corev1.ServiceAccount, mutate func(*corev1.ServiceAccount)) {
+		Expect(retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current := &corev1.ServiceAccount{}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(sa), current); err != nil {
+				return err
+			}
+			mutate(current)
+			return k8sClient.Update(ctx, current)
+		})).To(Succeed())
+	}
+
+	associationOf := func(sa *corev1.ServiceAccount) func() []fakeeks.Association {
+		return func() []fakeeks.Association {
+			var associations []fakeeks.Association
+			for _, a := range eksServer.Associations(clusterName) {
+				if a.Namespace == sa.Namespace && a.ServiceAccount == sa.Name {
+					associations = append(associations, a)
+				}
+			}
+			return associations
+		}
+	}
+
+	withRole := func(role string) OmegaMatcher {
+		return ContainElement(HaveField("RoleArn", role))
+	}
+
+	// ready waits until the association of the ServiceAccount is ready and returns its ID
+	ready := func(sa *corev1.ServiceAccount) string {
+		Eventually(get(sa), timeout, interval).Should(HaveField("ObjectMeta.Annotations",
+			HaveKeyWithValue(controller.PodIdentityAssociationReadyAnnotation, "true")))
+		current := get(sa)(Default)
+		Expect(controllerutil.ContainsFinalizer(current, controller.PodIdentityAssociationFinalizer)).To(BeTrue())
+		return current.Annotations[controller.PodIdentityAssociationIDAnnotation]
+	}
+
+	BeforeEach(func() {
+		namespace = newNamespace(true)
+	})
+
+	It("should create, update and delete the association of an annotated ServiceAccount", func() {
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+
+		associationID := ready(sa)
+		Expect(associationOf(sa)()).To(ConsistOf(HaveField("ID", associationID)))
+		Expect(associationOf(sa)()).To(withRole(roleArn))
+
+		update(sa, func(sa *corev1.ServiceAccount) {
+			sa.Annotations[controller.PodIdentityAssociationRoleAnnotation] = otherRoleArn
+		})
+		Eventually(associationOf(sa), timeout, interval).Should(withRole(otherRoleArn))
+		Expect(associationOf(sa)()).To(ConsistOf(HaveField("ID", associationID)))
+
+		Expect(k8sClient.Delete(ctx, sa)).To(Succeed())
+		Eventually(associationOf(sa), timeout, interval).Should(BeEmpty())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{}))
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("should clean up when the role annotation is removed", func() {
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+		ready(sa)
+
+		update(sa, func(sa *corev1.ServiceAccount) {
+			delete(sa.Annotations, controller.PodIdentityAssociationRoleAnnotation)
+		})
+
+		Eventually(associationOf(sa), timeout, interval).Should(BeEmpty())
+		Eventually(get(sa), timeout, interval).Should(SatisfyAll(
+			HaveField("ObjectMeta.Finalizers", BeEmpty()),
+			HaveField("ObjectMeta.Annotations", Not(HaveKey(controller.PodIdentityAssociationIDAnnotation))),
+		))
+	})
+
+	It("should ignore ServiceAccounts without annotations", func() {
+		creates := eksServer.Calls(fakeeks.OperationCreate)
+		sa := newServiceAccount("plain", nil)
+
+		Consistently(get(sa), time.Second, interval).Should(HaveField("ObjectMeta.Finalizers", BeEmpty()))
+		Expect(eksServer.Calls(fakeeks.OperationCreate)).To(Equal(creates))
+	})
+
+	It("should pick up ServiceAccounts of namespaces entering the scope", func() {
+		namespace = newNamespace(false)
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+
+		Consistently(associationOf(sa), time.Second, interval).Should(BeEmpty())
+
+		ns := &corev1.Namespace{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, ns)).To(Succeed())
+		ns.Labels[scopeLabel] = "true"
+		Expect(k8sClient.Update(ctx, ns)).To(Succeed())
+
+		ready(sa)
+		Expect(associationOf(sa)()).To(withRole(roleArn))
+	})
+
+	It("should adopt an association that exists without an ID annotation", func() {
+		existingID := eksServer.Put(fakeeks.Association{
+			ClusterName:    clusterName,
+			Namespace:      namespace,
+			ServiceAccount: "app",
+			RoleArn:        otherRoleArn,
+		})
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+
+		Expect(ready(sa)).To(Equal(existingID))
+		Expect(associationOf(sa)()).To(SatisfyAll(HaveLen(1), withRole(roleArn)))
+	})
+
+	It("should retry throttled and failing EKS calls", func() {
+		eksServer.Throttle(fakeeks.OperationList, 2)
+		eksServer.InjectFault(fakeeks.OperationCreate, fakeeks.Fault{Code: "ServerException", Message: "internal failure", Times: 1})
+		DeferCleanup(eksServer.ClearFaults)
+
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+
+		ready(sa)
+		Expect(associationOf(sa)()).To(HaveLen(1))
+	})
+
+	It("should converge while the ServiceAccount is modified concurrently", func() {
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+		for i := 0; i < 5; i++ {
+			update(sa, func(sa *corev1.ServiceAccount) {
+				if sa.Labels == nil {
+					sa.Labels = map[string]string{}
+				}
+				sa.Labels["revision"] = fmt.Sprint(i)
+			})
+		}
+
+		ready(sa)
+		Expect(associationOf(sa)()).To(SatisfyAll(HaveLen(1), withRole(roleArn)))
+		Expect(get(sa)(Default).Labels).To(HaveKeyWithValue("revision", "4"))
+	})
+
+	It("should delete the association of a ServiceAccount deleted without its finalizer", func() {
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+		ready(sa)
+
+		update(sa, func(sa *corev1.ServiceAccount) {
+			controllerutil.RemoveFinalizer(sa, controller.PodIdentityAssociationFinalizer)
+		})
+		Expect(k8sClient.Delete(ctx, sa)).To(Succeed())
+
+		Eventually(associationOf(sa), timeout, interval).Should(BeEmpty())
+	})
+
+	It("should delete the association when the annotation is removed during deletion", func() {
+		sa := newServiceAccount("app", map[string]string{controller.PodIdentityAssociationRoleAnnotation: roleArn})
+		ready(sa)
+
+		// Keep the first deletion attempt from completing so the annotation is removed while the ServiceAccount is deleted
+		eksServer.InjectFault(fakeeks.OperationDelete, fakeeks.Fault{Code: "ServerException", Message: "internal failure", Times: 1})
+		DeferCleanup(eksServer.ClearFaults)
+
+		Expect(k8sClient.Delete(ctx, sa)).To(Succeed())
+		// The retried deletion may already have finished
+		Expect(client.IgnoreNotFound(retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current := &corev1.ServiceAccount{}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(sa), current); err != nil {
+				return err
+			}
+			delete(current.Annotations, controller.PodIdentityAssociationRoleAnnotation)
+			return k8sClient.Update(ctx, current)
+		}))).To(Succeed())
+
+		Eventually(associationOf(sa), timeout, interval).Should(BeEmpty())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{}))
+		}, timeout, interval).Should(BeTrue())
+	})
+})